@@ -0,0 +1,377 @@
+// common/artist_merge.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the detection of near-duplicate artists in djmdArtist and the preparation
+// of their merge into one canonical artist.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"MetaRekordFixer/locales"
+)
+
+// DefaultArtistSimilarity is the default minimum similarity of two normalized artist names
+// for them to be grouped as likely duplicates.
+const DefaultArtistSimilarity = 0.9
+
+// ArtistRef is an artist of a duplicate group with the number of rows referencing it.
+type ArtistRef struct {
+	ID   string
+	Name string
+	Uses int // Number of tracks and albums referencing the artist
+}
+
+// ArtistDuplicateGroup holds artists whose names are likely variants of the same name.
+type ArtistDuplicateGroup struct {
+	Artists []ArtistRef // Members of the group, the most used artist first
+}
+
+// Canonical returns the suggested canonical artist of the group: the most used one.
+func (g ArtistDuplicateGroup) Canonical() ArtistRef {
+	return g.Artists[0]
+}
+
+// featuringPattern lists the spellings of "featuring" which are unified by NormalizeArtistName.
+var featuringPattern = []string{"featuring", "feat.", "feat", "ft.", "ft"}
+
+// diacriticsReplacer replaces letters with diacritics by their base letters.
+var diacriticsReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a", "ą", "a", "ă", "a",
+	"č", "c", "ç", "c", "ć", "c",
+	"ď", "d", "đ", "d",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "ě", "e", "ę", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ľ", "l", "ĺ", "l", "ł", "l",
+	"ň", "n", "ñ", "n", "ń", "n",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o", "ő", "o",
+	"ř", "r", "ŕ", "r",
+	"š", "s", "ś", "s", "ş", "s", "ß", "ss",
+	"ť", "t", "ţ", "t",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u", "ů", "u", "ű", "u",
+	"ý", "y", "ÿ", "y",
+	"ž", "z", "ź", "z", "ż", "z",
+	"æ", "ae", "œ", "oe",
+)
+
+// NormalizeArtistName converts an artist name to a form in which spelling variants of the same name are equal:
+// lower case without diacritics and punctuation, single spaces, "feat."/"ft." unified to "feat"
+// and "and" unified to "&".
+//
+// Parameters:
+//   - name: The artist name
+//
+// Returns:
+//   - The normalized name
+func NormalizeArtistName(name string) string {
+	name = diacriticsReplacer.Replace(strings.ToLower(name))
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ','
+	})
+	normalized := make([]string, 0, len(words))
+	for _, word := range words {
+		for _, feat := range featuringPattern {
+			if word == feat {
+				word = "feat"
+				break
+			}
+		}
+		if word == "and" || word == "+" {
+			word = "&"
+		}
+		// Remaining punctuation (dots, quotes, brackets) does not distinguish artists
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '&' {
+				return r
+			}
+			return -1
+		}, word)
+		if word != "" {
+			normalized = append(normalized, word)
+		}
+	}
+	return strings.Join(normalized, " ")
+}
+
+// ArtistNameSimilarity returns the similarity of two normalized artist names between 0 and 1,
+// based on the Levenshtein distance relative to the length of the longer name.
+func ArtistNameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longer := len(ra)
+	if len(rb) > longer {
+		longer = len(rb)
+	}
+	if longer == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longer)
+}
+
+// levenshtein returns the edit distance of two rune slices.
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// FindArtistDuplicates groups the artists of djmdArtist whose normalized names are equal
+// or at least as similar as the given threshold. Only groups with two or more artists are returned.
+//
+// Parameters:
+//   - dbMgr: Database manager of the database
+//   - threshold: Minimum similarity (0-1) of normalized names, 1 groups equal normalized names only
+//   - isCancelled: Optional function reporting whether the user stopped the run
+//
+// Returns:
+//   - The duplicate groups sorted by the name of their canonical artist
+//   - ErrCancelled if the run was cancelled, or an error if a query fails
+func FindArtistDuplicates(dbMgr *DBManager, threshold float64, isCancelled func() bool) ([]ArtistDuplicateGroup, error) {
+	schema, err := InspectSchema(dbMgr)
+	if err != nil {
+		return nil, err
+	}
+
+	uses, err := countArtistUses(dbMgr, schema)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+	}
+
+	rows, err := dbMgr.Query("SELECT ID, COALESCE(Name, '') FROM djmdArtist")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+	}
+	var artists []ArtistRef
+	var keys []string
+	for rows.Next() {
+		var artist ArtistRef
+		if err := rows.Scan(&artist.ID, &artist.Name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+		}
+		key := NormalizeArtistName(artist.Name)
+		if key == "" {
+			continue
+		}
+		artist.Uses = uses[artist.ID]
+		artists = append(artists, artist)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+	}
+	rows.Close()
+
+	// Union-find over the artists, artists with equal normalized names are joined first
+	parent := make([]int, len(artists))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[rj] = ri
+		}
+	}
+
+	byKey := make(map[string]int)
+	for i, key := range keys {
+		if first, ok := byKey[key]; ok {
+			union(first, i)
+		} else {
+			byKey[key] = i
+		}
+	}
+
+	// Similar names are only compared within the same first letter, which keeps the comparison fast
+	// for large libraries and catches typos, missing spaces and punctuation variants
+	if threshold < 1 {
+		distinct := make([]string, 0, len(byKey))
+		for key := range byKey {
+			distinct = append(distinct, key)
+		}
+		sort.Strings(distinct)
+		for i, a := range distinct {
+			if isCancelled != nil && isCancelled() {
+				return nil, ErrCancelled
+			}
+			for _, b := range distinct[i+1:] {
+				if []rune(a)[0] != []rune(b)[0] {
+					break
+				}
+				if ArtistNameSimilarity(a, b) >= threshold {
+					union(byKey[a], byKey[b])
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]ArtistRef)
+	for i, artist := range artists {
+		root := find(i)
+		members[root] = append(members[root], artist)
+	}
+
+	var groups []ArtistDuplicateGroup
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			if group[i].Uses != group[j].Uses {
+				return group[i].Uses > group[j].Uses
+			}
+			return group[i].Name < group[j].Name
+		})
+		groups = append(groups, ArtistDuplicateGroup{Artists: group})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return strings.ToLower(groups[i].Canonical().Name) < strings.ToLower(groups[j].Canonical().Name)
+	})
+
+	return groups, nil
+}
+
+// artistReferences returns the table and column pairs referencing djmdArtist which exist in the inspected schema.
+// A missing column is logged, its references would otherwise be left pointing to merged artists.
+func artistReferences(dbMgr *DBManager, schema *SchemaReport) [][2]string {
+	var references [][2]string
+	add := func(table, column string) {
+		if !schema.HasColumn(table, column) {
+			dbMgr.logger.Warning("Artist reference column %s.%s not found in database schema, skipping it", table, column)
+			return
+		}
+		references = append(references, [2]string{table, column})
+	}
+	for _, column := range orphanArtistColumns {
+		add(SQLTableDJMDContent, column)
+	}
+	add(SQLTableDJMDAlbum, "AlbumArtistID")
+	return references
+}
+
+// countArtistUses returns the number of tracks and albums referencing each artist.
+func countArtistUses(dbMgr *DBManager, schema *SchemaReport) (map[string]int, error) {
+	uses := make(map[string]int)
+	for _, reference := range artistReferences(dbMgr, schema) {
+		rows, err := dbMgr.Query(fmt.Sprintf("SELECT %s, COUNT(*) FROM %s WHERE %s IS NOT NULL GROUP BY %s",
+			reference[1], reference[0], reference[1], reference[1]))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			var count int
+			if err := rows.Scan(&id, &count); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			uses[id] += count
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return uses, nil
+}
+
+// PrepareArtistMerge records the merge of duplicate artists into a canonical artist in the changeset:
+// all track and album references to the duplicates are repointed to the canonical artist,
+// the duplicates are deleted and the canonical artist is renamed if a different name is given.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - canonicalID: The ID of the artist which is kept
+//   - canonicalName: The name of the kept artist, empty keeps its current name
+//   - duplicates: The artists merged into the canonical artist
+//
+// Returns:
+//   - The number of repointed references
+//   - An error if the references cannot be read
+func PrepareArtistMerge(cs *Changeset, canonicalID string, canonicalName string, duplicates []ArtistRef) (int, error) {
+	dbMgr := cs.DB()
+
+	schema, err := InspectSchema(dbMgr)
+	if err != nil {
+		return 0, err
+	}
+
+	if canonicalName != "" {
+		if _, err := cs.Update(SQLTableDJMDArtist, canonicalID, canonicalName, FieldChange{Column: "Name", New: canonicalName}); err != nil {
+			return 0, err
+		}
+	}
+
+	references := artistReferences(dbMgr, schema)
+	repointed := 0
+	for _, duplicate := range duplicates {
+		if duplicate.ID == canonicalID {
+			continue
+		}
+		for _, reference := range references {
+			table, column := reference[0], reference[1]
+			labelColumn := "COALESCE(Title, '')"
+			if table == SQLTableDJMDAlbum {
+				labelColumn = "COALESCE(Name, '')"
+			}
+			rows, err := dbMgr.Query(fmt.Sprintf("SELECT ID, %s FROM %s WHERE %s = ?", labelColumn, table, column), duplicate.ID)
+			if err != nil {
+				return repointed, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+			}
+			type referencingRow struct{ id, label string }
+			var found []referencingRow
+			for rows.Next() {
+				var ref referencingRow
+				if err := rows.Scan(&ref.id, &ref.label); err != nil {
+					rows.Close()
+					return repointed, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+				}
+				found = append(found, ref)
+			}
+			err = rows.Err()
+			rows.Close()
+			if err != nil {
+				return repointed, fmt.Errorf("%s: %w", locales.Translate("common.err.artistread"), err)
+			}
+
+			for _, ref := range found {
+				if cs.UpdateKnown(table, "ID", ref.id, ref.label, FieldChange{Column: column, Old: duplicate.ID, New: canonicalID}) {
+					repointed++
+				}
+			}
+		}
+
+		dbMgr.logger.Info("%s %s", fmt.Sprintf(locales.Translate("common.log.artist"), duplicate.Name),
+			fmt.Sprintf(locales.Translate("common.log.artistmerged"), canonicalID))
+		cs.Delete(SQLTableDJMDArtist, duplicate.ID, duplicate.Name)
+	}
+
+	return repointed, nil
+}
@@ -0,0 +1,304 @@
+// common/artwork.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the import of artwork embedded in audio files into the rekordbox share folder.
+
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png" // Embedded pictures are JPEG or PNG
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dhowden/tag"
+
+	"MetaRekordFixer/locales"
+)
+
+// flacPictureFrontCover is the picture type of a front cover in FLAC PICTURE blocks and ID3v2 APIC frames.
+const flacPictureFrontCover = 3
+
+// id3PictureFrontCover is the picture type name of a front cover reported by the tag library.
+const id3PictureFrontCover = "Cover (front)"
+
+// artworkJPEGQuality is the quality of the generated artwork images.
+const artworkJPEGQuality = 90
+
+// artworkImages lists the images rekordbox expects in the folder of an artwork,
+// with their edge length in pixels: the small one for lists, the medium one for the track detail.
+var artworkImages = []struct {
+	name string
+	size int
+}{
+	{"artwork.jpg", 80},
+	{"artwork_m.jpg", 240},
+}
+
+// ArtworkFile describes an artwork to create for a track after its ImagePath was written.
+type ArtworkFile struct {
+	Source    string // Path to the audio file with the embedded picture
+	ImagePath string // ImagePath of the track, relative to the share folder
+}
+
+// ArtworkShareDir returns the share folder of a rekordbox database, which holds the artwork of its tracks.
+//
+// Parameters:
+//   - dbPath: The path to master.db
+//
+// Returns:
+//   - The path to the share folder next to the database
+func ArtworkShareDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "share")
+}
+
+// newArtworkImagePath returns the ImagePath of a new artwork in the layout rekordbox uses,
+// "/PIONEER/Artwork/<first 3 characters of the UUID>/<UUID>/artwork.jpg".
+func newArtworkImagePath() string {
+	uuid := NewUUID()
+	return path.Join("/PIONEER/Artwork", uuid[:3], uuid, artworkImages[0].name)
+}
+
+// artworkExists reports whether the image of an ImagePath exists in the share folder.
+func artworkExists(shareDir string, imagePath string) bool {
+	if imagePath == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(shareDir, filepath.FromSlash(strings.TrimPrefix(imagePath, "/"))))
+	return err == nil
+}
+
+// ReadEmbeddedArtwork reads the picture embedded in an audio file. FLAC files are read from their
+// PICTURE blocks, all other formats from their ID3v2 APIC frames or MP4 cover atom.
+// A front cover is preferred over other pictures.
+//
+// Parameters:
+//   - filePath: The path to the audio file
+//
+// Returns:
+//   - The picture data, nil if the file has no embedded picture
+//   - An error if the tags of the file cannot be read
+func ReadEmbeddedArtwork(filePath string) ([]byte, error) {
+	format := AudioFormatFromPath(filePath)
+	if format == AudioFormatFLAC {
+		blocks, _, err := readFlacMetadata(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artworkread"), err)
+		}
+		var picture []byte
+		for _, block := range blocks {
+			if block.kind != flacBlockPicture {
+				continue
+			}
+			kind, data, ok := parseFlacPicture(block.data)
+			if !ok {
+				continue
+			}
+			if kind == flacPictureFrontCover {
+				return data, nil
+			}
+			if picture == nil {
+				picture = data
+			}
+		}
+		return picture, nil
+	}
+
+	rawTags, err := readRawTags(filePath, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.artworkread"), err)
+	}
+	var picture []byte
+	for _, container := range []TagContainer{TagContainerID3v2, TagContainerMP4} {
+		raw := rawTags[container]
+		keys := make([]string, 0, len(raw))
+		for key := range raw {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			p, ok := raw[key].(*tag.Picture)
+			if !ok || len(p.Data) == 0 {
+				continue
+			}
+			if p.Type == id3PictureFrontCover {
+				return p.Data, nil
+			}
+			if picture == nil {
+				picture = p.Data
+			}
+		}
+	}
+	return picture, nil
+}
+
+// parseFlacPicture returns the picture type and the picture data of a FLAC PICTURE block.
+func parseFlacPicture(data []byte) (uint32, []byte, bool) {
+	offset := 0
+	next := func() (uint32, bool) {
+		if offset+4 > len(data) {
+			return 0, false
+		}
+		value := binary.BigEndian.Uint32(data[offset:])
+		offset += 4
+		return value, true
+	}
+
+	kind, ok := next()
+	if !ok {
+		return 0, nil, false
+	}
+	// MIME type and description precede the picture
+	for range 2 {
+		length, ok := next()
+		if !ok || uint64(offset)+uint64(length) > uint64(len(data)) {
+			return 0, nil, false
+		}
+		offset += int(length)
+	}
+	// Width, height, color depth and number of colors are not needed
+	offset += 16
+	length, ok := next()
+	if !ok || uint64(offset)+uint64(length) > uint64(len(data)) || length == 0 {
+		return 0, nil, false
+	}
+	return kind, data[offset : offset+int(length)], true
+}
+
+// embeddedArtworkImages renders the picture embedded in an audio file into the images rekordbox expects,
+// cropped to a square and scaled to the sizes of artworkImages.
+//
+// Parameters:
+//   - filePath: The path to the audio file
+//
+// Returns:
+//   - The encoded images in the order of artworkImages, nil if the file has no picture in a supported image format
+//   - An error if the tags of the file cannot be read
+func embeddedArtworkImages(filePath string) ([][]byte, error) {
+	data, err := ReadEmbeddedArtwork(filePath)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	picture, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil
+	}
+
+	images := make([][]byte, 0, len(artworkImages))
+	for _, artwork := range artworkImages {
+		var buffer bytes.Buffer
+		if err := jpeg.Encode(&buffer, scaleSquare(picture, artwork.size), &jpeg.Options{Quality: artworkJPEGQuality}); err != nil {
+			return nil, err
+		}
+		images = append(images, buffer.Bytes())
+	}
+	return images, nil
+}
+
+// artworkMatches reports whether the folder of an ImagePath holds exactly the given images,
+// i.e. the artwork was created from the same picture before.
+func artworkMatches(shareDir string, imagePath string, images [][]byte) bool {
+	if imagePath == "" {
+		return false
+	}
+	folder := filepath.Join(shareDir, filepath.FromSlash(path.Dir(strings.TrimPrefix(imagePath, "/"))))
+	for i, artwork := range artworkImages {
+		existing, err := os.ReadFile(filepath.Join(folder, artwork.name))
+		if err != nil || !bytes.Equal(existing, images[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteArtworkFiles creates the artwork images of tracks in the share folder. The pictures are
+// read again from the audio files, cropped to a square and scaled to the sizes rekordbox expects.
+// A file which cannot be created does not stop the others; its error is returned with the others.
+//
+// Parameters:
+//   - shareDir: The share folder of the database, see ArtworkShareDir
+//   - files: The artwork to create
+//
+// Returns:
+//   - The number of created artworks
+//   - The errors of artworks which could not be created
+func WriteArtworkFiles(shareDir string, files []ArtworkFile) (int, []error) {
+	written := 0
+	var failed []error
+	for _, file := range files {
+		if err := writeArtworkFile(shareDir, file); err != nil {
+			failed = append(failed, fmt.Errorf("%s %s: %w",
+				locales.Translate("common.err.artworkwrite"), filepath.Base(file.Source), err))
+			continue
+		}
+		written++
+	}
+	return written, failed
+}
+
+// writeArtworkFile creates the images of one artwork.
+func writeArtworkFile(shareDir string, file ArtworkFile) error {
+	images, err := embeddedArtworkImages(file.Source)
+	if err != nil {
+		return err
+	}
+	if images == nil {
+		return errors.New(locales.Translate("common.err.artworknone"))
+	}
+
+	folder := filepath.Join(shareDir, filepath.FromSlash(path.Dir(strings.TrimPrefix(file.ImagePath, "/"))))
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return err
+	}
+	for i, artwork := range artworkImages {
+		if err := os.WriteFile(filepath.Join(folder, artwork.name), images[i], 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scaleSquare crops the centre square of an image and scales it to the given edge length.
+// Each target pixel is the average of the source pixels it covers.
+func scaleSquare(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	left := bounds.Min.X + (bounds.Dx()-side)/2
+	top := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0 := top + y*side/size
+		y1 := max(top+(y+1)*side/size, y0+1)
+		for x := 0; x < size; x++ {
+			x0 := left + x*side/size
+			x1 := max(left+(x+1)*side/size, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r += uint64(pr)
+					g += uint64(pg)
+					b += uint64(pb)
+					a += uint64(pa)
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(b / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
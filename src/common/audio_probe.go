@@ -0,0 +1,129 @@
+// common/audio_probe.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the reading of technical audio properties and tags of a file using ffprobe.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// probeError is an error of ProbeAudioFile identified by its localization key.
+// The message is translated when it is shown, so the error can be compared with errors.Is.
+type probeError string
+
+func (e probeError) Error() string {
+	return locales.Translate(string(e))
+}
+
+// ErrAudioProbe is wrapped by the errors of ProbeAudioFile when ffprobe fails or its output cannot be parsed.
+var ErrAudioProbe error = probeError("common.err.audioprobe")
+
+// ErrNoAudioStream is wrapped by the errors of ProbeAudioFile when the file has no audio stream.
+var ErrNoAudioStream error = probeError("common.err.noaudiostream")
+
+// AudioProperties holds the properties of an audio file as reported by ffprobe.
+type AudioProperties struct {
+	Length     int               // Duration in whole seconds
+	BitRate    int               // Bit rate in kbit/s
+	SampleRate int               // Sample rate in Hz
+	BitDepth   int               // Bits per sample, 0 for lossy formats
+	Tags       map[string]string // Format tags with lower-case keys
+}
+
+// ProbeAudioFile reads the duration, bit rate, sample rate, bit depth and tags of an audio file.
+//
+// Parameters:
+//   - filePath: The path to the audio file
+//
+// Returns:
+//   - The audio properties of the file
+//   - An error if ffprobe fails, its output cannot be parsed or the file has no audio stream
+func ProbeAudioFile(filePath string) (AudioProperties, error) {
+	cmd := exec.Command(FilePathFFprobe, "-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", filePath)
+	output, err := cmd.Output()
+	if err != nil {
+		return AudioProperties{}, fmt.Errorf("%w '%s': %w", ErrAudioProbe, filepath.Base(filePath), err)
+	}
+
+	var result struct {
+		Format struct {
+			Duration string            `json:"duration"`
+			BitRate  string            `json:"bit_rate"`
+			Tags     map[string]string `json:"tags"`
+		} `json:"format"`
+		Streams []struct {
+			CodecType   string      `json:"codec_type"`
+			SampleRate  string      `json:"sample_rate"`
+			BitRate     string      `json:"bit_rate"`
+			BitsPerRaw  json.Number `json:"bits_per_raw_sample"`
+			BitsPerSamp json.Number `json:"bits_per_sample"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return AudioProperties{}, fmt.Errorf("%w '%s': %w", ErrAudioProbe, filepath.Base(filePath), err)
+	}
+
+	props := AudioProperties{Tags: make(map[string]string)}
+	for key, value := range result.Format.Tags {
+		props.Tags[strings.ToLower(key)] = strings.TrimSpace(value)
+	}
+	if duration, err := strconv.ParseFloat(result.Format.Duration, 64); err == nil {
+		props.Length = int(math.Round(duration))
+	}
+	bitRate := result.Format.BitRate
+
+	audioFound := false
+	for _, stream := range result.Streams {
+		if stream.CodecType != "audio" {
+			continue
+		}
+		audioFound = true
+		props.SampleRate, _ = strconv.Atoi(stream.SampleRate)
+		if stream.BitRate != "" {
+			bitRate = stream.BitRate
+		}
+
+		// Lossy codecs report no bits per sample, rekordbox stores 0 for them as well
+		if depth, err := strconv.Atoi(string(stream.BitsPerRaw)); err == nil && depth > 0 {
+			props.BitDepth = depth
+		} else if depth, err := strconv.Atoi(string(stream.BitsPerSamp)); err == nil && depth > 0 {
+			props.BitDepth = depth
+		}
+		break
+	}
+	if !audioFound {
+		return AudioProperties{}, fmt.Errorf("%w: %s", ErrNoAudioStream, filepath.Base(filePath))
+	}
+
+	if value, err := strconv.Atoi(bitRate); err == nil {
+		props.BitRate = int(math.Round(float64(value) / 1000))
+	}
+
+	return props, nil
+}
+
+// Tag returns the first non-empty tag among the given keys.
+//
+// Parameters:
+//   - keys: Lower-case tag keys in priority order
+//
+// Returns:
+//   - The tag value, or an empty string if none of the tags is set
+func (p AudioProperties) Tag(keys ...string) string {
+	for _, key := range keys {
+		if value := p.Tags[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,396 @@
+// common/backup_manager.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the backup subsystem: creating, listing, verifying, pruning and restoring database backups.
+
+package common
+
+import (
+	"compress/gzip"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+const (
+	// backupTimeFormat is the timestamp layout embedded in backup file names.
+	backupTimeFormat = "2006-01-02@15_04_05"
+	// backupCompressedExt is appended to the file name of compressed backups.
+	backupCompressedExt = ".gz"
+)
+
+// backupNamePattern matches backup file names created by BackupManager, compressed or not,
+// and the safety copies written before a restore.
+var backupNamePattern = regexp.MustCompile(`^master_(backup|safety)_(\d{4}-\d{2}-\d{2}@\d{2}_\d{2}_\d{2})\.db(\.gz)?$`)
+
+// BackupSettings holds the user-configurable backup policy.
+// Zero values mean "unlimited" for the retention limits and "next to the database" for the directory.
+type BackupSettings struct {
+	Dir       string // Directory for backups, empty means the database directory
+	KeepCount int    // Number of newest backups to keep, 0 keeps all
+	KeepDays  int    // Maximum age of backups in days, 0 keeps all
+	Compress  bool   // Whether new backups are gzip-compressed
+}
+
+// BackupInfo describes a single backup file found in the backup directory.
+type BackupInfo struct {
+	Path       string    // Full path to the backup file
+	Name       string    // File name of the backup
+	Created    time.Time // Creation time parsed from the file name
+	Size       int64     // Size of the backup file in bytes
+	Compressed bool      // Whether the backup is gzip-compressed
+	Safety     bool      // Whether the file is the safety copy of the database written before a restore
+}
+
+// BackupManager manages backups of a single Rekordbox database file.
+// It does not hold a database connection; callers must make sure the database
+// is closed before creating or restoring a backup.
+type BackupManager struct {
+	dbPath   string
+	settings BackupSettings
+	logger   *Logger
+}
+
+// NewBackupManager creates a new backup manager for the specified database file.
+//
+// Parameters:
+//   - dbPath: Path to the Rekordbox database file
+//   - settings: Backup policy to apply
+//   - logger: Logger instance for recording backup operations
+//
+// Returns:
+//   - A new BackupManager instance
+func NewBackupManager(dbPath string, settings BackupSettings, logger *Logger) *BackupManager {
+	if logger == nil {
+		logger = &Logger{}
+	}
+	return &BackupManager{
+		dbPath:   dbPath,
+		settings: settings,
+		logger:   logger,
+	}
+}
+
+// Dir returns the directory where backups are stored.
+// When no backup directory is configured, the database directory is used.
+//
+// Returns:
+//   - The backup directory path
+func (b *BackupManager) Dir() string {
+	if !IsEmptyString(b.settings.Dir) {
+		return NormalizePath(b.settings.Dir)
+	}
+	return filepath.Dir(b.dbPath)
+}
+
+// Create writes a new timestamped backup of the database file into the backup directory.
+// The backup is gzip-compressed when compression is enabled in the settings.
+//
+// Returns:
+//   - The created backup and nil if successful
+//   - An empty BackupInfo and an error if the database cannot be read or the backup cannot be written
+func (b *BackupManager) Create() (BackupInfo, error) {
+	name := fmt.Sprintf("master_backup_%s.db", time.Now().Format(backupTimeFormat))
+	if b.settings.Compress {
+		name += backupCompressedExt
+	}
+	backupPath := filepath.Join(b.Dir(), name)
+
+	var err error
+	if b.settings.Compress {
+		err = compressFile(b.dbPath, backupPath)
+	} else {
+		err = CopyFile(b.dbPath, backupPath)
+	}
+	if err != nil {
+		os.Remove(backupPath)
+		return BackupInfo{}, fmt.Errorf("%s: %w", locales.Translate("common.err.dbbackup"), err)
+	}
+
+	info, err := backupInfoFromPath(backupPath)
+	if err != nil {
+		return BackupInfo{}, fmt.Errorf("%s: %w", locales.Translate("common.err.dbbackup"), err)
+	}
+
+	b.logger.Info("Database backup created: %s", backupPath)
+	return info, nil
+}
+
+// List returns all backups found in the backup directory, newest first.
+// Safety copies written by Restore are listed too and marked as such.
+//
+// Returns:
+//   - A slice of BackupInfo structures and nil if successful
+//   - nil and an error if the backup directory cannot be read
+func (b *BackupManager) List() ([]BackupInfo, error) {
+	entries, err := os.ReadDir(b.Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.backuplist"), err)
+	}
+
+	var backups []BackupInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !backupNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		info, err := backupInfoFromPath(filepath.Join(b.Dir(), entry.Name()))
+		if err != nil {
+			b.logger.Warning("Skipping unreadable backup %s: %v", entry.Name(), err)
+			continue
+		}
+		backups = append(backups, info)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Created.After(backups[j].Created)
+	})
+
+	return backups, nil
+}
+
+// Verify opens the backup with the database key and runs PRAGMA integrity_check on it.
+// Compressed backups are decompressed into a temporary file for the check.
+//
+// Parameters:
+//   - backup: The backup to verify
+//
+// Returns:
+//   - nil if the backup is a readable database and the integrity check reports "ok"
+//   - An error describing why the backup failed verification
+func (b *BackupManager) Verify(backup BackupInfo) error {
+	checkPath := backup.Path
+	if backup.Compressed {
+		tmpPath, err := decompressToTemp(backup.Path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.backupverify"), err)
+		}
+		defer os.Remove(tmpPath)
+		checkPath = tmpPath
+	}
+
+	if err := checkDatabaseIntegrity(checkPath); err != nil {
+		b.logger.Warning("Backup %s failed verification: %v", backup.Name, err)
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.backupverify"), err)
+	}
+
+	b.logger.Info("Backup verified: %s", backup.Path)
+	return nil
+}
+
+// Prune deletes backups that fall outside the retention policy.
+// A backup is removed when it is not among the KeepCount newest backups or is older than KeepDays.
+// Safety copies count as backups. The newest backup which is not a safety copy is never removed.
+//
+// Returns:
+//   - A slice of removed backups and nil if successful
+//   - The backups removed so far and an error if listing or deleting fails
+func (b *BackupManager) Prune() ([]BackupInfo, error) {
+	if b.settings.KeepCount <= 0 && b.settings.KeepDays <= 0 {
+		return nil, nil
+	}
+
+	backups, err := b.List()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -b.settings.KeepDays)
+	var removed []BackupInfo
+	keptNewest := false
+	for i, backup := range backups {
+		if !backup.Safety && !keptNewest {
+			keptNewest = true
+			continue
+		}
+		expired := b.settings.KeepDays > 0 && backup.Created.Before(cutoff)
+		overLimit := b.settings.KeepCount > 0 && i >= b.settings.KeepCount
+		if !expired && !overLimit {
+			continue
+		}
+		if err := os.Remove(backup.Path); err != nil {
+			return removed, fmt.Errorf("%s: %w", locales.Translate("common.err.backupprune"), err)
+		}
+		b.logger.Info("Backup removed by retention policy: %s", backup.Path)
+		removed = append(removed, backup)
+	}
+
+	return removed, nil
+}
+
+// Restore replaces the database file with the content of the given backup.
+// The backup is verified first, then the current database file is copied to a safety
+// file in the backup directory and finally overwritten. The database must not be open.
+//
+// Parameters:
+//   - backup: The backup to restore
+//
+// Returns:
+//   - The path of the safety copy and nil if the restore was successful
+//   - An empty string and an error if verification, the safety copy or the restore fails
+func (b *BackupManager) Restore(backup BackupInfo) (string, error) {
+	if err := b.Verify(backup); err != nil {
+		return "", err
+	}
+
+	safetyPath := filepath.Join(b.Dir(), fmt.Sprintf("master_safety_%s.db", time.Now().Format(backupTimeFormat)))
+	if FileExists(b.dbPath) {
+		if err := CopyFile(b.dbPath, safetyPath); err != nil {
+			os.Remove(safetyPath)
+			return "", fmt.Errorf("%s: %w", locales.Translate("common.err.backupsafety"), err)
+		}
+		b.logger.Info("Safety copy of the current database created: %s", safetyPath)
+	}
+
+	// Write the restored content next to the database first, so a failed copy never leaves a truncated master.db
+	tmpPath := b.dbPath + ".restore"
+	var err error
+	if backup.Compressed {
+		err = decompressFile(backup.Path, tmpPath)
+	} else {
+		err = CopyFile(backup.Path, tmpPath)
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, b.dbPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.backuprestore"), err)
+	}
+
+	b.logger.Info("Database restored from backup %s", backup.Path)
+	return safetyPath, nil
+}
+
+// backupInfoFromPath builds a BackupInfo from a backup file on disk.
+// The creation time is taken from the file name and falls back to the modification time.
+func backupInfoFromPath(path string) (BackupInfo, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return BackupInfo{}, err
+	}
+
+	name := filepath.Base(path)
+	created := stat.ModTime()
+	if match := backupNamePattern.FindStringSubmatch(name); match != nil {
+		if t, err := time.ParseInLocation(backupTimeFormat, match[2], time.Local); err == nil {
+			created = t
+		}
+	}
+
+	return BackupInfo{
+		Path:       path,
+		Name:       name,
+		Created:    created,
+		Size:       stat.Size(),
+		Compressed: strings.HasSuffix(name, backupCompressedExt),
+		Safety:     strings.HasPrefix(name, "master_safety_"),
+	}, nil
+}
+
+// checkDatabaseIntegrity opens an encrypted database file read-only and runs PRAGMA integrity_check.
+func checkDatabaseIntegrity(path string) error {
+	db, err := sql.Open("sqlite3", cipherConnString(path)+"&mode=ro")
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rows, err := db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	problems, err := scanIntegrityResult(rows)
+	if err != nil {
+		return err
+	}
+	return integrityError(problems)
+}
+
+// compressFile writes a gzip-compressed copy of sourcePath to destPath.
+func compressFile(sourcePath, destPath string) error {
+	if err := EnsureDirectoryExists(filepath.Dir(destPath)); err != nil {
+		return err
+	}
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	writer := gzip.NewWriter(dest)
+	writer.Name = filepath.Base(sourcePath)
+	if _, err := io.Copy(writer, source); err != nil {
+		writer.Close()
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return dest.Sync()
+}
+
+// decompressFile writes the decompressed content of the gzip file sourcePath to destPath.
+func decompressFile(sourcePath, destPath string) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	reader, err := gzip.NewReader(source)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	if _, err := io.Copy(dest, reader); err != nil {
+		return err
+	}
+
+	return dest.Sync()
+}
+
+// decompressToTemp decompresses a gzip file into a new temporary file and returns its path.
+// The caller is responsible for removing the temporary file.
+func decompressToTemp(sourcePath string) (string, error) {
+	tmp, err := os.CreateTemp("", "mrf_backup_*.db")
+	if err != nil {
+		return "", err
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+
+	if err := decompressFile(sourcePath, tmpPath); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+
+	return tmpPath, nil
+}
@@ -0,0 +1,434 @@
+// common/change_journal.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the change journal: an application-side record of every database write,
+// which allows reverting a single run without restoring a full backup.
+
+package common
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+// journalTimeFormat is the layout of run timestamps stored in the journal.
+const journalTimeFormat = "2006-01-02 15:04:05"
+
+// journalSchema creates the journal tables. Each applied changeset is one run,
+// each changed column of a row is one entry; rows without columns (plain deletes) have an empty column name.
+var journalSchema = []string{
+	`CREATE TABLE IF NOT EXISTS journal_run (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		module      TEXT    NOT NULL,
+		db_path     TEXT    NOT NULL,
+		created_at  TEXT    NOT NULL,
+		row_count   INTEGER NOT NULL,
+		revert_of   INTEGER NOT NULL DEFAULT 0,
+		reverted_by INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE IF NOT EXISTS journal_entry (
+		run_id      INTEGER NOT NULL,
+		seq         INTEGER NOT NULL,
+		table_name  TEXT    NOT NULL,
+		key_column  TEXT    NOT NULL,
+		row_key     TEXT    NOT NULL,
+		action      TEXT    NOT NULL,
+		label       TEXT    NOT NULL,
+		column_name TEXT    NOT NULL,
+		old_value,
+		new_value,
+		PRIMARY KEY (run_id, seq)
+	)`,
+}
+
+// JournalRun describes one applied changeset recorded in the journal.
+type JournalRun struct {
+	ID         int64     // Run ID
+	Module     string    // Name of the module that wrote the changes
+	DBPath     string    // Path of the database the changes were written to
+	Created    time.Time // Time the changes were committed
+	Rows       int       // Number of changed rows, not counting the USN counter
+	RevertOf   int64     // ID of the run this run reverted, 0 for regular runs
+	RevertedBy int64     // ID of the run that reverted this run, 0 if not reverted
+}
+
+// ChangeJournal stores the old and new values of all changes written by Changeset.Apply
+// in a separate SQLite file, so that a single run can be reverted later.
+// The journal file is opened on first use.
+type ChangeJournal struct {
+	path   string
+	db     *sql.DB
+	logger *Logger
+	mutex  sync.Mutex
+}
+
+// NewChangeJournal creates a change journal stored in the specified file.
+//
+// Parameters:
+//   - path: Path to the journal file, usually next to settings.conf
+//   - logger: Logger instance for recording journal operations
+//
+// Returns:
+//   - A new ChangeJournal instance
+func NewChangeJournal(path string, logger *Logger) *ChangeJournal {
+	if logger == nil {
+		logger = &Logger{}
+	}
+	return &ChangeJournal{
+		path:   path,
+		logger: logger,
+	}
+}
+
+// Path returns the path of the journal file.
+func (j *ChangeJournal) Path() string {
+	return j.path
+}
+
+// open opens the journal file and creates the tables if needed. The caller must hold the mutex.
+func (j *ChangeJournal) open() error {
+	if j.db != nil {
+		return nil
+	}
+
+	if err := EnsureDirectoryExists(filepath.Dir(j.path)); err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.journalopen"), err)
+	}
+
+	// The journal is a plain SQLite file, no encryption key is set
+	db, err := sql.Open("sqlite3", "file:"+j.path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.journalopen"), err)
+	}
+	for _, statement := range journalSchema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.journalopen"), err)
+		}
+	}
+
+	j.db = db
+	j.logger.Info("Change journal opened: %s", j.path)
+	return nil
+}
+
+// Close closes the journal file. It is safe to call when the journal was never opened.
+//
+// Returns:
+//   - An error if closing the file fails
+func (j *ChangeJournal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if j.db == nil {
+		return nil
+	}
+	err := j.db.Close()
+	j.db = nil
+	return err
+}
+
+// Record stores the applied changes of a changeset as a new run.
+// If the changeset reverts another run, that run is marked as reverted in the same transaction.
+//
+// Parameters:
+//   - module: Name of the module that wrote the changes
+//   - dbPath: Path of the database the changes were written to
+//   - changes: The applied row changes, deleted rows carry their former column values as Old values
+//   - revertOf: ID of the reverted run, 0 for regular runs
+//
+// Returns:
+//   - The ID of the new run
+//   - An error if the journal cannot be written
+func (j *ChangeJournal) Record(module, dbPath string, changes []RowChange, revertOf int64) (int64, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err := j.open(); err != nil {
+		return 0, err
+	}
+
+	rowCount := 0
+	for _, change := range changes {
+		if change.Table != "agentRegistry" {
+			rowCount++
+		}
+	}
+
+	tx, err := j.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("INSERT INTO journal_run (module, db_path, created_at, row_count, revert_of) VALUES (?, ?, ?, ?, ?)",
+		module, dbPath, time.Now().Format(journalTimeFormat), rowCount, revertOf)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+	}
+	runID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+	}
+
+	statement, err := tx.Prepare(`INSERT INTO journal_entry
+		(run_id, seq, table_name, key_column, row_key, action, label, column_name, old_value, new_value)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+	}
+	defer statement.Close()
+
+	seq := 0
+	for _, change := range changes {
+		fields := change.Fields
+		if len(fields) == 0 {
+			fields = []FieldChange{{}}
+		}
+		for _, field := range fields {
+			seq++
+			_, err := statement.Exec(runID, seq, change.Table, change.KeyColumn, change.Key, string(change.Action),
+				change.Label, field.Column, normalizeChangeValue(field.Old), normalizeChangeValue(field.New))
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+			}
+		}
+	}
+
+	if revertOf != 0 {
+		if _, err := tx.Exec("UPDATE journal_run SET reverted_by = ? WHERE id = ?", runID, revertOf); err != nil {
+			return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.journalwrite"), err)
+	}
+
+	j.logger.Info("Change journal run %d recorded: %s, %d rows", runID, module, rowCount)
+	return runID, nil
+}
+
+// Runs returns all recorded runs, newest first.
+//
+// Returns:
+//   - A slice of JournalRun structures
+//   - An error if the journal cannot be read
+func (j *ChangeJournal) Runs() ([]JournalRun, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+
+	rows, err := j.db.Query("SELECT id, module, db_path, created_at, row_count, revert_of, reverted_by FROM journal_run ORDER BY id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+	}
+	defer rows.Close()
+
+	var runs []JournalRun
+	for rows.Next() {
+		var run JournalRun
+		var created string
+		if err := rows.Scan(&run.ID, &run.Module, &run.DBPath, &created, &run.Rows, &run.RevertOf, &run.RevertedBy); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+		}
+		run.Created, _ = time.ParseInLocation(journalTimeFormat, created, time.Local)
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+	}
+
+	return runs, nil
+}
+
+// Entries returns the row changes of a run in the order they were applied.
+//
+// Parameters:
+//   - runID: The ID of the run
+//
+// Returns:
+//   - The row changes of the run
+//   - An error if the journal cannot be read
+func (j *ChangeJournal) Entries(runID int64) ([]RowChange, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	if err := j.open(); err != nil {
+		return nil, err
+	}
+
+	rows, err := j.db.Query(`SELECT table_name, key_column, row_key, action, label, column_name, old_value, new_value
+		FROM journal_entry WHERE run_id = ? ORDER BY seq`, runID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+	}
+	defer rows.Close()
+
+	var changes []RowChange
+	for rows.Next() {
+		var change RowChange
+		var action, column string
+		var oldValue, newValue interface{}
+		if err := rows.Scan(&change.Table, &change.KeyColumn, &change.Key, &action, &change.Label, &column, &oldValue, &newValue); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+		}
+		change.Action = ChangeAction(action)
+
+		// Consecutive entries of the same row belong to one row change
+		last := len(changes) - 1
+		if last < 0 || changes[last].Table != change.Table || changes[last].KeyColumn != change.KeyColumn ||
+			changes[last].Key != change.Key || changes[last].Action != change.Action {
+			changes = append(changes, change)
+			last++
+		}
+		if column != "" {
+			changes[last].Fields = append(changes[last].Fields, FieldChange{
+				Column: column,
+				Old:    normalizeChangeValue(oldValue),
+				New:    normalizeChangeValue(newValue),
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.journalread"), err)
+	}
+
+	return changes, nil
+}
+
+// PrepareRevert fills the changeset with the changes that restore the values a run has overwritten:
+// inserted rows are deleted, updated columns get their old values back and deleted rows are inserted again.
+// USN and timestamp columns are not restored, the revert is stamped like any other change.
+// Columns changed again after the run are reverted as well, but counted as conflicts.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - journal: The journal holding the run
+//   - run: The run to revert
+//
+// Returns:
+//   - The number of columns or rows that were changed after the run
+//   - An error if the run cannot be reverted or the database cannot be read
+func PrepareRevert(cs *Changeset, journal *ChangeJournal, run JournalRun) (int, error) {
+	if run.RevertedBy != 0 {
+		return 0, fmt.Errorf(locales.Translate("common.err.journalreverted"), run.ID, run.RevertedBy)
+	}
+	if !strings.EqualFold(NormalizePath(run.DBPath), NormalizePath(cs.DB().GetDatabasePath())) {
+		return 0, fmt.Errorf(locales.Translate("common.err.journalotherdb"), run.DBPath)
+	}
+
+	changes, err := journal.Entries(run.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	cs.mutex.Lock()
+	cs.revertOf = run.ID
+	cs.mutex.Unlock()
+	conflicts := 0
+
+	// Undo the changes in reverse order, so dependent rows are handled before the rows they refer to
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if !isStampedTable(change.Table) {
+			continue
+		}
+
+		switch change.Action {
+		case ChangeActionInsert:
+			cs.Delete(change.Table, change.Key, change.Label)
+
+		case ChangeActionDelete:
+			var fields []FieldChange
+			for _, field := range change.Fields {
+				// USN and updated_at are overwritten by the stamp of the changeset, created_at is kept
+				if field.Column != change.KeyColumn {
+					fields = append(fields, FieldChange{Column: field.Column, New: field.Old})
+				}
+			}
+			cs.Insert(change.Table, change.Key, change.Label, fields...)
+
+		default:
+			var fields []FieldChange
+			var written []interface{}
+			for _, field := range change.Fields {
+				if !changesetStampColumns[field.Column] {
+					fields = append(fields, FieldChange{Column: field.Column, New: field.Old})
+					written = append(written, field.New)
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			if _, err := cs.UpdateByKey(change.Table, change.KeyColumn, change.Key, change.Label, fields...); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					// The row was deleted after the run, there is nothing to restore
+					conflicts++
+					continue
+				}
+				return conflicts, err
+			}
+			// UpdateByKey filled in the current values, which differ from the written ones if the row changed since
+			for k, field := range fields {
+				if !sameChangeValue(field.Old, written[k]) {
+					conflicts++
+				}
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+// snapshotRow reads all columns of a row, so a deleted row can be journaled and restored later.
+//
+// Parameters:
+//   - dbMgr: The database manager, usually inside an active write session
+//   - table: The name of the table
+//   - keyColumn: The column identifying the row
+//   - key: The value of the key column
+//
+// Returns:
+//   - The column values of the row as Old values, nil if the row does not exist
+//   - An error if the row cannot be read
+func snapshotRow(dbMgr *DBManager, table, keyColumn, key string) ([]FieldChange, error) {
+	rows, err := dbMgr.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", table, keyColumn), key)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbquery"), err)
+	}
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbquery"), err)
+	}
+
+	fields := make([]FieldChange, len(columns))
+	for i, column := range columns {
+		fields[i] = FieldChange{Column: column, Old: normalizeChangeValue(values[i])}
+	}
+	return fields, nil
+}
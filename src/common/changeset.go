@@ -0,0 +1,736 @@
+// common/changeset.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the changeset used to preview database changes before they are written.
+
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+// ChangeAction identifies what a row change does with a database row.
+type ChangeAction string
+
+const (
+	// ChangeActionInsert adds a new row
+	ChangeActionInsert ChangeAction = "insert"
+
+	// ChangeActionUpdate modifies columns of an existing row
+	ChangeActionUpdate ChangeAction = "update"
+
+	// ChangeActionDelete removes an existing row
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+// changesetTimeFormat is the layout of created_at and updated_at values written by rekordbox.
+const changesetTimeFormat = "2006-01-02 15:04:05.000 +00:00"
+
+// changesetStampColumns lists bookkeeping columns that are written together with real changes,
+// but do not make a row "changed" on their own.
+var changesetStampColumns = map[string]bool{
+	"rb_local_usn": true,
+	"created_at":   true,
+	"updated_at":   true,
+}
+
+// FieldChange holds the old and new value of a single column.
+type FieldChange struct {
+	Column string      `json:"column"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+}
+
+// RowChange describes one pending change of a single database row.
+type RowChange struct {
+	Table     string        `json:"table"`
+	KeyColumn string        `json:"keyColumn"`
+	Key       string        `json:"key"`
+	Action    ChangeAction  `json:"action"`
+	Label     string        `json:"label"` // Human readable description of the row, e.g. file name
+	Fields    []FieldChange `json:"fields"`
+}
+
+// Changeset collects database changes prepared by a module without writing them.
+// Modules fill the changeset instead of calling DBManager.Execute directly, the user reviews
+// the old and new values and only then the changes are written by Apply in a single write session.
+// All reads done while the changeset is being filled see the database as it was before the run,
+// so the changeset keeps track of IDs it has allocated and rows it is going to insert.
+type Changeset struct {
+	dbMgr    *DBManager
+	ids      *IDAllocator // allocator of IDs and UUIDs for inserted rows
+	changes  []RowChange
+	index    map[string]int // "table|keyColumn|key" -> position in changes
+	usn      int64          // USN used by this changeset, 0 until NextUSN is called
+	usnOld   int64          // USN counter value before this changeset
+	stamp    string         // updated_at value written with the changes, empty until Stamp is called
+	source   string         // name of the module recorded in the change journal
+	revertOf int64          // ID of the journal run reverted by this changeset, 0 for regular changesets
+	mutex    sync.Mutex
+}
+
+// NewChangeset creates an empty changeset bound to the given database manager.
+//
+// Parameters:
+//   - dbMgr: Database manager used to read current values and to apply the changes
+//
+// Returns:
+//   - A new empty Changeset
+func NewChangeset(dbMgr *DBManager) *Changeset {
+	return &Changeset{
+		dbMgr: dbMgr,
+		ids:   NewIDAllocator(dbMgr),
+		index: make(map[string]int),
+	}
+}
+
+// DB returns the database manager the changeset reads from and writes to.
+func (c *Changeset) DB() *DBManager {
+	return c.dbMgr
+}
+
+// IDs returns the allocator of IDs and UUIDs used for rows inserted by this changeset.
+func (c *Changeset) IDs() *IDAllocator {
+	return c.ids
+}
+
+// SetSource sets the name of the module recorded with the changes in the change journal.
+//
+// Parameters:
+//   - source: Name of the module writing the changes
+func (c *Changeset) SetSource(source string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.source = source
+}
+
+// changeKey builds the lookup key of a row in the changeset.
+func changeKey(table, keyColumn, key string) string {
+	return table + "|" + keyColumn + "|" + key
+}
+
+// Len returns the number of rows that would be changed, not counting the USN counter.
+func (c *Changeset) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.changes)
+}
+
+// Changes returns a copy of all pending row changes in the order they will be applied.
+// If any change is pending and a USN was allocated, the update of the USN counter comes first.
+// After Stamp was called, inserted and updated rows carry the USN and timestamps of the changeset.
+func (c *Changeset) Changes() []RowChange {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	result := make([]RowChange, 0, len(c.changes)+1)
+	if c.usn != 0 && len(c.changes) > 0 {
+		result = append(result, RowChange{
+			Table:     "agentRegistry",
+			KeyColumn: "registry_id",
+			Key:       "localUpdateCount",
+			Action:    ChangeActionUpdate,
+			Fields:    []FieldChange{{Column: "int_1", Old: c.usnOld, New: c.usn}},
+		})
+	}
+	for _, change := range c.changes {
+		change.Fields = append([]FieldChange(nil), change.Fields...)
+		if c.stamp != "" && change.Action != ChangeActionDelete && isStampedTable(change.Table) {
+			change.Fields = mergeFieldChange(change.Fields, FieldChange{Column: "rb_local_usn", New: c.usn})
+			change.Fields = mergeFieldChange(change.Fields, FieldChange{Column: "updated_at", New: c.stamp})
+			if change.Action == ChangeActionInsert && !hasFieldChange(change.Fields, "created_at") {
+				change.Fields = append(change.Fields, FieldChange{Column: "created_at", New: c.stamp})
+			}
+		}
+		result = append(result, change)
+	}
+	return result
+}
+
+// NextID returns a free ID of the table, taking IDs already allocated by this changeset into account.
+//
+// Parameters:
+//   - table: The name of the table
+//
+// Returns:
+//   - The new ID as a string
+//   - An error if no free ID can be allocated
+func (c *Changeset) NextID(table string) (string, error) {
+	return c.ids.NextID(table)
+}
+
+// NextUSN returns the USN (Update Sequence Number) used for all changes of this changeset.
+// The USN counter in agentRegistry is only incremented by Apply, and only if there is something to write.
+//
+// Returns:
+//   - The USN value
+//   - An error if the current USN cannot be read
+func (c *Changeset) NextUSN() (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.usn != 0 {
+		return c.usn, nil
+	}
+
+	row := c.dbMgr.QueryRow("SELECT int_1 FROM agentRegistry WHERE registry_id = 'localUpdateCount'")
+	if row == nil {
+		return 0, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), c.dbMgr.GetDatabasePath())
+	}
+	var current int64
+	if err := row.Scan(&current); err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.dbusnretrievalfailed"), err)
+	}
+
+	c.usnOld = current
+	c.usn = current + 1
+	return c.usn, nil
+}
+
+// Stamp allocates the USN and the timestamp of the changeset. From then on every inserted or updated
+// row of a rekordbox table is written with this USN in rb_local_usn and the timestamp in updated_at
+// (and created_at for new rows), so rekordbox sees all modified rows as changed.
+// Modules never set these columns themselves. Calling Stamp again has no effect.
+//
+// Returns:
+//   - An error if the current USN cannot be read
+func (c *Changeset) Stamp() error {
+	if _, err := c.NextUSN(); err != nil {
+		return err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stamp == "" {
+		c.stamp = time.Now().UTC().Format(changesetTimeFormat)
+	}
+	return nil
+}
+
+// Insert records a new row. The key column of inserted rows is always ID.
+//
+// Parameters:
+//   - table: The name of the table
+//   - id: The ID of the new row, usually obtained from NextID
+//   - label: Human readable description of the row
+//   - fields: Column values of the new row, Old values are ignored
+func (c *Changeset) Insert(table, id, label string, fields ...FieldChange) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	change := RowChange{Table: table, KeyColumn: "ID", Key: id, Action: ChangeActionInsert, Label: label}
+	for _, field := range fields {
+		change.Fields = append(change.Fields, FieldChange{Column: field.Column, New: field.New})
+	}
+	c.index[changeKey(table, "ID", id)] = len(c.changes)
+	c.changes = append(c.changes, change)
+}
+
+// InsertNew records a new row the way rekordbox creates it: with a freshly allocated ID,
+// a new UUID and cleared sync columns. Columns given in fields take precedence over these defaults.
+//
+// Parameters:
+//   - table: The name of the table
+//   - label: Human readable description of the row
+//   - fields: Column values of the new row
+//
+// Returns:
+//   - The ID of the new row
+//   - An error if no free ID can be allocated
+func (c *Changeset) InsertNew(table, label string, fields ...FieldChange) (string, error) {
+	id, err := c.NextID(table)
+	if err != nil {
+		return "", err
+	}
+
+	given := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		given[field.Column] = true
+	}
+	if !given["UUID"] {
+		fields = append(fields, FieldChange{Column: "UUID", New: NewUUID()})
+	}
+	for _, field := range rowSyncDefaults {
+		if !given[field.Column] {
+			fields = append(fields, field)
+		}
+	}
+
+	c.Insert(table, id, label, fields...)
+	return id, nil
+}
+
+// Delete records the removal of a row identified by its ID. A row already recorded for removal is not recorded again.
+//
+// Parameters:
+//   - table: The name of the table
+//   - id: The ID of the row to remove
+//   - label: Human readable description of the row
+func (c *Changeset) Delete(table, id, label string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// A row is removed only once, a second delete would remove nothing
+	key := changeKey(table, "ID", id)
+	if i, ok := c.index[key]; ok && c.changes[i].Action == ChangeActionDelete {
+		return
+	}
+	c.index[key] = len(c.changes)
+	c.changes = append(c.changes, RowChange{Table: table, KeyColumn: "ID", Key: id, Action: ChangeActionDelete, Label: label})
+}
+
+// Update records new values for columns of an existing row identified by its ID.
+// Current values are read from the database; columns whose value does not change are dropped.
+//
+// Parameters:
+//   - table: The name of the table
+//   - id: The ID of the row
+//   - label: Human readable description of the row
+//   - fields: New column values, Old values are filled in by the changeset
+//
+// Returns:
+//   - true if at least one column (other than USN and timestamps) would change
+//   - An error if the current values cannot be read
+func (c *Changeset) Update(table, id, label string, fields ...FieldChange) (bool, error) {
+	return c.UpdateByKey(table, "ID", id, label, fields...)
+}
+
+// UpdateByKey works like Update, but identifies the row by an arbitrary key column.
+//
+// Parameters:
+//   - table: The name of the table
+//   - keyColumn: The column identifying the row
+//   - key: The value of the key column
+//   - label: Human readable description of the row
+//   - fields: New column values, Old values are filled in by the changeset
+//
+// Returns:
+//   - true if at least one column (other than USN and timestamps) would change
+//   - An error if the current values cannot be read
+func (c *Changeset) UpdateByKey(table, keyColumn, key, label string, fields ...FieldChange) (bool, error) {
+	if len(fields) == 0 {
+		return false, nil
+	}
+
+	// Rows inserted by this changeset do not exist in the database yet
+	c.mutex.Lock()
+	pos, pending := c.index[changeKey(table, keyColumn, key)]
+	pendingInsert := pending && c.changes[pos].Action == ChangeActionInsert
+	c.mutex.Unlock()
+
+	if !pendingInsert {
+		columns := make([]string, len(fields))
+		values := make([]interface{}, len(fields))
+		ptrs := make([]interface{}, len(fields))
+		for i, field := range fields {
+			columns[i] = field.Column
+			ptrs[i] = &values[i]
+		}
+
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", strings.Join(columns, ", "), table, keyColumn)
+		row := c.dbMgr.QueryRow(query, key)
+		if row == nil {
+			return false, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), c.dbMgr.GetDatabasePath())
+		}
+		if err := row.Scan(ptrs...); err != nil {
+			return false, fmt.Errorf("%s: %w", locales.Translate("common.err.dbquery"), err)
+		}
+		for i := range fields {
+			fields[i].Old = normalizeChangeValue(values[i])
+		}
+	}
+
+	return c.UpdateKnown(table, keyColumn, key, label, fields...), nil
+}
+
+// UpdateKnown records new values for columns of an existing row whose current values the caller already knows.
+// This avoids one extra query per row in bulk operations.
+//
+// Parameters:
+//   - table: The name of the table
+//   - keyColumn: The column identifying the row
+//   - key: The value of the key column
+//   - label: Human readable description of the row
+//   - fields: Old and new column values
+//
+// Returns:
+//   - true if at least one column (other than USN and timestamps) would change
+func (c *Changeset) UpdateKnown(table, keyColumn, key, label string, fields ...FieldChange) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	changed := false
+	var kept []FieldChange
+	for _, field := range fields {
+		field.New = normalizeChangeValue(field.New)
+		if changesetStampColumns[field.Column] {
+			kept = append(kept, field)
+			continue
+		}
+		if sameChangeValue(field.Old, field.New) {
+			continue
+		}
+		changed = true
+		kept = append(kept, field)
+	}
+
+	pos, pending := c.index[changeKey(table, keyColumn, key)]
+	if pending && c.changes[pos].Action != ChangeActionDelete {
+		// Merge into the pending change of the same row
+		if c.changes[pos].Action == ChangeActionInsert {
+			changed = true
+		}
+		if !changed {
+			return false
+		}
+		for _, field := range kept {
+			c.changes[pos].Fields = mergeFieldChange(c.changes[pos].Fields, field)
+		}
+		return true
+	}
+
+	if !changed {
+		return false
+	}
+
+	c.index[changeKey(table, keyColumn, key)] = len(c.changes)
+	c.changes = append(c.changes, RowChange{
+		Table:     table,
+		KeyColumn: keyColumn,
+		Key:       key,
+		Action:    ChangeActionUpdate,
+		Label:     label,
+		Fields:    kept,
+	})
+	return true
+}
+
+// FindPendingInsert looks for a row inserted by this changeset with the given column value (case insensitive).
+//
+// Parameters:
+//   - table: The name of the table
+//   - column: The column to compare
+//   - value: The value to look for
+//
+// Returns:
+//   - The ID of the pending row and true if found, otherwise an empty string and false
+func (c *Changeset) FindPendingInsert(table, column, value string) (string, bool) {
+	return c.FindPendingInsertMatching(table, map[string]string{column: value})
+}
+
+// FindPendingInsertMatching looks for a row inserted by this changeset whose columns all have the given values
+// (case insensitive). A column missing in the inserted row matches an empty value.
+//
+// Parameters:
+//   - table: The name of the table
+//   - match: The values to look for by column
+//
+// Returns:
+//   - The ID of the pending row and true if found, otherwise an empty string and false
+func (c *Changeset) FindPendingInsertMatching(table string, match map[string]string) (string, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, change := range c.changes {
+		if change.Table != table || change.Action != ChangeActionInsert {
+			continue
+		}
+		values := make(map[string]string, len(change.Fields))
+		for _, field := range change.Fields {
+			if field.New != nil {
+				values[field.Column] = fmt.Sprintf("%v", field.New)
+			}
+		}
+		matches := true
+		for column, value := range match {
+			if !strings.EqualFold(values[column], value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return change.Key, true
+		}
+	}
+	return "", false
+}
+
+// Summary returns the number of changed rows per table and action, e.g. "djmdContent": {"update": 12}.
+func (c *Changeset) Summary() map[string]map[ChangeAction]int {
+	summary := make(map[string]map[ChangeAction]int)
+	for _, change := range c.Changes() {
+		if summary[change.Table] == nil {
+			summary[change.Table] = make(map[ChangeAction]int)
+		}
+		summary[change.Table][change.Action]++
+	}
+	return summary
+}
+
+// SummaryText returns a localized one line description of the changeset summary.
+func (c *Changeset) SummaryText() string {
+	summary := c.Summary()
+	tables := make([]string, 0, len(summary))
+	for table := range summary {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	parts := make([]string, 0, len(tables))
+	for _, table := range tables {
+		counts := summary[table]
+		var actions []string
+		for _, action := range []ChangeAction{ChangeActionInsert, ChangeActionUpdate, ChangeActionDelete} {
+			if counts[action] > 0 {
+				actions = append(actions, fmt.Sprintf(locales.Translate("common.changeset."+string(action)+"count"), counts[action]))
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", table, strings.Join(actions, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Apply stamps the changeset and writes all pending changes to the database in a single write session.
+// Writing is refused if the database schema does not match a known profile or lacks a written column.
+// The session is rolled back if any statement fails or the run is cancelled,
+// so the database is either fully updated or left untouched.
+// If the database manager has a change journal, the written changes are recorded in it after the commit;
+// deleted rows are read before they are removed, so they can be restored by reverting the run.
+//
+// Parameters:
+//   - isCancelled: Optional function reporting whether the user stopped the run
+//   - onProgress: Optional callback invoked after each applied row
+//
+// Returns:
+//   - The number of applied row changes (not counting the USN counter)
+//   - ErrCancelled if the run was cancelled, or an error if writing failed; nothing is written in both cases
+func (c *Changeset) Apply(isCancelled func() bool, onProgress func(applied, total int)) (int, error) {
+	// The write permission covers this Apply only, also if it writes nothing or fails before the session
+	defer c.dbMgr.DisableWrites()
+
+	if c.Len() == 0 {
+		return 0, nil
+	}
+
+	// Every written row gets the USN and timestamp of this changeset
+	if err := c.Stamp(); err != nil {
+		return 0, err
+	}
+	changes := c.Changes()
+
+	// Never write to a schema the application does not know
+	report, err := c.dbMgr.CheckWriteCompatibility()
+	if err != nil {
+		return 0, err
+	}
+	if err := checkChangesetColumns(report, changes); err != nil {
+		return 0, err
+	}
+
+	if err := c.dbMgr.BeginSession(); err != nil {
+		return 0, err
+	}
+	defer c.dbMgr.Rollback()
+
+	journal := c.dbMgr.Journal()
+	total := c.Len()
+	applied := 0
+	journaled := make([]RowChange, 0, len(changes))
+	for _, change := range changes {
+		if isCancelled != nil && isCancelled() {
+			return 0, ErrCancelled
+		}
+
+		if journal != nil && change.Action == ChangeActionDelete {
+			fields, err := snapshotRow(c.dbMgr, change.Table, change.KeyColumn, change.Key)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.changesetapply"), err)
+			}
+			change.Fields = fields
+		}
+		// A delete of a missing row removes nothing, reverting it must not insert an empty row
+		if change.Action != ChangeActionDelete || change.Fields != nil {
+			journaled = append(journaled, change)
+		}
+
+		query, args := change.statement()
+		if err := c.dbMgr.Execute(query, args...); err != nil {
+			c.dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("%s/%s", change.Table, change.Key), err)
+			return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.changesetapply"), err)
+		}
+
+		if change.Table == "agentRegistry" {
+			continue
+		}
+		applied++
+		if onProgress != nil {
+			onProgress(applied, total)
+		}
+	}
+
+	if err := c.dbMgr.Commit(); err != nil {
+		return 0, err
+	}
+
+	// The changes are committed at this point, a journal failure only costs the ability to revert this run
+	if journal != nil {
+		c.mutex.Lock()
+		source, revertOf := c.source, c.revertOf
+		c.mutex.Unlock()
+		if _, err := journal.Record(source, c.dbMgr.GetDatabasePath(), journaled, revertOf); err != nil {
+			c.dbMgr.logger.Error("%s: %v", locales.Translate("common.err.journalwrite"), err)
+		}
+	}
+
+	return applied, nil
+}
+
+// Export writes the changeset to a file. The format is chosen by the file extension:
+// ".json" produces a JSON array of row changes, anything else produces CSV with one line per column change.
+//
+// Parameters:
+//   - path: The path of the export file
+//
+// Returns:
+//   - An error if the file cannot be written
+func (c *Changeset) Export(path string) error {
+	changes := c.Changes()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.changesetexport"), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.changesetexport"), err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.changesetexport"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"table", "action", "key", "label", "column", "old", "new"})
+	for _, change := range changes {
+		if len(change.Fields) == 0 {
+			writer.Write([]string{change.Table, string(change.Action), change.Key, change.Label, "", "", ""})
+			continue
+		}
+		for _, field := range change.Fields {
+			writer.Write([]string{
+				change.Table, string(change.Action), change.Key, change.Label,
+				field.Column, FormatChangeValue(field.Old), FormatChangeValue(field.New),
+			})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.changesetexport"), err)
+	}
+	return nil
+}
+
+// statement builds the SQL statement and its arguments for the row change.
+func (rc RowChange) statement() (string, []interface{}) {
+	switch rc.Action {
+	case ChangeActionInsert:
+		columns := []string{rc.KeyColumn}
+		placeholders := []string{"?"}
+		args := []interface{}{rc.Key}
+		for _, field := range rc.Fields {
+			columns = append(columns, field.Column)
+			placeholders = append(placeholders, "?")
+			args = append(args, field.New)
+		}
+		return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", rc.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", ")), args
+	case ChangeActionDelete:
+		return fmt.Sprintf("DELETE FROM %s WHERE %s = ?", rc.Table, rc.KeyColumn), []interface{}{rc.Key}
+	default:
+		assignments := make([]string, len(rc.Fields))
+		args := make([]interface{}, 0, len(rc.Fields)+1)
+		for i, field := range rc.Fields {
+			assignments[i] = field.Column + " = ?"
+			args = append(args, field.New)
+		}
+		args = append(args, rc.Key)
+		return fmt.Sprintf("UPDATE %s SET %s WHERE %s = ?", rc.Table, strings.Join(assignments, ", "), rc.KeyColumn), args
+	}
+}
+
+// mergeFieldChange replaces the new value of an already recorded column or appends the column.
+func mergeFieldChange(fields []FieldChange, field FieldChange) []FieldChange {
+	for i := range fields {
+		if fields[i].Column == field.Column {
+			fields[i].New = field.New
+			return fields
+		}
+	}
+	return append(fields, field)
+}
+
+// hasFieldChange reports whether the column is already present in the field changes.
+func hasFieldChange(fields []FieldChange, column string) bool {
+	for _, field := range fields {
+		if field.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// isStampedTable reports whether rows of the table carry rb_local_usn and updated_at.
+// All rekordbox library tables (djmd*) do, agentRegistry and other internal tables do not.
+func isStampedTable(table string) bool {
+	return strings.HasPrefix(table, "djmd")
+}
+
+// normalizeChangeValue converts database values to comparable Go values.
+func normalizeChangeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case NullString:
+		return v.ValueOrNil()
+	case NullInt64:
+		return v.ValueOrNil()
+	case int:
+		return int64(v)
+	default:
+		return v
+	}
+}
+
+// sameChangeValue reports whether two normalized values are equal.
+// NULL is only equal to NULL, other values are compared by their text representation,
+// because SQLite columns may hold numbers as text and vice versa.
+func sameChangeValue(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
+}
+
+// FormatChangeValue returns the text representation of a changeset value used in the review dialog and exports.
+func FormatChangeValue(value interface{}) string {
+	if value == nil {
+		return "NULL"
+	}
+	return fmt.Sprintf("%v", normalizeChangeValue(value))
+}
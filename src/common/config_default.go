@@ -5,6 +5,8 @@
 
 package common
 
+import "strings"
+
 // GetDefaultFormatConverterCfg returns default configuration for FormatConverter module
 func GetDefaultFormatConverterCfg() FormatConverterCfg {
 	return FormatConverterCfg{
@@ -171,14 +173,91 @@ func GetDefaultFlacFixerCfg() FlacFixerCfg {
 			ValidateOnActions: []string{},
 		},
 		Extensions: FieldCfg{
-			FieldType:         "hidden",
-			Required:          false,
+			FieldType:         "select",
+			Required:          true,
 			DependsOn:         "",
 			ActiveWhen:        "",
 			ValidationType:    "none",
-			Value:             ExtensionFLAC,
+			Value:             strings.Join([]string{ExtensionFLAC, ExtensionAIFF, ".aif", ExtensionWAV, ExtensionM4A, ExtensionMP3}, ","),
 			ValidateOnActions: []string{ValidatorActionStart},
 		},
+		WriteLabel: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteComposer: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteISRC: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteRemixer: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteGenre: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteTrackNo: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteDiscNo: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{},
+		},
+		WriteArtwork: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "false",
+			ValidateOnActions: []string{},
+		},
+		FieldPolicies: FieldCfg{
+			FieldType:         "select",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "",
+			ValidateOnActions: []string{},
+		},
+		DateFallback: FieldCfg{
+			FieldType:         "select",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             DateFallbackFirstDay,
+			ValidateOnActions: []string{},
+		},
+		TagMapping: FieldCfg{
+			FieldType:         "mapping",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "",
+			ValidateOnActions: []string{},
+		},
 	}
 }
 
@@ -258,6 +337,188 @@ func GetDefaultFormatUpdaterCfg() FormatUpdaterCfg {
 	}
 }
 
+// GetDefaultDbAuditCfg returns default configuration for DbAudit module
+func GetDefaultDbAuditCfg() DbAuditCfg {
+	return DbAuditCfg{
+		CleanCues: FieldCfg{
+			FieldType:      "checkbox",
+			Required:       false,
+			ValidationType: "none",
+			Value:          "true",
+		},
+		CleanPlaylistEntries: FieldCfg{
+			FieldType:      "checkbox",
+			Required:       false,
+			ValidationType: "none",
+			Value:          "true",
+		},
+		CleanAlbums: FieldCfg{
+			FieldType:      "checkbox",
+			Required:       false,
+			ValidationType: "none",
+			Value:          "false",
+		},
+		CleanArtists: FieldCfg{
+			FieldType:      "checkbox",
+			Required:       false,
+			ValidationType: "none",
+			Value:          "false",
+		},
+	}
+}
+
+// GetDefaultPlaylistMirrorCfg returns default configuration for PlaylistMirror module
+func GetDefaultPlaylistMirrorCfg() PlaylistMirrorCfg {
+	return PlaylistMirrorCfg{
+		SourcePlaylist: FieldCfg{
+			FieldType:         ContentTypePlaylist,
+			Required:          true,
+			ValidationType:    "filled",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		TargetFolder: FieldCfg{
+			FieldType:         ContentTypePlaylist,
+			Required:          true,
+			ValidationType:    "filled",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		TwinFolder: FieldCfg{
+			FieldType:         ContentTypeFolder,
+			Required:          true,
+			ValidationType:    "exists",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		RemoveStale: FieldCfg{
+			FieldType:      "checkbox",
+			Required:       false,
+			ValidationType: "none",
+			Value:          "false",
+		},
+	}
+}
+
+// GetDefaultTrackImporterCfg returns default configuration for TrackImporter module
+func GetDefaultTrackImporterCfg() TrackImporterCfg {
+	return TrackImporterCfg{
+		SourceFolder: FieldCfg{
+			FieldType:         "folder",
+			Required:          true,
+			DependsOn:         "",
+			ActiveWhen:        "",
+			ValidationType:    "exists",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		Recursive: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			DependsOn:         "",
+			ActiveWhen:        "",
+			ValidationType:    "none",
+			Value:             "false",
+			ValidateOnActions: []string{},
+		},
+	}
+}
+
+// GetDefaultRekordboxXmlCfg returns default configuration for RekordboxXml module.
+// Only the import is validated, the export only reads the database and needs no backup.
+func GetDefaultRekordboxXmlCfg() RekordboxXmlCfg {
+	return RekordboxXmlCfg{
+		ExportAll: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{ValidatorActionCustomUpdate},
+		},
+		ExportPlaylist: FieldCfg{
+			FieldType:         ContentTypePlaylist,
+			Required:          false,
+			DependsOn:         "exportAll",
+			ActiveWhen:        "false",
+			ValidationType:    "none",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionCustomUpdate},
+		},
+		ImportFile: FieldCfg{
+			FieldType:         "file",
+			Required:          true,
+			ValidationType:    "exists",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		ImportCues: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "true",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		ImportMetadata: FieldCfg{
+			FieldType:         "checkbox",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "false",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+	}
+}
+
+// GetDefaultArtistMergeCfg returns default configuration for ArtistMerge module
+func GetDefaultArtistMergeCfg() ArtistMergeCfg {
+	return ArtistMergeCfg{
+		Similarity: FieldCfg{
+			FieldType:         "select",
+			Required:          false,
+			ValidationType:    "none",
+			Value:             "90",
+			ValidateOnActions: []string{},
+		},
+	}
+}
+
+// GetDefaultTagWriterCfg returns default configuration for TagWriter module
+func GetDefaultTagWriterCfg() TagWriterCfg {
+	return TagWriterCfg{
+		SourceType: FieldCfg{
+			FieldType:         "select",
+			Required:          true,
+			ValidationType:    "none",
+			Value:             ContentTypeFolder,
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		Folder: FieldCfg{
+			FieldType:         ContentTypeFolder,
+			Required:          true,
+			DependsOn:         "sourceType",
+			ActiveWhen:        ContentTypeFolder,
+			ValidationType:    "exists | write",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		PlaylistID: FieldCfg{
+			FieldType:         ContentTypePlaylist,
+			Required:          true,
+			DependsOn:         "sourceType",
+			ActiveWhen:        ContentTypePlaylist,
+			ValidationType:    "filled",
+			Value:             "",
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+		Fields: FieldCfg{
+			FieldType:         "select",
+			Required:          true,
+			ValidationType:    "filled",
+			Value:             strings.Join(DefaultTagExportFields, ","),
+			ValidateOnActions: []string{ValidatorActionStart},
+		},
+	}
+}
+
 // GetDefaultModuleCfg returns default configuration for any module by type
 func GetDefaultModuleCfg(moduleType string) interface{} {
 	switch moduleType {
@@ -271,6 +532,18 @@ func GetDefaultModuleCfg(moduleType string) interface{} {
 		return GetDefaultDataDuplicatorCfg()
 	case ModuleKeyFormatUpdater:
 		return GetDefaultFormatUpdaterCfg()
+	case ModuleKeyDbAudit:
+		return GetDefaultDbAuditCfg()
+	case ModuleKeyPlaylistMirror:
+		return GetDefaultPlaylistMirrorCfg()
+	case ModuleKeyTrackImporter:
+		return GetDefaultTrackImporterCfg()
+	case ModuleKeyRekordboxXml:
+		return GetDefaultRekordboxXmlCfg()
+	case ModuleKeyArtistMerge:
+		return GetDefaultArtistMergeCfg()
+	case ModuleKeyTagWriter:
+		return GetDefaultTagWriterCfg()
 	default:
 		return nil
 	}
@@ -17,8 +17,12 @@ import (
 // GlobalConfig holds global application settings that are shared across all modules.
 // These settings typically include application-wide preferences and configurations.
 type GlobalConfig struct {
-	DatabasePath string
-	Language     string
+	DatabasePath    string
+	Language        string
+	BackupDir       string
+	BackupKeepCount int
+	BackupKeepDays  int
+	BackupCompress  bool
 }
 
 // ConfigManager handles loading, saving, and managing application configuration.
@@ -71,6 +75,21 @@ func (mgr *ConfigManager) GetGlobalConfig() GlobalConfig {
 	return mgr.globalConfig
 }
 
+// GetBackupSettings returns the backup policy stored in the global configuration.
+//
+// Returns:
+//   - BackupSettings: Backup directory, retention limits and compression flag
+func (mgr *ConfigManager) GetBackupSettings() BackupSettings {
+	config := mgr.GetGlobalConfig()
+
+	return BackupSettings{
+		Dir:       config.BackupDir,
+		KeepCount: config.BackupKeepCount,
+		KeepDays:  config.BackupKeepDays,
+		Compress:  config.BackupCompress,
+	}
+}
+
 // SaveGlobalConfig updates and saves the global configuration using the typed configuration system.
 // This operation is thread-safe and will persist changes to disk.
 //
@@ -87,6 +106,10 @@ func (mgr *ConfigManager) SaveGlobalConfig(config GlobalConfig) error {
 	if mgr.cfg != nil {
 		mgr.cfg.Global.DatabasePath = config.DatabasePath
 		mgr.cfg.Global.Language = config.Language
+		mgr.cfg.Global.BackupDir = config.BackupDir
+		mgr.cfg.Global.BackupKeepCount = config.BackupKeepCount
+		mgr.cfg.Global.BackupKeepDays = config.BackupKeepDays
+		mgr.cfg.Global.BackupCompress = config.BackupCompress
 	}
 	mgr.mutex.Unlock()
 
@@ -232,6 +255,18 @@ func (mgr *ConfigManager) GetModuleCfg(moduleType string, moduleName string) (in
 		moduleConfig = mgr.cfg.Modules.DataDuplicator
 	case ModuleKeyFormatUpdater:
 		moduleConfig = mgr.cfg.Modules.FormatUpdater
+	case ModuleKeyDbAudit:
+		moduleConfig = mgr.cfg.Modules.DbAudit
+	case ModuleKeyPlaylistMirror:
+		moduleConfig = mgr.cfg.Modules.PlaylistMirror
+	case ModuleKeyTrackImporter:
+		moduleConfig = mgr.cfg.Modules.TrackImporter
+	case ModuleKeyRekordboxXml:
+		moduleConfig = mgr.cfg.Modules.RekordboxXml
+	case ModuleKeyArtistMerge:
+		moduleConfig = mgr.cfg.Modules.ArtistMerge
+	case ModuleKeyTagWriter:
+		moduleConfig = mgr.cfg.Modules.TagWriter
 	default:
 		return nil, fmt.Errorf("unknown module type: %s", moduleType)
 	}
@@ -287,6 +322,42 @@ func (mgr *ConfigManager) SaveModuleCfg(moduleType string, moduleName string, co
 		} else {
 			return fmt.Errorf("invalid configuration type for formatupdater")
 		}
+	case ModuleKeyDbAudit:
+		if cfg, ok := config.(DbAuditCfg); ok {
+			mgr.cfg.Modules.DbAudit = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for dbaudit")
+		}
+	case ModuleKeyPlaylistMirror:
+		if cfg, ok := config.(PlaylistMirrorCfg); ok {
+			mgr.cfg.Modules.PlaylistMirror = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for playlistmirror")
+		}
+	case ModuleKeyTrackImporter:
+		if cfg, ok := config.(TrackImporterCfg); ok {
+			mgr.cfg.Modules.TrackImporter = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for trackimporter")
+		}
+	case ModuleKeyRekordboxXml:
+		if cfg, ok := config.(RekordboxXmlCfg); ok {
+			mgr.cfg.Modules.RekordboxXml = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for rekordboxxml")
+		}
+	case ModuleKeyArtistMerge:
+		if cfg, ok := config.(ArtistMergeCfg); ok {
+			mgr.cfg.Modules.ArtistMerge = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for artistmerge")
+		}
+	case ModuleKeyTagWriter:
+		if cfg, ok := config.(TagWriterCfg); ok {
+			mgr.cfg.Modules.TagWriter = cfg
+		} else {
+			return fmt.Errorf("invalid configuration type for tagwriter")
+		}
 	default:
 		return fmt.Errorf("unknown module type: %s", moduleType)
 	}
@@ -316,6 +387,12 @@ func CreateCfgFile(cfgPath string) error {
 			FlacFixer:       FlacFixerCfg{},
 			DataDuplicator:  DataDuplicatorCfg{},
 			FormatUpdater:   FormatUpdaterCfg{},
+			DbAudit:         DbAuditCfg{},
+			PlaylistMirror:  PlaylistMirrorCfg{},
+			TrackImporter:   TrackImporterCfg{},
+			RekordboxXml:    RekordboxXmlCfg{},
+			ArtistMerge:     ArtistMergeCfg{},
+			TagWriter:       TagWriterCfg{},
 		},
 	}
 
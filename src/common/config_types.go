@@ -12,10 +12,14 @@ type Cfg struct {
 	Modules ModuleCfgs `json:"modules"`
 }
 
-// GlobalCfg contains global application settings, such as database path or preferred language.
+// GlobalCfg contains global application settings, such as database path, preferred language or backup policy.
 type GlobalCfg struct {
-	DatabasePath string `json:"DatabasePath"`
-	Language     string `json:"Language"`
+	DatabasePath    string `json:"DatabasePath"`
+	Language        string `json:"Language"`
+	BackupDir       string `json:"BackupDir"`
+	BackupKeepCount int    `json:"BackupKeepCount"`
+	BackupKeepDays  int    `json:"BackupKeepDays"`
+	BackupCompress  bool   `json:"BackupCompress"`
 }
 
 // FieldCfg defines the properties and value of a single Configuration field.
@@ -38,6 +42,12 @@ type ModuleCfgs struct {
 	FlacFixer       FlacFixerCfg       `json:"FlacFixer"`
 	DataDuplicator  DataDuplicatorCfg  `json:"DataDuplicator"`
 	FormatUpdater   FormatUpdaterCfg   `json:"FormatUpdater"`
+	DbAudit         DbAuditCfg         `json:"DbAudit"`
+	PlaylistMirror  PlaylistMirrorCfg  `json:"PlaylistMirror"`
+	TrackImporter   TrackImporterCfg   `json:"TrackImporter"`
+	RekordboxXml    RekordboxXmlCfg    `json:"RekordboxXml"`
+	ArtistMerge     ArtistMergeCfg     `json:"ArtistMerge"`
+	TagWriter       TagWriterCfg       `json:"TagWriter"`
 }
 
 // FormatConverterCfg defines all fields for the "Format Converter" module.
@@ -67,9 +77,20 @@ type DatesMasterCfg struct {
 
 // FlacFixerCfg defines all fields for the "Flac Fixer" module.
 type FlacFixerCfg struct {
-	SourceFolder FieldCfg `json:"sourceFolder"`
-	Recursive    FieldCfg `json:"recursive"`
-	Extensions   FieldCfg `json:"extensions"`
+	SourceFolder  FieldCfg `json:"sourceFolder"`
+	Recursive     FieldCfg `json:"recursive"`
+	Extensions    FieldCfg `json:"extensions"`
+	WriteLabel    FieldCfg `json:"writeLabel"`
+	WriteComposer FieldCfg `json:"writeComposer"`
+	WriteISRC     FieldCfg `json:"writeISRC"`
+	WriteRemixer  FieldCfg `json:"writeRemixer"`
+	WriteGenre    FieldCfg `json:"writeGenre"`
+	WriteTrackNo  FieldCfg `json:"writeTrackNo"`
+	WriteDiscNo   FieldCfg `json:"writeDiscNo"`
+	WriteArtwork  FieldCfg `json:"writeArtwork"`
+	FieldPolicies FieldCfg `json:"fieldPolicies"`
+	DateFallback  FieldCfg `json:"dateFallback"`
+	TagMapping    FieldCfg `json:"tagMapping"`
 }
 
 // DataDuplicatorCfg defines all fields for the "Data Duplicator" module.
@@ -87,3 +108,47 @@ type FormatUpdaterCfg struct {
 	Folder     FieldCfg `json:"folder"`
 	PlaylistID FieldCfg `json:"playlistID"`
 }
+
+// DbAuditCfg defines all fields for the "Database Audit" module.
+type DbAuditCfg struct {
+	CleanCues            FieldCfg `json:"cleanCues"`
+	CleanPlaylistEntries FieldCfg `json:"cleanPlaylistEntries"`
+	CleanAlbums          FieldCfg `json:"cleanAlbums"`
+	CleanArtists         FieldCfg `json:"cleanArtists"`
+}
+
+// PlaylistMirrorCfg defines all fields for the "Playlist Mirror" module.
+type PlaylistMirrorCfg struct {
+	SourcePlaylist FieldCfg `json:"sourcePlaylist"`
+	TargetFolder   FieldCfg `json:"targetFolder"`
+	TwinFolder     FieldCfg `json:"twinFolder"`
+	RemoveStale    FieldCfg `json:"removeStale"`
+}
+
+// TrackImporterCfg defines all fields for the "Track Importer" module.
+type TrackImporterCfg struct {
+	SourceFolder FieldCfg `json:"sourceFolder"`
+	Recursive    FieldCfg `json:"recursive"`
+}
+
+// RekordboxXmlCfg defines all fields for the "rekordbox XML" module.
+type RekordboxXmlCfg struct {
+	ExportAll      FieldCfg `json:"exportAll"`
+	ExportPlaylist FieldCfg `json:"exportPlaylist"`
+	ImportFile     FieldCfg `json:"importFile"`
+	ImportCues     FieldCfg `json:"importCues"`
+	ImportMetadata FieldCfg `json:"importMetadata"`
+}
+
+// ArtistMergeCfg defines all fields for the "Artist Merge" module.
+type ArtistMergeCfg struct {
+	Similarity FieldCfg `json:"similarity"`
+}
+
+// TagWriterCfg defines all fields for the "Tag Writer" module.
+type TagWriterCfg struct {
+	SourceType FieldCfg `json:"sourceType"`
+	Folder     FieldCfg `json:"folder"`
+	PlaylistID FieldCfg `json:"playlistID"`
+	Fields     FieldCfg `json:"fields"`
+}
@@ -21,6 +21,24 @@ const (
 
 	// ModuleKeyFormatConverter is the key for FormatConverter module
 	ModuleKeyFormatConverter = "FormatConverter"
+
+	// ModuleKeyDbAudit is the key for DbAudit module
+	ModuleKeyDbAudit = "DbAudit"
+
+	// ModuleKeyPlaylistMirror is the key for PlaylistMirror module
+	ModuleKeyPlaylistMirror = "PlaylistMirror"
+
+	// ModuleKeyTrackImporter is the key for TrackImporter module
+	ModuleKeyTrackImporter = "TrackImporter"
+
+	// ModuleKeyRekordboxXml is the key for RekordboxXml module
+	ModuleKeyRekordboxXml = "RekordboxXml"
+
+	// ModuleKeyArtistMerge is the key for ArtistMerge module
+	ModuleKeyArtistMerge = "ArtistMerge"
+
+	// ModuleKeyTagWriter is the key for TagWriter module
+	ModuleKeyTagWriter = "TagWriter"
 )
 
 // SourceTypes - Constants for data source types
@@ -77,8 +95,14 @@ const (
 	//FileNameFFmpegLog is the name of the ffmpeg log file
 	FileNameFFmpegLog = "metarekordfixer_ffmpeg.log"
 
+	// FileNameJournal is the name of the change journal, stored next to the configuration file
+	FileNameJournal = "metarekordfixer_journal.db"
+
 	//FolderNameLog is the name of the log folder
 	FolderNameLog = "log"
+
+	// FilePathFFprobe is the path of the bundled ffprobe executable
+	FilePathFFprobe = "tools/ffprobe.exe"
 )
 
 // ValidatorActions - Constants for validator actions
@@ -104,9 +128,6 @@ const (
 
 // SQLFragments - Constants for frequently used SQL query fragments
 const (
-	// SQLSelectMaxID is an SQL query fragment for getting the maximum ID
-	SQLSelectMaxID = "SELECT COALESCE(MAX(CAST(ID AS INTEGER)), 0) FROM"
-
 	// SQLTableDJMDContent is the name of the djmdContent table in the database
 	SQLTableDJMDContent = "djmdContent"
 
@@ -121,4 +142,13 @@ const (
 
 	// SQLTableDJMDAlbum is the name of the djmdAlbum table in the database
 	SQLTableDJMDAlbum = "djmdAlbum"
+
+	// SQLTableDJMDSongPlaylist is the name of the djmdSongPlaylist table in the database
+	SQLTableDJMDSongPlaylist = "djmdSongPlaylist"
+
+	// SQLTableDJMDGenre is the name of the djmdGenre table in the database
+	SQLTableDJMDGenre = "djmdGenre"
+
+	// SQLTableDJMDLabel is the name of the djmdLabel table in the database
+	SQLTableDJMDLabel = "djmdLabel"
 )
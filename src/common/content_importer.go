@@ -0,0 +1,275 @@
+// common/content_importer.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the importer registering new audio files in the rekordbox collection (djmdContent),
+// so they can be used by other modules without importing them in rekordbox first.
+
+package common
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+// ImportExtensions lists the extensions of audio files the importer registers.
+var ImportExtensions = []string{ExtensionMP3, ExtensionFLAC, ExtensionWAV, ExtensionAIFF, ExtensionM4A}
+
+// ImportSummary holds aggregated metrics of a folder import.
+type ImportSummary struct {
+	Total       int
+	Imported    int
+	Existing    int
+	SkippedZero int
+	ProbeErrs   int
+	SkippedDirs int
+}
+
+// GetFileType translates a file extension into a numeric identifier used in the database.
+// This identifier is stored in the FileType field of the djmdContent table.
+//
+// Parameters:
+//   - ext: The file extension including the dot (e.g., ".mp3")
+//
+// Returns:
+//   - An integer representing the file type in the database format
+func GetFileType(ext string) int {
+	switch strings.ToLower(ext) {
+	case ExtensionMP3:
+		return 1
+	case ExtensionM4A:
+		return 4
+	case ExtensionFLAC:
+		return 5
+	case ExtensionWAV:
+		return 11
+	case ExtensionAIFF:
+		return 12
+	default:
+		return 0
+	}
+}
+
+// AddOrGetGenre returns the ID of an existing genre with the given name, or records
+// the insertion of a new genre into the djmdGenre table in the changeset.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - genreName: The name of the genre to add or find
+//
+// Returns:
+//   - The ID of the genre (new or existing), empty if genreName is empty
+//   - An error if the database operation fails
+func AddOrGetGenre(cs *Changeset, genreName string) (string, error) {
+	return addOrGetNamedRow(cs, SQLTableDJMDGenre, genreName)
+}
+
+// AddOrGetLabel returns the ID of an existing label with the given name, or records
+// the insertion of a new label into the djmdLabel table in the changeset.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - labelName: The name of the label to add or find
+//
+// Returns:
+//   - The ID of the label (new or existing), empty if labelName is empty
+//   - An error if the database operation fails
+func AddOrGetLabel(cs *Changeset, labelName string) (string, error) {
+	return addOrGetNamedRow(cs, SQLTableDJMDLabel, labelName)
+}
+
+// addOrGetNamedRow returns the ID of the row of a lookup table (djmdGenre, djmdAlbum, ...) with the given name,
+// or records the insertion of a new row. Rows already recorded for insertion by the same changeset are reused.
+func addOrGetNamedRow(cs *Changeset, table, name string) (string, error) {
+	if name == "" {
+		return "", nil
+	}
+	dbMgr := cs.DB()
+
+	var id string
+	row := dbMgr.QueryRow(fmt.Sprintf("SELECT ID FROM %s WHERE Name = ? COLLATE NOCASE", table), name)
+	if row == nil {
+		return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	err := row.Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	if pendingID, ok := cs.FindPendingInsert(table, "Name", name); ok {
+		return pendingID, nil
+	}
+
+	dbMgr.logger.Info("%s prepared for insertion: %s", table, name)
+	return cs.InsertNew(table, name, FieldChange{Column: "Name", New: name})
+}
+
+// ImportTrack records the insertion of a djmdContent row for an audio file.
+// Technical properties are read with ffprobe, the title, artist, album, album artist and genre come from the file tags;
+// the artist, album and genre are linked to existing rows or created.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - filePath: The path to the audio file
+//
+// Returns:
+//   - The ID of the new track
+//   - An error if the file cannot be read or no ID can be allocated
+func ImportTrack(cs *Changeset, filePath string) (string, error) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	props, err := ProbeAudioFile(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := filepath.Base(filePath)
+	title := props.Tag("title")
+	if title == "" {
+		title = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	}
+
+	artistID, err := AddOrGetArtist(cs, props.Tag("artist"))
+	if err != nil {
+		return "", err
+	}
+	// Albums of the same name by different album artists are kept apart
+	albumArtistID, err := AddOrGetArtist(cs, props.Tag("album_artist", "albumartist", "album artist"))
+	if err != nil {
+		return "", err
+	}
+	albumID, err := AddOrGetAlbum(cs, props.Tag("album"), albumArtistID)
+	if err != nil {
+		return "", err
+	}
+	genreID, err := AddOrGetGenre(cs, props.Tag("genre"))
+	if err != nil {
+		return "", err
+	}
+
+	fields := []FieldChange{
+		{Column: "FolderPath", New: ToDbPath(filePath, false)},
+		{Column: "FileNameL", New: fileName},
+		{Column: "FileType", New: int64(GetFileType(filepath.Ext(filePath)))},
+		{Column: "FileSize", New: info.Size()},
+		{Column: "Length", New: int64(props.Length)},
+		{Column: "BitRate", New: int64(props.BitRate)},
+		{Column: "SampleRate", New: int64(props.SampleRate)},
+		{Column: "BitDepth", New: int64(props.BitDepth)},
+		{Column: "Title", New: title},
+		{Column: "StockDate", New: time.Now().Format("2006-01-02")},
+		{Column: "DateCreated", New: info.ModTime().Format("2006-01-02")},
+	}
+	if artistID != "" {
+		fields = append(fields, FieldChange{Column: "ArtistID", New: artistID})
+	}
+	if albumID != "" {
+		fields = append(fields, FieldChange{Column: "AlbumID", New: albumID})
+	}
+	if genreID != "" {
+		fields = append(fields, FieldChange{Column: "GenreID", New: genreID})
+	}
+
+	return cs.InsertNew(SQLTableDJMDContent, fileName, fields...)
+}
+
+// ImportFolder records the import of all audio files in a folder that are not in the collection yet.
+// Nothing is written to the database; the caller applies the changeset after the user reviews it.
+//
+// Parameters:
+//   - ctx: The context for cancellation
+//   - cs: The changeset collecting the changes
+//   - folderPath: The path to the folder containing the audio files
+//   - recursive: Whether to process subfolders recursively
+//   - onFilesFound: Callback invoked after counting files (can be nil)
+//   - onProgress: Callback invoked during processing with progress and counts (can be nil)
+//
+// Returns:
+//   - ImportSummary with counters
+//   - An error if the operation fails (fatal pre-processing errors only)
+func ImportFolder(
+	ctx context.Context,
+	cs *Changeset,
+	folderPath string,
+	recursive bool,
+	onFilesFound func(total int),
+	onProgress func(progress float64, imported int, total int),
+) (ImportSummary, error) {
+	dbMgr := cs.DB()
+
+	files, skippedDirs, err := GetFilesInFolder(dbMgr.logger, folderPath, ImportExtensions, recursive)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+
+	if onFilesFound != nil {
+		onFilesFound(len(files))
+	}
+
+	if len(files) == 0 {
+		return ImportSummary{}, errors.New(locales.Translate("common.err.nofiles"))
+	}
+
+	// Tracks already in the collection are skipped
+	tracks, err := dbMgr.GetExistingTracksInFolder(folderPath)
+	if err != nil {
+		return ImportSummary{}, err
+	}
+	known := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		known[NormalizePath(track.FolderPath)] = true
+	}
+
+	summary := ImportSummary{Total: len(files), SkippedDirs: len(skippedDirs)}
+	for i, file := range files {
+		select {
+		case <-ctx.Done():
+			return summary, ErrCancelled
+		default:
+		}
+
+		dbPath := ToDbPath(file, false)
+		if known[NormalizePath(dbPath)] {
+			summary.Existing++
+		} else if fi, statErr := os.Stat(file); statErr != nil || fi.Size() == 0 {
+			dbMgr.logger.Error("%s %s",
+				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(file)),
+				locales.Translate("common.log.iswrong"))
+			summary.SkippedZero++
+		} else if _, err := ImportTrack(cs, file); err != nil {
+			// Unreadable files are skipped, database errors stop the import
+			if !errors.Is(err, ErrAudioProbe) && !errors.Is(err, ErrNoAudioStream) {
+				return summary, err
+			}
+			dbMgr.logger.Warning("%s %s: %v",
+				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(file)),
+				locales.Translate("common.log.skipped"), err)
+			summary.ProbeErrs++
+		} else {
+			known[NormalizePath(dbPath)] = true
+			summary.Imported++
+			dbMgr.logger.Info("%s %s",
+				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(file)),
+				locales.Translate("common.log.dbinserted"))
+		}
+
+		if onProgress != nil {
+			onProgress(float64(i+1)/float64(len(files)), summary.Imported, summary.Total)
+		}
+	}
+
+	return summary, nil
+}
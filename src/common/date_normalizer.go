@@ -0,0 +1,122 @@
+// common/date_normalizer.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the normalization of release dates read from file tags to the YYYY-MM-DD form
+// rekordbox and the CDJs expect.
+
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+// Fallbacks for release dates which contain only a year or a year and a month.
+const (
+	// DateFallbackFirstDay completes incomplete dates with the first month and day (2019 → 2019-01-01)
+	DateFallbackFirstDay = "firstday"
+	// DateFallbackSkip does not write incomplete dates
+	DateFallbackSkip = "skip"
+)
+
+// DateFallbacks lists the available fallbacks for incomplete release dates.
+var DateFallbacks = []string{DateFallbackFirstDay, DateFallbackSkip}
+
+// ReleaseDateLayout is the form of release dates stored in the database.
+const ReleaseDateLayout = "2006-01-02"
+
+// fullDateLayouts lists the accepted forms of complete dates, including timestamps written by taggers.
+var fullDateLayouts = []string{
+	ReleaseDateLayout,
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+	"02.01.2006",
+	"2.1.2006",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+}
+
+// monthDateLayouts lists the accepted forms of dates without a day.
+var monthDateLayouts = []string{"2006-01", "2006/01", "01.2006", "01/2006"}
+
+// yearDateLayout is the form of dates which consist of a year only.
+const yearDateLayout = "2006"
+
+// DateIssue describes a release date tag which could not be normalized.
+type DateIssue struct {
+	File   string // Path to the audio file
+	Value  string // The raw tag value
+	Reason string // Localized reason
+}
+
+// NormalizeReleaseDate converts a release date tag value to the YYYY-MM-DD form.
+// Dates with only a year or a year and a month are completed or rejected according to the fallback.
+//
+// Parameters:
+//   - value: The raw tag value, e.g. "2019", "2019-03", "03.04.2019" or "2019-03-04T00:00:00Z"
+//   - fallback: One of the DateFallback constants, empty completes incomplete dates
+//
+// Returns:
+//   - The normalized date
+//   - A localized error if the value is not a date or is incomplete and the fallback is DateFallbackSkip
+func NormalizeReleaseDate(value string, fallback string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range fullDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.Format(ReleaseDateLayout), nil
+		}
+	}
+
+	incomplete := time.Time{}
+	for _, layout := range monthDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			incomplete = t
+			break
+		}
+	}
+	if incomplete.IsZero() && len(value) == len(yearDateLayout) {
+		if t, err := time.Parse(yearDateLayout, value); err == nil {
+			incomplete = t
+		}
+	}
+
+	if incomplete.IsZero() {
+		return "", fmt.Errorf(locales.Translate("common.err.dateinvalid"), value)
+	}
+	if fallback == DateFallbackSkip {
+		return "", fmt.Errorf(locales.Translate("common.err.dateincomplete"), value)
+	}
+	// time.Parse completes missing months and days with 1
+	return incomplete.Format(ReleaseDateLayout), nil
+}
+
+// NormalizeReleaseDateCandidates returns the first of several release date tag values which can be normalized.
+// The values are tried in order of tag preference, so an unparseable RELEASEDATE falls back to DATE or ORIGINALDATE.
+//
+// Parameters:
+//   - values: The raw tag values in order of preference
+//   - fallback: One of the DateFallback constants
+//
+// Returns:
+//   - The normalized date, empty if there are no values
+//   - The error of the first value if none of the values can be normalized
+func NormalizeReleaseDateCandidates(values []string, fallback string) (string, error) {
+	var firstErr error
+	for _, value := range values {
+		date, err := NormalizeReleaseDate(value, fallback)
+		if err == nil {
+			return date, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return "", firstErr
+}
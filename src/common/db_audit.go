@@ -0,0 +1,216 @@
+// common/db_audit.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the database audit: integrity check and detection of orphaned rows.
+
+package common
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// OrphanKind identifies a category of orphaned rows found by the audit.
+type OrphanKind string
+
+const (
+	// OrphanKindCue is a djmdCue row whose ContentID no longer exists
+	OrphanKindCue OrphanKind = "cue"
+
+	// OrphanKindPlaylistEntry is a djmdSongPlaylist row pointing at deleted content or a deleted playlist
+	OrphanKindPlaylistEntry OrphanKind = "playlistentry"
+
+	// OrphanKindAlbum is a djmdAlbum row not referenced by any track
+	OrphanKindAlbum OrphanKind = "album"
+
+	// OrphanKindArtist is a djmdArtist row not referenced by any track or album
+	OrphanKindArtist OrphanKind = "artist"
+)
+
+// OrphanKinds lists all orphan categories in the order they are reported and cleaned up.
+var OrphanKinds = []OrphanKind{OrphanKindCue, OrphanKindPlaylistEntry, OrphanKindAlbum, OrphanKindArtist}
+
+// orphanArtistColumns lists djmdContent columns that may reference an artist.
+// Only columns present in the inspected schema are used.
+var orphanArtistColumns = []string{"ArtistID", "OrgArtistID", "RemixerID", "ComposerID", "Lyricist"}
+
+// Orphan describes a single orphaned row.
+type Orphan struct {
+	Kind  OrphanKind
+	Table string
+	ID    string
+	Label string // Human readable description of the row
+}
+
+// AuditReport holds the results of a database audit.
+type AuditReport struct {
+	Integrity []string                // Problems reported by PRAGMA integrity_check, empty if the database is fine
+	Orphans   map[OrphanKind][]Orphan // Orphaned rows per category
+}
+
+// IntegrityOK reports whether the integrity check found no problems.
+func (r *AuditReport) IntegrityOK() bool {
+	return len(r.Integrity) == 0
+}
+
+// CheckIntegrity runs PRAGMA integrity_check on the connected database.
+//
+// Returns:
+//   - A slice of problems reported by SQLite, empty if the database is consistent
+//   - An error if the check cannot be run
+func (m *DBManager) CheckIntegrity() ([]string, error) {
+	rows, err := m.Query("PRAGMA integrity_check")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	problems, err := scanIntegrityResult(rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.auditintegrity"), err)
+	}
+	return problems, nil
+}
+
+// AuditDatabase checks the integrity of the database and looks for orphaned rows of all categories.
+//
+// Parameters:
+//   - dbMgr: Database manager of the audited database
+//   - isCancelled: Optional function reporting whether the user stopped the run
+//
+// Returns:
+//   - The audit report and nil if the audit completed
+//   - nil and ErrCancelled if the run was cancelled, or an error if a query fails
+func AuditDatabase(dbMgr *DBManager, isCancelled func() bool) (*AuditReport, error) {
+	report := &AuditReport{Orphans: make(map[OrphanKind][]Orphan)}
+
+	integrity, err := dbMgr.CheckIntegrity()
+	if err != nil {
+		return nil, err
+	}
+	report.Integrity = integrity
+
+	schema, err := InspectSchema(dbMgr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, kind := range OrphanKinds {
+		if isCancelled != nil && isCancelled() {
+			return nil, ErrCancelled
+		}
+		orphans, err := findOrphans(dbMgr, schema, kind)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.auditorphans"), err)
+		}
+		report.Orphans[kind] = orphans
+	}
+
+	return report, nil
+}
+
+// findOrphans returns all orphaned rows of one category.
+func findOrphans(dbMgr *DBManager, schema *SchemaReport, kind OrphanKind) ([]Orphan, error) {
+	var table, query string
+	switch kind {
+	case OrphanKindCue:
+		table = SQLTableDJMDCue
+		query = `
+			SELECT ID, COALESCE(ContentID, '')
+			FROM djmdCue
+			WHERE ContentID IS NULL OR ContentID NOT IN (SELECT ID FROM djmdContent)`
+	case OrphanKindPlaylistEntry:
+		table = "djmdSongPlaylist"
+		query = `
+			SELECT ID, COALESCE(PlaylistID, '') || ' / ' || COALESCE(ContentID, '')
+			FROM djmdSongPlaylist
+			WHERE ContentID IS NULL OR ContentID NOT IN (SELECT ID FROM djmdContent)
+			   OR PlaylistID IS NULL OR PlaylistID NOT IN (SELECT ID FROM djmdPlaylist)`
+	case OrphanKindAlbum:
+		table = SQLTableDJMDAlbum
+		query = `
+			SELECT ID, COALESCE(Name, '')
+			FROM djmdAlbum
+			WHERE ID NOT IN (SELECT AlbumID FROM djmdContent WHERE AlbumID IS NOT NULL)`
+	case OrphanKindArtist:
+		table = SQLTableDJMDArtist
+		// Artists may be referenced by several track columns and by albums
+		// A missing column is logged, artists referenced only by it would be reported as orphans
+		var references []string
+		for _, column := range orphanArtistColumns {
+			if !schema.HasColumn(SQLTableDJMDContent, column) {
+				dbMgr.logger.Warning("Artist reference column %s.%s not found in database schema, skipping it", SQLTableDJMDContent, column)
+				continue
+			}
+			references = append(references, fmt.Sprintf("SELECT %s FROM djmdContent WHERE %s IS NOT NULL", column, column))
+		}
+		if schema.HasColumn(SQLTableDJMDAlbum, "AlbumArtistID") {
+			references = append(references, "SELECT AlbumArtistID FROM djmdAlbum WHERE AlbumArtistID IS NOT NULL")
+		} else {
+			dbMgr.logger.Warning("Artist reference column %s.%s not found in database schema, skipping it", SQLTableDJMDAlbum, "AlbumArtistID")
+		}
+		if len(references) == 0 {
+			return nil, nil
+		}
+		query = fmt.Sprintf(`
+			SELECT ID, COALESCE(Name, '')
+			FROM djmdArtist
+			WHERE ID NOT IN (%s)`, strings.Join(references, " UNION "))
+	default:
+		return nil, fmt.Errorf("unknown orphan kind: %s", kind)
+	}
+
+	rows, err := dbMgr.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orphans []Orphan
+	for rows.Next() {
+		var id sql.NullString
+		var label string
+		if err := rows.Scan(&id, &label); err != nil {
+			return nil, err
+		}
+		if !id.Valid {
+			continue
+		}
+		orphans = append(orphans, Orphan{Kind: kind, Table: table, ID: id.String, Label: label})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return orphans, nil
+}
+
+// scanIntegrityResult reads the rows returned by PRAGMA integrity_check and returns all reported problems.
+func scanIntegrityResult(rows *sql.Rows) ([]string, error) {
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, err
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return problems, nil
+}
+
+// integrityError converts integrity check problems into an error, nil if there are none.
+func integrityError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "; "))
+}
@@ -13,7 +13,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"sync"
-	"time"
 
 	"MetaRekordFixer/locales"
 	"strings"
@@ -31,18 +30,53 @@ func getDbPassword() string {
 	return dbPassword
 }
 
+// cipherConnString builds the SQLCipher connection string used to open a Rekordbox database file.
+//
+// Parameters:
+//   - path: Path to the encrypted database file
+//
+// Returns:
+//   - The connection string including the encryption key and cipher settings
+func cipherConnString(path string) string {
+	return fmt.Sprintf("file:%s?_pragma_key=%s&_pragma_cipher_compatibility=3&_pragma_cipher_page_size=4096", path, getDbPassword())
+}
+
+// readOnlyConnString builds the connection string of a read-only connection,
+// which can neither modify nor lock the database file for writing.
+//
+// Parameters:
+//   - path: Path to the encrypted database file
+//
+// Returns:
+//   - The connection string including the encryption key, cipher settings and read-only mode
+func readOnlyConnString(path string) string {
+	return cipherConnString(path) + "&mode=ro"
+}
+
 // DBManager provides unified database access for all modules in the application.
 // It handles encrypted Rekordbox database connections, transactions, and query execution
 // while providing error handling, logging, and thread safety through mutex locking.
 type DBManager struct {
-	db              *sql.DB       // database connection
-	dbPath          string        // path to the database file
-	isConnected     bool          // whether the connection is established
-	mutex           sync.Mutex    // mutex for thread safety
-	logger          *Logger       // logger for recording operations
-	errorHandler    *ErrorHandler // handler for database errors
-	useTransactions bool          // whether to use transactions
-	finalized       bool          // whether the manager has been finalized
+	db           *sql.DB        // database connection
+	dbPath       string         // path to the database file
+	isConnected  bool           // whether the connection is established
+	mutex        sync.Mutex     // mutex for thread safety
+	logger       *Logger        // logger for recording operations
+	errorHandler *ErrorHandler  // handler for database errors
+	tx           *sql.Tx        // active write session, nil when none is open
+	finalized    bool           // whether the manager has been finalized
+	backupCfg    BackupSettings // backup policy applied by BackupDatabase
+	readOnly     bool           // whether the current connection was opened read-only
+	writeEnabled bool           // whether the next write session may upgrade to a writable connection
+	journal      *ChangeJournal // journal recording applied changesets, nil disables journaling
+}
+
+// sqlExecutor is the common subset of *sql.DB and *sql.Tx used by DBManager.
+// It allows Execute, Query and QueryRow to run inside an active write session transparently.
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
 // NewDBManager creates a new database manager instance for the specified database path.
@@ -65,12 +99,11 @@ func NewDBManager(dbPath string, logger *Logger, errorHandler *ErrorHandler) (*D
 	}
 
 	manager := &DBManager{
-		dbPath:          dbPath,
-		isConnected:     false,
-		logger:          logger,
-		errorHandler:    errorHandler,
-		useTransactions: false,
-		finalized:       false,
+		dbPath:       dbPath,
+		isConnected:  false,
+		logger:       logger,
+		errorHandler: errorHandler,
+		finalized:    false,
 	}
 
 	if manager.logger == nil {
@@ -80,12 +113,14 @@ func NewDBManager(dbPath string, logger *Logger, errorHandler *ErrorHandler) (*D
 	return manager, nil
 }
 
-// Connect establishes a connection to the encrypted Rekordbox database.
+// Connect establishes a read-only connection to the encrypted Rekordbox database.
+// All browsing and preview work (loading playlists, counting rows, filling a changeset) runs on this connection,
+// so opening a module can never modify or lock the library file. BeginSession upgrades the connection
+// to a writable one once writes were enabled by EnableWrites after the backup.
 // This method performs several validation steps:
 // 1. Checks if the database path is set
 // 2. Verifies the database file exists and is not empty
 // 3. Attempts to open the database with the encryption key
-// 4. Sets pragmas to optimize database performance
 //
 // The method is thread-safe through mutex locking and handles various error conditions
 // with specific localized error messages.
@@ -101,6 +136,20 @@ func (m *DBManager) Connect() error {
 		return nil
 	}
 
+	return m.open(true)
+}
+
+// open opens the database connection in the requested mode. The caller must hold the mutex.
+// Writable connections additionally set pragmas to disable WAL mode and to make commits durable.
+//
+// Parameters:
+//   - readOnly: Whether the connection is opened read-only
+//
+// Returns:
+//   - nil if the connection is successful
+//   - An error with context if any step fails
+func (m *DBManager) open(readOnly bool) error {
+
 	// Check if database path is set
 	if m.dbPath == "" {
 		return errors.New(locales.Translate("common.err.dbpath"))
@@ -120,8 +169,11 @@ func (m *DBManager) Connect() error {
 		return errors.New(locales.Translate("common.err.dbzerolength"))
 	}
 
-	connStr := fmt.Sprintf("file:%s?_pragma_key=%s&_pragma_cipher_compatibility=3&_pragma_cipher_page_size=4096", m.dbPath, getDbPassword())
-	db, err := sql.Open("sqlite3", connStr)
+	connString := cipherConnString(m.dbPath)
+	if readOnly {
+		connString = readOnlyConnString(m.dbPath)
+	}
+	db, err := sql.Open("sqlite3", connString)
 	if err != nil {
 		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbopen"), err)
 	}
@@ -139,26 +191,79 @@ func (m *DBManager) Connect() error {
 		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbconnect"), err)
 	}
 
-	// Set pragmas to disable WAL mode and optimize performance
-	_, err = db.Exec("PRAGMA journal_mode=DELETE")
-	if err != nil {
-		db.Close()
-		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbpragma"), err)
-	}
+	if !readOnly {
+		// Set pragmas to disable WAL mode and optimize performance
+		_, err = db.Exec("PRAGMA journal_mode=DELETE")
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.dbpragma"), err)
+		}
 
-	_, err = db.Exec("PRAGMA synchronous=FULL")
-	if err != nil {
-		db.Close()
-		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbpragmasync"), err)
+		_, err = db.Exec("PRAGMA synchronous=FULL")
+		if err != nil {
+			db.Close()
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.dbpragmasync"), err)
+		}
 	}
 
 	m.db = db
+	m.readOnly = readOnly
 	m.isConnected = true
-	m.logger.Info("Connected to database: %s", m.dbPath)
+	m.finalized = false
+	if readOnly {
+		m.logger.Info("Connected to database (read-only): %s", m.dbPath)
+	} else {
+		m.logger.Info("Connected to database (read-write): %s", m.dbPath)
+	}
 
 	return nil
 }
 
+// EnableWrites allows the next write session to upgrade to a writable connection.
+// It is called after the database backup has been created; the permission is consumed
+// by the next Changeset.Apply or withdrawn by DisableWrites or Finalize, so every write session needs a fresh backup.
+func (m *DBManager) EnableWrites() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.writeEnabled = true
+}
+
+// DisableWrites withdraws the permission granted by EnableWrites without a write session,
+// so a later write session cannot reuse a backup made for another run.
+func (m *DBManager) DisableWrites() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.writeEnabled = false
+}
+
+// TakeWrites withdraws the permission granted by EnableWrites and reports whether it was granted.
+// It lets the review of changes hold the permission of its run while the connection is finalized,
+// the permission is granted again with EnableWrites when the changes are applied.
+//
+// Returns:
+//   - true if writes were enabled
+func (m *DBManager) TakeWrites() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	enabled := m.writeEnabled
+	m.writeEnabled = false
+	return enabled
+}
+
+// IsReadOnly reports whether the current connection is read-only.
+//
+// Returns:
+//   - true if the connection is open and read-only, false if it is writable or closed
+func (m *DBManager) IsReadOnly() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.isConnected && m.readOnly
+}
+
 // EnsureConnected ensures the database connection is active before performing operations.
 // If skipConnect is false and the database is not connected, it will attempt to connect.
 // If skipConnect is true and the database is not connected, it will return an error.
@@ -200,7 +305,7 @@ func (m *DBManager) Execute(query string, args ...interface{}) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	_, execErr := m.db.Exec(query, args...)
+	_, execErr := m.executor().Exec(query, args...)
 	if execErr != nil {
 		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbqueryexec"), execErr)
 	}
@@ -228,7 +333,7 @@ func (m *DBManager) Query(query string, args ...interface{}) (*sql.Rows, error)
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	rows, queryErr := m.db.Query(query, args...)
+	rows, queryErr := m.executor().Query(query, args...)
 	if queryErr != nil {
 		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbquery"), queryErr)
 	}
@@ -256,12 +361,171 @@ func (m *DBManager) QueryRow(query string, args ...interface{}) *sql.Row {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	return m.db.QueryRow(query, args...)
+	return m.executor().QueryRow(query, args...)
+}
+
+// executor returns the active write session if one is open, otherwise the plain connection.
+// The caller must hold the mutex.
+func (m *DBManager) executor() sqlExecutor {
+	if m.tx != nil {
+		return m.tx
+	}
+	return m.db
+}
+
+// BeginSession opens a write session backed by a single database transaction.
+// A read-only connection is replaced by a writable one first, which requires a prior EnableWrites call.
+// All subsequent Execute, Query and QueryRow calls run inside this transaction
+// until Commit or Rollback is called. Only one session can be active at a time.
+//
+// Returns:
+//   - nil if the session was started
+//   - An error if the database is not connected, writes were not enabled, a session is already active,
+//     or the transaction cannot be started
+func (m *DBManager) BeginSession() error {
+	err := m.EnsureConnected(false)
+	if err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.tx != nil {
+		return fmt.Errorf(locales.Translate("common.err.dbtxactive"), m.dbPath)
+	}
+
+	// Writing is only allowed after the backup was created, also on a connection left writable by an earlier session
+	if !m.writeEnabled {
+		return fmt.Errorf(locales.Translate("common.err.dbreadonly"), m.dbPath)
+	}
+
+	if m.readOnly {
+		if err := m.db.Close(); err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.dbclosefinal"), err)
+		}
+		m.isConnected = false
+		if err := m.open(false); err != nil {
+			return err
+		}
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbtxbegin"), err)
+	}
+
+	m.tx = tx
+	m.logger.Info("Database write session started: %s", m.dbPath)
+	return nil
+}
+
+// Commit makes all changes of the active write session permanent and closes the session.
+//
+// Returns:
+//   - nil if the changes were committed
+//   - An error if no session is active or the commit fails (the session is closed in both cases)
+func (m *DBManager) Commit() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.tx == nil {
+		return fmt.Errorf(locales.Translate("common.err.dbtxnoactive"), m.dbPath)
+	}
+
+	err := m.tx.Commit()
+	m.tx = nil
+	m.writeEnabled = false
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbtxcommit"), err)
+	}
+
+	m.logger.Info("Database write session committed: %s", m.dbPath)
+	return nil
+}
+
+// Rollback discards all changes of the active write session and closes the session.
+// It is safe to call when no session is active, so it can be deferred right after BeginSession.
+//
+// Returns:
+//   - nil if the changes were discarded or no session was active
+//   - An error if the rollback fails
+func (m *DBManager) Rollback() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.tx == nil {
+		return nil
+	}
+
+	err := m.tx.Rollback()
+	m.tx = nil
+	m.writeEnabled = false
+	if err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbtxrollback"), err)
+	}
+
+	m.logger.Info("Database write session rolled back: %s", m.dbPath)
+	return nil
+}
+
+// InSession reports whether a write session is currently active.
+//
+// Returns:
+//   - true if BeginSession was called and the session has not been committed or rolled back yet
+func (m *DBManager) InSession() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.tx != nil
+}
+
+// SetBackupSettings sets the backup policy used by BackupDatabase and BackupManager.
+//
+// Parameters:
+//   - settings: Backup directory, retention limits and compression flag
+func (m *DBManager) SetBackupSettings(settings BackupSettings) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.backupCfg = settings
+}
+
+// SetJournal sets the change journal that records all changesets applied to this database.
+//
+// Parameters:
+//   - journal: The change journal, nil disables journaling
+func (m *DBManager) SetJournal(journal *ChangeJournal) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.journal = journal
+}
+
+// Journal returns the change journal of the database, or nil if journaling is disabled.
+func (m *DBManager) Journal() *ChangeJournal {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.journal
+}
+
+// BackupManager returns a backup manager for the current database path and backup policy.
+//
+// Returns:
+//   - A new BackupManager instance
+func (m *DBManager) BackupManager() *BackupManager {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return NewBackupManager(m.dbPath, m.backupCfg, m.logger)
 }
 
 // BackupDatabase creates a backup of the database.
-// This method creates a timestamped copy of the database file in the same directory.
+// This method closes the connection, creates a timestamped copy of the database file
+// in the configured backup directory and then removes backups outside the retention policy.
 // It performs validation checks on the database path before attempting the backup.
+// A failure to prune old backups is logged but does not fail the backup.
 //
 // Returns:
 //   - nil if the backup was successful
@@ -284,70 +548,58 @@ func (m *DBManager) BackupDatabase() error {
 		return fmt.Errorf(locales.Translate("common.err.dbclose"), err)
 	}
 
-	// Generate the backup file name with the current timestamp
-	backupFileName := fmt.Sprintf("master_backup_%s.db", time.Now().Format("2006-01-02@15_04_05"))
-	backupPath := filepath.Join(filepath.Dir(m.dbPath), backupFileName)
+	backupMgr := m.BackupManager()
+	if _, err := backupMgr.Create(); err != nil {
+		return err
+	}
 
-	// Copy the database file to the backup location
-	err := CopyFile(m.dbPath, backupPath)
-	if err != nil {
-		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbbackup"), err)
+	// Apply the retention policy, the new backup itself is never removed
+	if removed, err := backupMgr.Prune(); err != nil {
+		m.logger.Warning("Failed to prune old database backups: %v", err)
+	} else if len(removed) > 0 {
+		m.logger.Info("Removed %d old database backups", len(removed))
 	}
 
-	m.logger.Info("Database backup created: %s", backupPath)
 	return nil
 }
 
-// GetPlaylists loads all playlists from the database with their hierarchical structure.
-// This method retrieves playlist information including ID, name, parent ID, and full path.
-// The path is constructed to show the playlist hierarchy (e.g., "Parent > Child").
-// Results are ordered by playlist sequence for proper hierarchical display.
+// RestoreBackup replaces the database file with the given backup.
+// The connection is closed first and the current database file is kept as a safety copy
+// in the backup directory. The next database call reconnects to the restored file.
+//
+// Parameters:
+//   - backup: The backup to restore
 //
 // Returns:
-//   - A slice of PlaylistItem structures and nil if successful
-//   - nil and an error if the database is not connected or the query fails
-func (m *DBManager) GetPlaylists() ([]PlaylistItem, error) {
-	err := m.EnsureConnected(false)
-	if err != nil {
-		return nil, err // EnsureConnected (and thus Connect) already provides a localized error.
+//   - The path of the safety copy and nil if the restore was successful
+//   - An empty string and an error if the connection cannot be closed or the restore fails
+func (m *DBManager) RestoreBackup(backup BackupInfo) (string, error) {
+	if err := m.Finalize(); err != nil {
+		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.backuprestore"), err)
 	}
 
-	query := `
-        SELECT p1.ID, p1.Name, p1.ParentID,
-        CASE
-            WHEN p2.Name IS NOT NULL THEN p2.Name || ' > ' || p1.Name
-            ELSE p1.Name
-        END as Path
-        FROM djmdPlaylist p1
-        LEFT JOIN djmdPlaylist p2 ON p1.ParentID = p2.ID
-        ORDER BY
-            CASE WHEN p2.ID IS NULL THEN p1.Seq ELSE p2.Seq END,
-            CASE WHEN p2.ID IS NULL THEN 0 ELSE p1.Seq + 1 END
-    `
+	return m.BackupManager().Restore(backup)
+}
 
-	rows, err := m.Query(query)
+// GetPlaylists loads all folders, playlists and smart playlists from the database as a flat list.
+// The list follows the playlist tree depth-first in Seq order, every item carries its full path
+// (e.g., "Folder > Subfolder > Playlist"), type, depth and track count.
+//
+// Returns:
+//   - A slice of PlaylistItem structures and nil if successful
+//   - nil and an error if the database is not connected or the query fails
+func (m *DBManager) GetPlaylists() ([]PlaylistItem, error) {
+	tree, err := m.GetPlaylistTree()
 	if err != nil {
-		return nil, fmt.Errorf(locales.Translate("common.err.playlistload"), err)
-	}
-	defer rows.Close()
-
-	var playlists []PlaylistItem
-	for rows.Next() {
-		var playlist PlaylistItem
-		err := rows.Scan(&playlist.ID, &playlist.Name, &playlist.ParentID, &playlist.Path)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
-		}
-		playlists = append(playlists, playlist)
+		return nil, err
 	}
-
-	return playlists, nil
+	return tree.Items(), nil
 }
 
 // Finalize ensures the database connection is properly closed.
 // This method should be called when the DBManager is no longer needed to release
 // database resources and prevent connection leaks. It is thread-safe through mutex locking
-// and idempotent (can be called multiple times safely).
+// and idempotent (can be called multiple times safely). The permission granted by EnableWrites is withdrawn.
 //
 // Returns:
 //   - nil if the connection was successfully closed or was already closed
@@ -356,6 +608,9 @@ func (m *DBManager) Finalize() error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
+	// The write permission belongs to the run which ends here
+	m.writeEnabled = false
+
 	if m.finalized {
 		return nil
 	}
@@ -365,22 +620,34 @@ func (m *DBManager) Finalize() error {
 		return nil
 	}
 
-	// Force synchronization before closing - helps with removing .db-shm and .db-wal files
-	_, err := m.db.Exec("PRAGMA wal_checkpoint(FULL)")
-	if err != nil {
-		m.logger.Info("Warning: Failed to execute WAL checkpoint: %v", err)
-		// Continue despite error
+	// Never close the connection with a pending write session, uncommitted changes are discarded
+	if m.tx != nil {
+		if err := m.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			m.logger.Warning("Failed to roll back pending write session: %v", err)
+		}
+		m.tx = nil
+		m.logger.Warning("Pending database write session rolled back before closing: %s", m.dbPath)
 	}
 
-	// Optimize the database to clean up prepared statements
-	_, err = m.db.Exec("PRAGMA optimize")
-	if err != nil {
-		m.logger.Info("Warning: Failed to optimize database: %v", err)
-		// Continue despite error
+	// A read-only connection has nothing to synchronize and must not write to the file
+	if !m.readOnly {
+		// Force synchronization before closing - helps with removing .db-shm and .db-wal files
+		_, err := m.db.Exec("PRAGMA wal_checkpoint(FULL)")
+		if err != nil {
+			m.logger.Info("Warning: Failed to execute WAL checkpoint: %v", err)
+			// Continue despite error
+		}
+
+		// Optimize the database to clean up prepared statements
+		_, err = m.db.Exec("PRAGMA optimize")
+		if err != nil {
+			m.logger.Info("Warning: Failed to optimize database: %v", err)
+			// Continue despite error
+		}
 	}
 
 	// Close the database connection
-	err = m.db.Close()
+	err := m.db.Close()
 	if err != nil {
 		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbclosefinal"), err)
 	}
@@ -403,6 +670,30 @@ func (m *DBManager) Finalize() error {
 //   - A slice of TrackItem structures and nil if successful
 //   - nil and an error if the database is not connected, the query fails, or no tracks are found
 func (m *DBManager) GetTracksBasedOnFolder(folderPath string) ([]TrackItem, error) {
+	tracks, err := m.GetExistingTracksInFolder(folderPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tracks) == 0 {
+		folderName := filepath.Base(folderPath)
+		return nil, fmt.Errorf(locales.Translate("common.err.dbfoldermatch"), folderName)
+	}
+
+	return tracks, nil
+}
+
+// GetExistingTracksInFolder retrieves all tracks from a specific folder like GetTracksBasedOnFolder,
+// but a folder without tracks in the database is not an error. It is used to find the files
+// of a folder which are already in the collection, e.g. before importing new files.
+//
+// Parameters:
+//   - folderPath: The filesystem path of the folder to search for tracks
+//
+// Returns:
+//   - A slice of TrackItem structures, empty if no tracks are found, and nil if successful
+//   - nil and an error if the database is not connected or the query fails
+func (m *DBManager) GetExistingTracksInFolder(folderPath string) ([]TrackItem, error) {
 	err := m.EnsureConnected(false)
 	if err != nil {
 		return nil, fmt.Errorf(locales.Translate("common.err.dbconnect"), err)
@@ -452,17 +743,14 @@ func (m *DBManager) GetTracksBasedOnFolder(folderPath string) ([]TrackItem, erro
 		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbrowsiteration"), err)
 	}
 
-	if len(tracks) == 0 {
-		folderName := filepath.Base(folderPath)
-		return nil, fmt.Errorf(locales.Translate("common.err.dbfoldermatch"), folderName)
-	}
-
 	return tracks, nil
 }
 
 // GetTracksBasedOnPlaylist retrieves all tracks from a specific playlist in the Rekordbox database.
 // This method joins the djmdContent and djmdSongPlaylist tables to find tracks associated
-// with the specified playlist ID. Results are ordered by filename.
+// with the specified playlist ID. For smart playlists the tracks matching the conditions
+// stored in djmdPlaylist.SmartList are returned instead, without tracks deleted from the collection.
+// Results are ordered by filename.
 //
 // Parameters:
 //   - playlistID: The unique identifier of the playlist to retrieve tracks from
@@ -490,8 +778,39 @@ func (m *DBManager) GetTracksBasedOnPlaylist(playlistID string) ([]TrackItem, er
         WHERE sp.PlaylistID = ?
         ORDER BY c.FileNameL
     `
+	args := []interface{}{playlistID}
 
-	rows, err := m.Query(query, playlistID)
+	// Smart playlists have no entries in djmdSongPlaylist, their tracks are defined by conditions
+	var attribute NullInt64
+	row := m.QueryRow("SELECT Attribute FROM djmdPlaylist WHERE ID = ?", playlistID)
+	if row == nil {
+		return nil, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), m.GetDatabasePath())
+	}
+	if err := row.Scan(&attribute); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
+	}
+	if PlaylistType(attribute.Int64) == PlaylistTypeSmart {
+		where, whereArgs, err := m.smartListWhere(playlistID)
+		if err != nil {
+			return nil, err
+		}
+		query = `
+        SELECT
+            c.ID,
+            c.FolderPath,
+            c.FileNameL,
+            c.StockDate,
+            c.DateCreated,
+            c.ColorID,
+            c.DJPlayCount
+        FROM djmdContent c
+        WHERE ` + where + `
+        ORDER BY c.FileNameL
+    `
+		args = whereArgs
+	}
+
+	rows, err := m.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tracks in playlist: %w", err)
 	}
@@ -534,17 +853,8 @@ func (m *DBManager) GetTrackHotCues(trackID string) ([]map[string]interface{}, e
 		return nil, fmt.Errorf(locales.Translate("common.err.dbconnect"), err)
 	}
 
-	query := `
-        SELECT 
-            ID, ContentID, InMsec, InFrame, InMpegFrame, InMpegAbs, 
-            OutMsec, OutFrame, OutMpegFrame, OutMpegAbs, 
-            Kind, Color, ColorTableIndex, ActiveLoop, Comment, 
-            BeatLoopSize, CueMicrosec, InPointSeekInfo, OutPointSeekInfo, 
-            ContentUUID, UUID, rb_data_status, rb_local_data_status, 
-            rb_local_deleted, rb_local_synced
-        FROM djmdCue
-        WHERE ContentID = ?
-    `
+	// The column list is shared with the schema profile, so a schema change is detected before any write
+	query := fmt.Sprintf("SELECT %s FROM djmdCue WHERE ContentID = ?", strings.Join(CueColumns, ", "))
 
 	rows, err := m.Query(query, trackID)
 	if err != nil {
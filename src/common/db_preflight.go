@@ -0,0 +1,125 @@
+// common/db_preflight.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the preflight check detecting a running rekordbox or another holder
+// of the database before anything is written.
+
+package common
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"MetaRekordFixer/locales"
+)
+
+// PreflightLockTimeout is how long the preflight check waits for an exclusive lock of the database.
+const PreflightLockTimeout = 2 * time.Second
+
+// rekordboxProcessNames lists the executable names of rekordbox itself (lowercase, without path).
+// The background rekordboxAgent is not listed, it does not modify the library while rekordbox is closed.
+var rekordboxProcessNames = []string{"rekordbox", "rekordbox.exe"}
+
+// databaseSidecarSuffixes lists the SQLite files that exist next to the database while a connection
+// has unfinished writes (rollback journal) or the database is open in WAL mode.
+var databaseSidecarSuffixes = []string{"-journal", "-wal"}
+
+// ProcessLister returns the names of the running processes.
+// It is pluggable, so the preflight check can use a platform specific implementation or be disabled.
+type ProcessLister func() ([]string, error)
+
+// DefaultProcessLister lists running processes with the tools of the operating system.
+var DefaultProcessLister ProcessLister = listProcessNames
+
+// CheckNotInUse verifies that no other program holds the database, so a write session cannot collide
+// with rekordbox or end with lock errors mid-run. Three checks are performed:
+// the rekordbox process is looked up (if a process lister is given), leftover journal and WAL files
+// are detected and an exclusive lock of the database is attempted with a timeout.
+// Nothing is written by the check; the exclusive lock is released immediately.
+//
+// Parameters:
+//   - timeout: How long to wait for the exclusive lock
+//   - lister: Function listing running processes, nil skips the process check
+//
+// Returns:
+//   - nil if the database is not in use
+//   - A localized error explaining what holds the database
+func (m *DBManager) CheckNotInUse(timeout time.Duration, lister ProcessLister) error {
+	if m.InSession() {
+		return fmt.Errorf(locales.Translate("common.err.dbtxactive"), m.dbPath)
+	}
+
+	if lister != nil {
+		names, err := lister()
+		if err != nil {
+			// Not being able to list processes must not block the user, the lock check still runs
+			m.logger.Warning("Failed to list running processes: %v", err)
+		}
+		for _, name := range names {
+			name = strings.ToLower(filepath.Base(strings.TrimSpace(name)))
+			for _, rekordbox := range rekordboxProcessNames {
+				if name == rekordbox {
+					return fmt.Errorf(locales.Translate("common.err.dbinuseprocess"), name)
+				}
+			}
+		}
+	}
+
+	for _, suffix := range databaseSidecarSuffixes {
+		sidecar := m.dbPath + suffix
+		if info, err := os.Stat(sidecar); err == nil && info.Size() > 0 {
+			return fmt.Errorf(locales.Translate("common.err.dbinusesidecar"), filepath.Base(sidecar))
+		}
+	}
+
+	// Our own idle connection holds no lock, but it is closed so the probe is the only connection of this process
+	if err := m.Finalize(); err != nil {
+		return err
+	}
+
+	if err := probeExclusiveLock(m.dbPath, timeout); err != nil {
+		m.logger.Warning("Exclusive lock of the database failed: %v", err)
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbinuselock"), err)
+	}
+
+	return nil
+}
+
+// probeExclusiveLock opens a separate connection, takes an exclusive lock of the database and releases it again.
+// If another connection holds a lock, SQLite retries until the timeout expires.
+//
+// Parameters:
+//   - path: Path to the encrypted database file
+//   - timeout: How long to wait for the lock
+//
+// Returns:
+//   - nil if the lock could be taken
+//   - An error if the database is locked or cannot be opened
+func probeExclusiveLock(path string, timeout time.Duration) error {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("%s&_busy_timeout=%d", cipherConnString(path), timeout.Milliseconds()))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+time.Second)
+	defer cancel()
+
+	// BEGIN and ROLLBACK must run on the same connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN EXCLUSIVE"); err != nil {
+		return err
+	}
+	_, err = conn.ExecContext(ctx, "ROLLBACK")
+	return err
+}
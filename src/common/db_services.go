@@ -14,12 +14,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
-	"time"
 
 	"MetaRekordFixer/locales"
-
-	"github.com/dhowden/tag"
 )
 
 // ErrCancelled is a sentinel error used for programmatic cancellation flow control.
@@ -31,149 +31,106 @@ import (
 var ErrCancelled = errors.New("operation cancelled")
 
 // ReadMetadataFromFile reads metadata from an audio file using the github.com/dhowden/tag library.
-// It supports reading metadata from different audio formats, currently focusing on FLAC.
+// The format selects the tag containers to read: Vorbis comments for FLAC, ID3v2 frames for MP3,
+// iTunes atoms for M4A and the ID3 chunk and RIFF INFO list for WAV and AIFF.
+// Each field of TagFieldKeys is looked up under the keys of these containers.
 //
 // Parameters:
 //   - filePath: The path to the audio file
-//   - format: The format of the audio file (e.g., "FLAC", "MP3")
+//   - format: The format of the audio file (e.g., "FLAC", "MP3"), empty to detect it from the extension
 //
 // Returns:
-//   - A map of metadata key-value pairs
+//   - A map of metadata key-value pairs (e.g., "ALBUMARTIST"), fields not present in the file are omitted
 //   - An error if the file cannot be read or parsed
 func ReadMetadataFromFile(filePath string, format string) (map[string]string, error) {
-	// Open the file
-	file, err := os.Open(filePath)
+	candidates, err := ReadMetadataCandidates(filePath, format)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	// Read metadata using github.com/dhowden/tag
-	metadata, err := tag.ReadFrom(file)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.metadataread"), err)
-	}
-
-	// Extract metadata into a map
-	metadataMap := make(map[string]string)
 
-	// Get all fields from Raw() map for consistency
-	rawData := metadata.Raw()
-	if rawData != nil {
-		if album, ok := rawData["album"]; ok {
-			if str, ok := album.(string); ok {
-				metadataMap["ALBUM"] = str
-			}
-		}
-		if albumArtist, ok := rawData["albumartist"]; ok {
-			if str, ok := albumArtist.(string); ok {
-				metadataMap["ALBUMARTIST"] = str
-			}
-		}
-		if origArtist, ok := rawData["origartist"]; ok {
-			if str, ok := origArtist.(string); ok {
-				metadataMap["ORIGARTIST"] = str
-			}
-		}
-		if releaseDate, ok := rawData["releasedate"]; ok {
-			if str, ok := releaseDate.(string); ok {
-				metadataMap["RELEASEDATE"] = str
-			}
-		}
-		if subtitle, ok := rawData["subtitle"]; ok {
-			if str, ok := subtitle.(string); ok {
-				metadataMap["SUBTITLE"] = str
-			}
-		}
+	// The most preferred key holds the value of a field
+	metadataMap := make(map[string]string, len(candidates))
+	for field, values := range candidates {
+		metadataMap[field] = values[0]
 	}
 
 	return metadataMap, nil
 }
 
-// GetNextID retrieves the next available ID for a specified table in the database.
-// It queries the maximum existing ID and increments it by 1.
+// ReadMetadataCandidates reads all values of each metadata field from an audio file, in order of tag preference.
+// Fields stored under several keys (e.g. RELEASEDATE, DATE and ORIGINALDATE) yield several values,
+// so a caller can fall back to the next key if a value cannot be used.
 //
 // Parameters:
-//   - dbMgr: The database manager instance
-//   - tableName: The name of the table to get the next ID for
+//   - filePath: The path to the audio file
+//   - format: The format of the audio file (e.g., "FLAC", "MP3"), empty to detect it from the extension
 //
 // Returns:
-//   - The next available ID as a string
-//   - An error if the database query fails
-func GetNextID(dbMgr *DBManager, tableName string) (string, error) {
-	var maxID int64
-
-	query := fmt.Sprintf("SELECT COALESCE(MAX(CAST(ID AS INTEGER)), 0) FROM %s", tableName)
-	row := dbMgr.QueryRow(query)
-	if row == nil {
-		return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
-	}
-	err := row.Scan(&maxID)
-	if err != nil {
-		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbmaxidcheck"), err)
-	}
-
-	maxID++
-	return fmt.Sprintf("%d", maxID), nil
+//   - The non-empty values of each field, fields not present in the file are omitted
+//   - An error if the file cannot be read or parsed
+func ReadMetadataCandidates(filePath string, format string) (map[string][]string, error) {
+	return ReadMappedMetadataCandidates(filePath, format, nil)
 }
 
-// GetNextUSN increments and retrieves the next USN (Update Sequence Number) from the agentRegistry table.
-// This function is used to maintain consistency in database updates.
+// ReadMappedMetadataCandidates reads all values of each metadata field from an audio file like ReadMetadataCandidates,
+// with the tag keys and transforms of a user-defined mapping. Fields without a mapping entry use the keys of TagFieldKeys.
 //
 // Parameters:
-//   - dbMgr: The database manager instance
+//   - filePath: The path to the audio file
+//   - format: The format of the audio file (e.g., "FLAC", "MP3"), empty to detect it from the extension
+//   - mapping: The tag mapping, nil for the default keys
 //
 // Returns:
-//   - The next USN value as an integer
-//   - An error if the database operation fails
-func GetNextUSN(dbMgr *DBManager) (int64, error) {
-	// Increment the USN counter
-	updateQuery := `
-		UPDATE agentRegistry
-		SET int_1 = int_1 + 1
-		WHERE registry_id = 'localUpdateCount'
-	`
-
-	err := dbMgr.Execute(updateQuery)
-	if err != nil {
-		return 0, err
+//   - The non-empty values of each field, fields not present in the file are omitted
+//   - An error if the file cannot be read or parsed
+func ReadMappedMetadataCandidates(filePath string, format string, mapping TagMapping) (map[string][]string, error) {
+	if format == "" {
+		format = AudioFormatFromPath(filePath)
 	}
 
-	// Get the new USN value
-	var usn int64
-	selectQuery := `
-		SELECT int_1
-		FROM agentRegistry
-		WHERE registry_id = 'localUpdateCount'
-	`
-
-	row := dbMgr.QueryRow(selectQuery)
-	if row == nil {
-		return 0, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
-	}
-	err = row.Scan(&usn)
+	rawTags, err := readRawTags(filePath, strings.ToUpper(format))
 	if err != nil {
-		return 0, err
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.metadataread"), err)
 	}
 
-	return usn, nil
+	candidates := make(map[string][]string)
+	for field := range TagFieldKeys {
+		var values []string
+		for _, container := range []TagContainer{TagContainerVorbis, TagContainerMP4, TagContainerID3v2, TagContainerRIFF} {
+			raw, ok := rawTags[container]
+			if !ok {
+				continue
+			}
+			for _, key := range mapping.containerKeys(field, container) {
+				if value := rawTagValue(raw, key); value != "" && !slices.Contains(values, value) {
+					values = append(values, value)
+				}
+			}
+		}
+		if values = mapping.transformValues(field, values); len(values) > 0 {
+			candidates[field] = values
+		}
+	}
+
+	return candidates, nil
 }
 
-// AddOrGetArtist adds a new artist to the djmdArtist table if it doesn't exist,
-// or returns the ID of an existing artist with the same name.
+// AddOrGetArtist returns the ID of an existing artist with the given name, or records
+// the insertion of a new artist into the djmdArtist table in the changeset.
+// Artists already recorded for insertion by the same changeset are reused.
 //
 // Parameters:
-//   - dbMgr: The database manager instance
+//   - cs: The changeset collecting the changes
 //   - artistName: The name of the artist to add or find
-//   - usn: The Update Sequence Number to use for the new record
 //
 // Returns:
 //   - The ID of the artist (new or existing)
 //   - An error if the database operation fails
-func AddOrGetArtist(dbMgr *DBManager, artistName string, usn int64) (string, error) {
+func AddOrGetArtist(cs *Changeset, artistName string) (string, error) {
 	if artistName == "" {
 		return "", nil
 	}
+	dbMgr := cs.DB()
 
 	// Check if artist already exists
 	var artistID string
@@ -195,31 +152,84 @@ func AddOrGetArtist(dbMgr *DBManager, artistName string, usn int64) (string, err
 		return "", err
 	}
 
+	// Artist may already be prepared for insertion by an earlier file of the same run
+	if pendingID, ok := cs.FindPendingInsert(SQLTableDJMDArtist, "Name", artistName); ok {
+		return pendingID, nil
+	}
+
 	// Artist doesn't exist, create new
 	dbMgr.logger.Info("%s %s",
 		fmt.Sprintf(locales.Translate("common.log.artist"), artistName),
 		locales.Translate("common.log.dbinserted"))
 
-	newID, err := GetNextID(dbMgr, "djmdArtist")
+	// Record new artist with a fresh ID and UUID, USN and timestamps are added by the changeset
+	newID, err := cs.InsertNew(SQLTableDJMDArtist, artistName,
+		FieldChange{Column: "Name", New: artistName},
+	)
 	if err != nil {
 		return "", err
 	}
 
-	// Get current timestamp
-	currentTime := time.Now().UTC().Format("2006-01-02 15:04:05.000 +00:00")
+	return newID, nil
+}
+
+// AddOrGetAlbum returns the ID of an existing album with the given name and album artist, or records
+// the insertion of a new album into the djmdAlbum table in the changeset.
+// An existing album of the same name without an album artist is reused, the caller assigns the artist.
+// Albums already recorded for insertion by the same changeset are reused.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - albumName: The name of the album to add or find
+//   - albumArtistID: The ID of the album artist, empty for albums without an album artist
+//
+// Returns:
+//   - The ID of the album (new or existing), empty if albumName is empty
+//   - An error if the database operation fails
+func AddOrGetAlbum(cs *Changeset, albumName string, albumArtistID string) (string, error) {
+	if albumName == "" {
+		return "", nil
+	}
+	dbMgr := cs.DB()
+
+	// Check if album already exists, an album of the artist is preferred to an album without artist
+	var albumID string
+	checkQuery := `SELECT ID FROM djmdAlbum
+		WHERE Name = ? COLLATE NOCASE AND (COALESCE(AlbumArtistID, '') = ? OR COALESCE(AlbumArtistID, '') = '')
+		ORDER BY COALESCE(AlbumArtistID, '') = ? DESC LIMIT 1`
+	row := dbMgr.QueryRow(checkQuery, albumName, albumArtistID, albumArtistID)
+	if row == nil {
+		return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	err := row.Scan(&albumID)
+
+	// If album exists, return its ID
+	if err == nil {
+		return albumID, nil
+	}
+
+	// If error is not "no rows", return the error
+	if err != sql.ErrNoRows {
+		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbalbumcheck"), err)
+	}
 
-	// Insert new artist
-	insertQuery := `
-		INSERT INTO djmdArtist (
-			ID, Name, rb_local_usn, created_at, updated_at
-		) VALUES (
-			?, ?, ?, ?, ?
-		)
-	`
+	// Album may already be prepared for insertion by an earlier file of the same run
+	if pendingID, ok := cs.FindPendingInsertMatching(SQLTableDJMDAlbum, map[string]string{"Name": albumName, "AlbumArtistID": albumArtistID}); ok {
+		return pendingID, nil
+	}
 
-	err = dbMgr.Execute(insertQuery, newID, artistName, usn, currentTime, currentTime)
+	// Album doesn't exist, create new
+	dbMgr.logger.Info("%s %s",
+		fmt.Sprintf(locales.Translate("common.log.album"), albumName),
+		locales.Translate("common.log.dbinserted"))
+
+	// Record new album with a fresh ID and UUID, USN and timestamps are added by the changeset
+	fields := []FieldChange{{Column: "Name", New: albumName}}
+	if albumArtistID != "" {
+		fields = append(fields, FieldChange{Column: "AlbumArtistID", New: albumArtistID})
+	}
+	newID, err := cs.InsertNew(SQLTableDJMDAlbum, albumName, fields...)
 	if err != nil {
-		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdArtist", err)
 		return "", err
 	}
 
@@ -257,46 +267,72 @@ func GetAlbumIDFromTrack(dbMgr *DBManager, trackID string) (string, error) {
 	return "", nil
 }
 
-// UpdateAlbumArtistID updates the AlbumArtistID in djmdAlbum table for a specific album.
+// UpdateAlbumArtistID records the change of AlbumArtistID in djmdAlbum table for a specific album.
 // This function is used to assign the correct artist to an existing album.
 //
 // Parameters:
-//   - dbMgr: The database manager instance
+//   - cs: The changeset collecting the changes
 //   - albumID: The ID of the album in djmdAlbum table
 //   - artistID: The ID of the artist to assign to the album
-//   - usn: The Update Sequence Number to use for the update
 //
 // Returns:
+//   - true if the album artist changes
 //   - An error if the database operation fails
-func UpdateAlbumArtistID(dbMgr *DBManager, albumID string, artistID string, usn int64) error {
-	// No separate log message needed here; the action is logged by the caller if necessary.
-	// Get current timestamp
-	var artistName string
-	artistNameQuery := "SELECT Name FROM djmdArtist WHERE ID = ?"
-	row := dbMgr.QueryRow(artistNameQuery, artistID)
-	if row != nil {
-		row.Scan(&artistName)
+func UpdateAlbumArtistID(cs *Changeset, albumID string, artistID string) (bool, error) {
+	changed, err := cs.Update(SQLTableDJMDAlbum, albumID, "",
+		FieldChange{Column: "AlbumArtistID", New: artistID},
+	)
+	if err != nil {
+		cs.DB().logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdAlbum/%s", albumID), err)
+		return false, fmt.Errorf("%s: %w", locales.Translate("common.err.albumupdate"), err)
 	}
 
-	dbMgr.logger.Info("%s %s",
-		fmt.Sprintf(locales.Translate("common.log.artist"), artistName),
-		fmt.Sprintf(locales.Translate("common.log.assignedalbum"), albumID))
+	if changed {
+		// Artist name is only used for the log, pending artists are logged by their ID
+		artistName := artistID
+		row := cs.DB().QueryRow("SELECT Name FROM djmdArtist WHERE ID = ?", artistID)
+		if row != nil {
+			row.Scan(&artistName)
+		}
 
-	currentTime := time.Now().UTC().Format("2006-01-02 15:04:05.000 +00:00")
+		cs.DB().logger.Info("%s %s",
+			fmt.Sprintf(locales.Translate("common.log.artist"), artistName),
+			fmt.Sprintf(locales.Translate("common.log.assignedalbum"), albumID))
+	}
 
-	updateQuery := `
-		UPDATE djmdAlbum
-		SET AlbumArtistID = ?, rb_local_usn = ?, updated_at = ?
-		WHERE ID = ?
-	`
+	return changed, nil
+}
 
-	err := dbMgr.Execute(updateQuery, artistID, usn, currentTime, albumID)
-	if err != nil {
-		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdAlbum/%s", albumID), err)
-		return fmt.Errorf("%s: %w", locales.Translate("common.err.albumupdate"), err)
-	}
+// Overwrite policies of the metadata fields written by ProcessFolderMetadata.
+const (
+	// FieldPolicyFillEmpty writes a field only if the database value is empty,
+	// differing values are kept and counted as conflicts
+	FieldPolicyFillEmpty = "fill"
+	// FieldPolicyOverwrite replaces differing database values with the tag value
+	FieldPolicyOverwrite = "overwrite"
+	// FieldPolicySkipDifferent writes a field only if the database value is empty,
+	// differing values are kept, counted as conflicts and logged for every file
+	FieldPolicySkipDifferent = "skip"
+)
 
-	return nil
+// FieldPolicies lists the available overwrite policies.
+var FieldPolicies = []string{FieldPolicyFillEmpty, FieldPolicyOverwrite, FieldPolicySkipDifferent}
+
+// MetadataOptions controls which files and fields ProcessFolderMetadata processes.
+type MetadataOptions struct {
+	Extensions   []string          // Extensions of the files to process, empty processes FLAC files only
+	Fields       []string          // Metadata fields to write (TagField constants), empty writes DefaultMetadataFields
+	Policies     map[string]string // Overwrite policy per field, fields without a policy are only filled
+	DateFallback string            // Fallback for incomplete release dates (DateFallback constants)
+	Recursive    bool              // Process subfolders recursively
+	Mapping      TagMapping        // Source tag keys and transforms per field, nil reads the keys of TagFieldKeys
+}
+
+// FieldStats holds the counts of one metadata field for folder metadata processing.
+type FieldStats struct {
+	Filled      int // Empty database values filled from the tag
+	Overwritten int // Differing database values replaced by the tag value
+	Conflicts   int // Differing database values kept by the FieldPolicyFillEmpty and FieldPolicySkipDifferent policies
 }
 
 // ProcessSummary holds aggregated metrics for folder metadata processing.
@@ -309,15 +345,77 @@ type ProcessSummary struct {
 	DbMisses     int
 	DbUpdateErrs int
 	SkippedDirs  int
+	Fields       map[string]FieldStats // Counts per metadata field
+	DateIssues   []DateIssue           // Release date tags which could not be normalized
+	Artwork      []ArtworkFile         // Artwork to create in the share folder once the changes are applied
+	Files        []FileReport          // Result of each processed file
 }
 
-// ProcessFolderMetadata processes metadata from all FLAC files in a folder
-// and updates the database accordingly.
+// countField adds the outcome of the overwrite policy for a field to the per-field counts.
+func (s *ProcessSummary) countField(field string, outcome fieldOutcome) {
+	if s.Fields == nil {
+		s.Fields = make(map[string]FieldStats)
+	}
+	stats := s.Fields[field]
+	switch outcome {
+	case fieldFilled:
+		stats.Filled++
+	case fieldOverwritten:
+		stats.Overwritten++
+	case fieldKept, fieldConflict:
+		stats.Conflicts++
+	default:
+		return
+	}
+	s.Fields[field] = stats
+}
+
+// ParseFieldPolicies parses the overwrite policies stored in the configuration
+// as comma separated FIELD=policy pairs. Pairs with unknown policies are ignored.
 //
 // Parameters:
-//   - dbMgr: The database manager instance
-//   - folderPath: The path to the folder containing FLAC files
-//   - recursive: Whether to process subfolders recursively
+//   - value: The stored policies, e.g. "RELEASEDATE=fill,SUBTITLE=overwrite"
+//
+// Returns:
+//   - The policy per field
+func ParseFieldPolicies(value string) map[string]string {
+	policies := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		field, policy, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !slices.Contains(FieldPolicies, policy) {
+			continue
+		}
+		policies[strings.ToUpper(strings.TrimSpace(field))] = policy
+	}
+	return policies
+}
+
+// FormatFieldPolicies formats overwrite policies for storing in the configuration, see ParseFieldPolicies.
+//
+// Parameters:
+//   - policies: The policy per field
+//
+// Returns:
+//   - The comma separated FIELD=policy pairs sorted by field
+func FormatFieldPolicies(policies map[string]string) string {
+	pairs := make([]string, 0, len(policies))
+	for field, policy := range policies {
+		pairs = append(pairs, field+"="+policy)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// ProcessFolderMetadata processes metadata from all audio files with the given extensions in a folder
+// and records the resulting database changes into the changeset.
+// Each field is written according to its overwrite policy.
+// Nothing is written to the database; the caller applies the changeset after the user reviews it.
+//
+// Parameters:
+//   - ctx: The context for cancellation
+//   - cs: The changeset collecting the changes
+//   - folderPath: The path to the folder containing the audio files
+//   - options: The extensions, fields and overwrite policies to process
 //   - onFilesFound: Callback invoked after counting files (can be nil)
 //   - onProgress: Callback invoked during processing with progress and counts (can be nil)
 //
@@ -326,14 +424,23 @@ type ProcessSummary struct {
 //   - An error if the operation fails (fatal pre-processing errors only)
 func ProcessFolderMetadata(
 	ctx context.Context,
-	dbMgr *DBManager,
+	cs *Changeset,
 	folderPath string,
-	recursive bool,
+	options MetadataOptions,
 	onFilesFound func(total int),
 	onProgress func(progress float64, updated int, total int),
 ) (ProcessSummary, error) {
-	// Find all FLAC files in the folder using the new safe file listing function
-	flacFiles, skippedDirsFromProcessing, err := GetFilesInFolder(dbMgr.logger, folderPath, []string{".flac"}, recursive)
+	dbMgr := cs.DB()
+
+	if len(options.Extensions) == 0 {
+		options.Extensions = []string{ExtensionFLAC}
+	}
+	if len(options.Fields) == 0 {
+		options.Fields = DefaultMetadataFields
+	}
+
+	// Find all audio files in the folder using the new safe file listing function
+	audioFiles, skippedDirsFromProcessing, err := GetFilesInFolder(dbMgr.logger, folderPath, options.Extensions, options.Recursive)
 
 	if err != nil {
 		return ProcessSummary{}, err
@@ -341,11 +448,11 @@ func ProcessFolderMetadata(
 
 	// Notify files found
 	if onFilesFound != nil {
-		onFilesFound(len(flacFiles))
+		onFilesFound(len(audioFiles))
 	}
 
 	// Return early if no files found
-	if len(flacFiles) == 0 {
+	if len(audioFiles) == 0 {
 		return ProcessSummary{}, errors.New(locales.Translate("common.err.nofiles"))
 	}
 
@@ -369,17 +476,11 @@ func ProcessFolderMetadata(
 		trackMap[normalizedPath] = track.ID
 	}
 
-	// Get a single USN for the entire operation
-	usn, err := GetNextUSN(dbMgr)
-	if err != nil {
-		return ProcessSummary{}, err
-	}
-
-	// Process each FLAC file
-	totalFiles := len(flacFiles)
+	// Process each audio file
+	totalFiles := len(audioFiles)
 	summary := ProcessSummary{Total: totalFiles, SkippedDirs: len(skippedDirsFromProcessing)}
 
-	for i, flacFile := range flacFiles {
+	for i, audioFile := range audioFiles {
 		// Cancellation check before processing each file
 		select {
 		case <-ctx.Done():
@@ -388,48 +489,65 @@ func ProcessFolderMetadata(
 		}
 
 		// Zero-byte file skip detection
-		if fi, statErr := os.Stat(flacFile); statErr != nil {
+		report := FileReport{Path: audioFile}
+		if fi, statErr := os.Stat(audioFile); statErr != nil {
 			dbMgr.logger.Error("%s %s",
-				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(flacFile)),
+				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(audioFile)),
 				locales.Translate("common.log.iswrong"))
 			summary.MetadataErrs++
+			report.Result, report.Reason = FileResultMetadataError, statErr.Error()
+			summary.Files = append(summary.Files, report)
 			continue
 		} else if fi.Size() == 0 {
 			dbMgr.logger.Error("%s %s",
-				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(flacFile)),
+				fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(audioFile)),
 				locales.Translate("common.log.iswrong"))
 			summary.SkippedZero++
+			report.Result, report.Reason = FileResultSkippedZero, locales.Translate("common.log.iswrong")
+			summary.Files = append(summary.Files, report)
 			continue
 		}
 
 		// Process the file using hash map lookup
-		updated, perr := updateFileMetadataInDB(dbMgr, flacFile, usn, trackMap)
+		updated, perr := updateFileMetadataInDB(cs, audioFile, trackMap, options, &summary, &report)
 		if perr != nil {
 			// Classify errors for metrics and continue
 			msg := perr.Error()
+			report.Reason = msg
 			switch {
 			case strings.Contains(msg, locales.Translate("common.err.metadataread")):
 				dbMgr.logger.Warning("%s %s",
-					fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(flacFile)),
+					fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(audioFile)),
 					locales.Translate("common.log.incorrmetadata"))
 				summary.MetadataErrs++
+				report.Result = FileResultMetadataError
 			case strings.Contains(msg, locales.Translate("common.err.dbnotrackfound")):
 				dbMgr.logger.Error("%s %s",
-					fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(flacFile)),
+					fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(audioFile)),
 					locales.Translate("common.log.dbnotfound"))
 				summary.DbMisses++
+				report.Result = FileResultDbMiss
 			default:
 				// General database error without SQL dump
 				summary.DbUpdateErrs++
+				report.Result = FileResultUpdateError
 			}
+			summary.Files = append(summary.Files, report)
 			continue
 		}
 
-		if updated {
+		switch {
+		case report.Result == FileResultMetadataError:
+			// Unreadable tags are skipped without an error, so the other files are processed
+			summary.MetadataErrs++
+		case updated:
 			summary.Updated++
-		} else {
+			report.Result = FileResultUpdated
+		default:
 			summary.NoChange++
+			report.Result = FileResultNoChange
 		}
+		summary.Files = append(summary.Files, report)
 
 		// Progress update after processing current file
 		if onProgress != nil && totalFiles > 0 {
@@ -445,18 +563,257 @@ func ProcessFolderMetadata(
 	return summary, nil
 }
 
-// Updates a FLAC file’s metadata in the database and logs changes.
-// Reads metadata via ReadMetadataFromFile.
+// DefaultMetadataFields lists the metadata fields written to the database when no fields are selected.
+var DefaultMetadataFields = []string{TagFieldAlbumArtist, TagFieldOrigArtist, TagFieldReleaseDate, TagFieldSubtitle}
+
+// contentTagFields maps the metadata fields stored in djmdContent to their columns, in order of processing.
+// Fields with a lookup table are stored as the ID of a row of the table, found or created by the lookup function.
+var contentTagFields = []struct {
+	field  string
+	column string
+	table  string
+	lookup func(cs *Changeset, name string) (string, error)
+}{
+	{TagFieldOrigArtist, "OrgArtistID", SQLTableDJMDArtist, AddOrGetArtist},
+	{TagFieldReleaseDate, "ReleaseDate", "", nil},
+	{TagFieldSubtitle, "Subtitle", "", nil},
+	{TagFieldLabel, "LabelID", SQLTableDJMDLabel, AddOrGetLabel},
+	{TagFieldComposer, "ComposerID", SQLTableDJMDArtist, AddOrGetArtist},
+	{TagFieldISRC, "ISRC", "", nil},
+	{TagFieldRemixer, "RemixerID", SQLTableDJMDArtist, AddOrGetArtist},
+	{TagFieldGenre, "GenreID", SQLTableDJMDGenre, AddOrGetGenre},
+	{TagFieldTrackNumber, "TrackNo", "", nil},
+	{TagFieldDiscNumber, "DiscNo", "", nil},
+}
+
+// fieldOutcome is the result of the overwrite policy for one field of a track.
+type fieldOutcome int
+
+const (
+	fieldUnchanged   fieldOutcome = iota // The tag is empty or the database holds the same value
+	fieldFilled                          // The empty database value is filled
+	fieldOverwritten                     // The differing database value is replaced
+	fieldKept                            // The differing database value is kept and counted as conflict
+	fieldConflict                        // The differing database value is kept, counted as conflict and logged
+)
+
+// decideFieldOutcome applies the overwrite policy of a field to its current database value and its tag value.
+func decideFieldOutcome(policy string, current string, equal bool) fieldOutcome {
+	switch {
+	case equal:
+		return fieldUnchanged
+	case current == "":
+		return fieldFilled
+	case policy == FieldPolicyOverwrite:
+		return fieldOverwritten
+	case policy == FieldPolicySkipDifferent:
+		return fieldConflict
+	default:
+		return fieldKept
+	}
+}
+
+// readCurrentContentValues reads the current values of the djmdContent columns of the given fields as text.
+// Columns referencing a lookup table are resolved to the name of the referenced row; NULL and 0 read as empty.
+func readCurrentContentValues(dbMgr *DBManager, trackID string, fields []string) (map[string]string, error) {
+	var names []string
+	var exprs []string
+	for _, tagField := range contentTagFields {
+		if !slices.Contains(fields, tagField.field) {
+			continue
+		}
+		names = append(names, tagField.field)
+		if tagField.table != "" {
+			exprs = append(exprs, fmt.Sprintf("COALESCE((SELECT Name FROM %s WHERE ID = c.%s), '')", tagField.table, tagField.column))
+		} else {
+			exprs = append(exprs, fmt.Sprintf("COALESCE(CAST(c.%s AS TEXT), '')", tagField.column))
+		}
+	}
+	values := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return values, nil
+	}
+
+	row := dbMgr.QueryRow(fmt.Sprintf("SELECT %s FROM djmdContent c WHERE c.ID = ?", strings.Join(exprs, ", ")), trackID)
+	if row == nil {
+		return nil, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	dest := make([]string, len(names))
+	args := make([]interface{}, len(names))
+	for i := range dest {
+		args[i] = &dest[i]
+	}
+	if err := row.Scan(args...); err != nil {
+		return nil, err
+	}
+	for i, name := range names {
+		if value := strings.TrimSpace(dest[i]); value != "0" {
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// artworkOutcome applies the overwrite policy of the artwork to the ImagePath of a track and the picture
+// embedded in its file. An ImagePath whose image is missing in the share folder counts as empty,
+// an artwork created from the same picture before counts as equal, so repeated runs change nothing.
+func artworkOutcome(dbMgr *DBManager, filePath string, trackID string, policy string) (fieldOutcome, error) {
+	images, err := embeddedArtworkImages(filePath)
+	if err != nil {
+		dbMgr.logger.Warning("%s %v", fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(filePath)), err)
+		return fieldUnchanged, nil
+	}
+	if images == nil {
+		return fieldUnchanged, nil
+	}
+
+	var current string
+	row := dbMgr.QueryRow("SELECT COALESCE(ImagePath, '') FROM djmdContent WHERE ID = ?", trackID)
+	if row == nil {
+		return fieldUnchanged, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	if err := row.Scan(&current); err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdContent/%s", trackID), err)
+		return fieldUnchanged, err
+	}
+	shareDir := ArtworkShareDir(dbMgr.GetDatabasePath())
+	if !artworkExists(shareDir, current) {
+		current = ""
+	}
+
+	outcome := decideFieldOutcome(policy, current, artworkMatches(shareDir, current, images))
+	if outcome == fieldConflict {
+		logFieldConflict(dbMgr, filePath, TagFieldArtwork, current, locales.Translate("common.log.embeddedpicture"))
+	}
+	return outcome, nil
+}
+
+// logFieldConflict logs a field whose differing database value is kept.
+func logFieldConflict(dbMgr *DBManager, filePath, field, current, value string) {
+	dbMgr.logger.Warning("%s %s",
+		fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(filePath)),
+		fmt.Sprintf(locales.Translate("common.log.fieldconflict"), field, current, value))
+}
+
+// linkAlbumFromTag finds or creates the album of the ALBUM and ALBUMARTIST tags of a track without an album,
+// links the track to it and assigns the album artist. Without an album artist the track is linked to the album
+// of the same name without an album artist. Tracks without an ALBUM tag are left unchanged.
+// Returns the outcome for the album artist, a linked album counts as filled, and any error encountered.
+func linkAlbumFromTag(cs *Changeset, filePath string, trackID string, albumName string, albumArtist string) (fieldOutcome, error) {
+	if albumName == "" {
+		return fieldUnchanged, nil
+	}
+	dbMgr := cs.DB()
+
+	// Get or create artist
+	artistID := ""
+	if albumArtist != "" {
+		var err error
+		artistID, err = AddOrGetArtist(cs, albumArtist)
+		if err != nil {
+			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdArtist", err)
+			return fieldUnchanged, err
+		}
+	}
+
+	// Get or create album of the artist
+	albumID, err := AddOrGetAlbum(cs, albumName, artistID)
+	if err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdAlbum", err)
+		return fieldUnchanged, err
+	}
+
+	// Link the track to the album
+	if _, err := cs.Update(SQLTableDJMDContent, trackID, filepath.Base(filePath), FieldChange{Column: "AlbumID", New: albumID}); err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdContent/%s", trackID), err)
+		return fieldUnchanged, err
+	}
+
+	// A reused album without an album artist gets the artist of the tag
+	if artistID != "" {
+		if _, err := UpdateAlbumArtistID(cs, albumID, artistID); err != nil {
+			return fieldUnchanged, err
+		}
+	}
+	return fieldFilled, nil
+}
+
+// updateAlbumArtistFromTag records the album artist of the album of a track according to the overwrite policy.
+// Tracks without an album are linked to the album of their ALBUM tag, which is created if needed.
+// Empty album artists are left unchanged.
+// Returns the outcome of the policy and any error encountered.
+func updateAlbumArtistFromTag(cs *Changeset, filePath string, trackID string, albumName string, albumArtist string, policy string) (fieldOutcome, error) {
+	dbMgr := cs.DB()
+
+	// Get AlbumID from the track (step 1 from scope)
+	albumID, err := GetAlbumIDFromTrack(dbMgr, trackID)
+	if err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdContent", err)
+		return fieldUnchanged, err
+	}
+
+	// Tracks without an album get the album of their tags (step 2 from scope)
+	if albumID == "" {
+		return linkAlbumFromTag(cs, filePath, trackID, albumName, albumArtist)
+	}
+	if albumArtist == "" {
+		return fieldUnchanged, nil
+	}
+
+	var current string
+	row := dbMgr.QueryRow("SELECT COALESCE((SELECT Name FROM djmdArtist WHERE ID = a.AlbumArtistID), '') FROM djmdAlbum a WHERE a.ID = ?", albumID)
+	if row == nil {
+		return fieldUnchanged, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	if err := row.Scan(&current); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdAlbum", err)
+		return fieldUnchanged, err
+	}
+
+	outcome := decideFieldOutcome(policy, current, strings.EqualFold(current, albumArtist))
+	if outcome == fieldConflict {
+		logFieldConflict(dbMgr, filePath, TagFieldAlbumArtist, current, albumArtist)
+	}
+	if outcome != fieldFilled && outcome != fieldOverwritten {
+		return outcome, nil
+	}
+
+	// Get or create artist
+	artistID, err := AddOrGetArtist(cs, albumArtist)
+	if err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdArtist", err)
+		return fieldUnchanged, err
+	}
+	if _, err := UpdateAlbumArtistID(cs, albumID, artistID); err != nil {
+		return fieldUnchanged, err
+	}
+	return outcome, nil
+}
+
+// Records the changes of an audio file’s metadata in the database into the changeset and logs them.
+// Reads metadata via ReadMappedMetadataCandidates using the tag keys of the file's format and the options' mapping.
+// The release date and fields with the date transform are normalized to YYYY-MM-DD,
+// unusable dates are added to the summary's DateIssues.
 // Looks up track ID using normalized path hash map.
-// Updates ALBUMARTIST, ORIGARTIST, RELEASEDATE, SUBTITLE fields as present.
+// Updates the selected fields as present according to their overwrite policies:
+// ALBUMARTIST in djmdAlbum (tracks without an album are linked to the album of their ALBUM tag),
+// all other fields in djmdContent. ARTWORK sets a new ImagePath if the file has an embedded picture and adds
+// the artwork to the summary, its images are created by WriteArtworkFiles once the changes are applied.
+// The outcomes are counted in the summary.
+// The track ID and the updated and not updated fields are recorded in the file report;
+// files whose tags cannot be read are reported as FileResultMetadataError.
 // Returns whether any field changed and any error encountered.
-func updateFileMetadataInDB(dbMgr *DBManager, filePath string, usn int64, trackMap map[string]string) (bool, error) {
+func updateFileMetadataInDB(cs *Changeset, filePath string, trackMap map[string]string, options MetadataOptions, summary *ProcessSummary, report *FileReport) (bool, error) {
+	dbMgr := cs.DB()
+	label := filepath.Base(filePath)
+
 	// Read metadata from file
-	metadata, err := ReadMetadataFromFile(filePath, "FLAC")
+	candidates, err := ReadMappedMetadataCandidates(filePath, AudioFormatFromPath(filePath), options.Mapping)
 	if err != nil {
 		dbMgr.logger.Warning("%s %s",
 			fmt.Sprintf(locales.Translate("common.log.incorrmetadata"), filePath),
 			locales.Translate("common.log.skipped"))
+		report.Result, report.Reason = FileResultMetadataError, err.Error()
 		return false, nil // Return nil to continue processing other files
 	}
 
@@ -469,110 +826,151 @@ func updateFileMetadataInDB(dbMgr *DBManager, filePath string, usn int64, trackM
 	if !exists {
 		return false, fmt.Errorf("%s: %s", locales.Translate("common.err.dbnotrackfound"), filepath.Base(filePath))
 	}
+	report.TrackID = trackID
+
+	// The most preferred value of each field is used, dates are the first value which is a date
+	metadata := make(map[string]string, len(candidates))
+	for field, values := range candidates {
+		metadata[field] = values[0]
+	}
+	for _, field := range options.Fields {
+		values, ok := candidates[field]
+		if !ok || (field != TagFieldReleaseDate && options.Mapping.Transform(field) != TagTransformDate) {
+			continue
+		}
+		date, err := NormalizeReleaseDateCandidates(values, options.DateFallback)
+		if err != nil {
+			dbMgr.logger.Warning("%s %s",
+				fmt.Sprintf(locales.Translate("common.log.file"), label), err.Error())
+			summary.DateIssues = append(summary.DateIssues, DateIssue{File: filePath, Value: values[0], Reason: err.Error()})
+		}
+		metadata[field] = date
+	}
 
 	changed := false
 	updatedFields := []string{}
 	notUpdatedFields := []string{}
+	outcomes := make(map[string]fieldOutcome)
 
-	// Process ALBUMARTIST if available
-	if albumArtist, ok := metadata["ALBUMARTIST"]; ok && albumArtist != "" {
-		// Get or create artist
-		artistID, err := AddOrGetArtist(dbMgr, albumArtist, usn)
-		if err != nil {
-			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdArtist", err)
-			return false, err
-		}
-
-		// Get AlbumID from the track (step 1 from scope)
-		albumID, err := GetAlbumIDFromTrack(dbMgr, trackID)
+	// Process ALBUMARTIST if selected and available
+	if slices.Contains(options.Fields, TagFieldAlbumArtist) {
+		outcome, err := updateAlbumArtistFromTag(cs, filePath, trackID,
+			metadata[TagFieldAlbum], metadata[TagFieldAlbumArtist], options.Policies[TagFieldAlbumArtist])
 		if err != nil {
-			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdContent", err)
 			return false, err
 		}
-
-		// Only update album if AlbumID exists (step 2-3 from scope)
-		if albumID != "" {
-			if err := UpdateAlbumArtistID(dbMgr, albumID, artistID, usn); err != nil {
-				dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdAlbum/%s", albumID), err)
-				return false, err
-			}
+		if outcome == fieldFilled || outcome == fieldOverwritten {
 			changed = true
-			updatedFields = append(updatedFields, "ALBUMARTIST")
+			updatedFields = append(updatedFields, TagFieldAlbumArtist)
 		} else {
-			notUpdatedFields = append(notUpdatedFields, "ALBUMARTIST")
+			notUpdatedFields = append(notUpdatedFields, TagFieldAlbumArtist)
 		}
-	} else {
-		notUpdatedFields = append(notUpdatedFields, "ALBUMARTIST")
+		summary.countField(TagFieldAlbumArtist, outcome)
 	}
 
-	// Process ORIGARTIST if available
-	if origArtist, ok := metadata["ORIGARTIST"]; ok && origArtist != "" {
-		// Get or create artist
-		artistID, err := AddOrGetArtist(dbMgr, origArtist, usn)
-		if err != nil {
-			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), "djmdArtist", err)
-			return false, err
+	current, err := readCurrentContentValues(dbMgr, trackID, options.Fields)
+	if err != nil {
+		dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdContent/%s", trackID), err)
+		return false, err
+	}
+
+	// Collect djmdContent columns of the track
+	fields := []FieldChange{}
+	fieldNames := []string{}
+
+	for _, tagField := range contentTagFields {
+		if !slices.Contains(options.Fields, tagField.field) {
+			continue
+		}
+		value := metadata[tagField.field]
+		if tagField.field == TagFieldGenre {
+			value = cleanGenre(value)
 		}
 
-		// Update track's OrgArtistID
-		updateQuery := `
-			UPDATE djmdContent
-			SET OrgArtistID = ?, rb_local_usn = ?
-			WHERE ID = ?
-		`
-		if err := dbMgr.Execute(updateQuery, artistID, usn, trackID); err != nil {
-			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdContent/%s", trackID), err)
-			return false, err
+		// Track and disc numbers are compared and stored as numbers
+		var newValue interface{} = value
+		equal := value == current[tagField.field]
+		if tagField.field == TagFieldTrackNumber || tagField.field == TagFieldDiscNumber {
+			number, ok := ParseTagNumber(value)
+			if !ok {
+				value = ""
+			}
+			newValue = number
+			equal = strconv.FormatInt(number, 10) == current[tagField.field]
+		} else if tagField.table != "" {
+			equal = strings.EqualFold(value, current[tagField.field])
+		}
+		if value == "" {
+			notUpdatedFields = append(notUpdatedFields, tagField.field)
+			continue
+		}
+
+		outcome := decideFieldOutcome(options.Policies[tagField.field], current[tagField.field], equal)
+		if outcome == fieldConflict {
+			logFieldConflict(dbMgr, filePath, tagField.field, current[tagField.field], value)
+		}
+		if outcome != fieldFilled && outcome != fieldOverwritten {
+			notUpdatedFields = append(notUpdatedFields, tagField.field)
+			summary.countField(tagField.field, outcome)
+			continue
 		}
-		changed = true
-		updatedFields = append(updatedFields, "ORIGARTIST")
-	} else {
-		notUpdatedFields = append(notUpdatedFields, "ORIGARTIST")
-	}
 
-	// Update RELEASEDATE and SUBTITLE if available
-	var updateFields []string
-	var updateValues []interface{}
+		if tagField.lookup != nil {
+			// Get or create the row of the lookup table
+			id, err := tagField.lookup(cs, value)
+			if err != nil {
+				dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), tagField.table, err)
+				return false, err
+			}
+			newValue = id
+		}
 
-	if releaseDate, ok := metadata["RELEASEDATE"]; ok {
-		updateFields = append(updateFields, "ReleaseDate = ?")
-		updateValues = append(updateValues, releaseDate)
-	} else {
-		notUpdatedFields = append(notUpdatedFields, "RELEASEDATE")
+		fields = append(fields, FieldChange{Column: tagField.column, New: newValue})
+		fieldNames = append(fieldNames, tagField.field)
+		outcomes[tagField.field] = outcome
 	}
 
-	if subtitle, ok := metadata["SUBTITLE"]; ok {
-		updateFields = append(updateFields, "Subtitle = ?")
-		updateValues = append(updateValues, subtitle)
-	} else {
-		notUpdatedFields = append(notUpdatedFields, "SUBTITLE")
+	// The embedded picture replaces the artwork only if there is none or the policy allows it
+	imagePath := ""
+	if slices.Contains(options.Fields, TagFieldArtwork) {
+		outcome, err := artworkOutcome(dbMgr, filePath, trackID, options.Policies[TagFieldArtwork])
+		if err != nil {
+			return false, err
+		}
+		if outcome == fieldFilled || outcome == fieldOverwritten {
+			imagePath = newArtworkImagePath()
+			fields = append(fields, FieldChange{Column: "ImagePath", New: imagePath})
+			fieldNames = append(fieldNames, TagFieldArtwork)
+			outcomes[TagFieldArtwork] = outcome
+		} else {
+			notUpdatedFields = append(notUpdatedFields, TagFieldArtwork)
+			summary.countField(TagFieldArtwork, outcome)
+		}
 	}
 
 	// If we have fields to update
-	if len(updateFields) > 0 {
-		// Add USN and ID to values
-		updateValues = append(updateValues, usn, trackID)
-
-		// Build and execute update query
-		updateQuery := fmt.Sprintf(`
-			UPDATE djmdContent
-			SET %s, rb_local_usn = ?
-			WHERE ID = ?
-		`, strings.Join(updateFields, ", "))
-
-		if err := dbMgr.Execute(updateQuery, updateValues...); err != nil {
+	if len(fields) > 0 {
+		trackChanged, err := cs.Update(SQLTableDJMDContent, trackID, label, fields...)
+		if err != nil {
 			dbMgr.logger.Error(locales.Translate("common.log.dberrorat"), fmt.Sprintf("djmdContent/%s", trackID), err)
 			return false, err
 		}
-		changed = true
-		if _, ok := metadata["RELEASEDATE"]; ok {
-			updatedFields = append(updatedFields, "RELEASEDATE")
-		}
-		if _, ok := metadata["SUBTITLE"]; ok {
-			updatedFields = append(updatedFields, "SUBTITLE")
+		if trackChanged {
+			changed = true
+			updatedFields = append(updatedFields, fieldNames...)
+			for _, name := range fieldNames {
+				summary.countField(name, outcomes[name])
+			}
+			if imagePath != "" {
+				summary.Artwork = append(summary.Artwork, ArtworkFile{Source: filePath, ImagePath: imagePath})
+			}
+		} else {
+			notUpdatedFields = append(notUpdatedFields, fieldNames...)
 		}
 	}
 
+	report.Updated, report.NotUpdated = updatedFields, notUpdatedFields
+
 	// INFO summary of processed files
 	dbMgr.logger.Info("%s, id: %s, %s %s, %s %s",
 		fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(filePath)), trackID,
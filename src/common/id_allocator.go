@@ -0,0 +1,160 @@
+// common/id_allocator.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the allocator of row IDs and UUIDs used for all inserted rows.
+
+package common
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"MetaRekordFixer/locales"
+)
+
+const (
+	// idMinValue is the smallest ID handed out, rekordbox never uses very small IDs for its own rows
+	idMinValue = 100
+
+	// idMaxAttempts limits the number of random IDs tried before giving up
+	idMaxAttempts = 1000
+)
+
+// rowSyncDefaults holds the values of the sync bookkeeping columns of a row created locally and never synced.
+var rowSyncDefaults = []FieldChange{
+	{Column: "rb_data_status", New: int64(0)},
+	{Column: "rb_local_data_status", New: int64(0)},
+	{Column: "rb_local_deleted", New: int64(0)},
+	{Column: "rb_local_synced", New: int64(0)},
+}
+
+// IDAllocator hands out row IDs and UUIDs the way rekordbox does.
+// Rekordbox does not use sequential IDs; every new row gets a random unused 28-bit number stored as text
+// and a random UUID. The allocator remembers IDs it has handed out, so rows prepared but not yet
+// written never collide with each other.
+type IDAllocator struct {
+	dbMgr        *DBManager
+	reserved     map[string]map[string]bool // table -> IDs handed out by this allocator
+	contentUUIDs map[string]string          // djmdContent ID -> UUID cache
+	mutex        sync.Mutex
+}
+
+// NewIDAllocator creates a new allocator bound to the given database manager.
+//
+// Parameters:
+//   - dbMgr: Database manager used to check that allocated IDs are not taken
+//
+// Returns:
+//   - A new IDAllocator
+func NewIDAllocator(dbMgr *DBManager) *IDAllocator {
+	return &IDAllocator{
+		dbMgr:        dbMgr,
+		reserved:     make(map[string]map[string]bool),
+		contentUUIDs: make(map[string]string),
+	}
+}
+
+// NextID returns a random ID not used in the table and not handed out before by this allocator.
+//
+// Parameters:
+//   - table: The name of the table
+//
+// Returns:
+//   - The new ID as a string
+//   - An error if the database cannot be queried or no free ID was found
+func (a *IDAllocator) NextID(table string) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.reserved[table] == nil {
+		a.reserved[table] = make(map[string]bool)
+	}
+
+	for attempt := 0; attempt < idMaxAttempts; attempt++ {
+		value, err := randomRowID()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbidalloc"), err)
+		}
+		if value < idMinValue {
+			continue
+		}
+		id := fmt.Sprintf("%d", value)
+		if a.reserved[table][id] {
+			continue
+		}
+
+		row := a.dbMgr.QueryRow(fmt.Sprintf("SELECT 1 FROM %s WHERE ID = ?", table), id)
+		if row == nil {
+			return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), a.dbMgr.GetDatabasePath())
+		}
+		var exists int
+		err = row.Scan(&exists)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbidalloc"), err)
+		}
+
+		a.reserved[table][id] = true
+		return id, nil
+	}
+
+	return "", errors.New(locales.Translate("common.err.dbidalloc"))
+}
+
+// ContentUUID returns the UUID of a track, which is stored as ContentUUID in rows belonging to the track.
+//
+// Parameters:
+//   - contentID: The ID of the track in djmdContent
+//
+// Returns:
+//   - The UUID of the track
+//   - An error if the track cannot be found
+func (a *IDAllocator) ContentUUID(contentID string) (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if uuid, ok := a.contentUUIDs[contentID]; ok {
+		return uuid, nil
+	}
+
+	row := a.dbMgr.QueryRow("SELECT UUID FROM djmdContent WHERE ID = ?", contentID)
+	if row == nil {
+		return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), a.dbMgr.GetDatabasePath())
+	}
+	var uuid NullString
+	if err := row.Scan(&uuid); err != nil {
+		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbnotrackfound"), err)
+	}
+
+	a.contentUUIDs[contentID] = uuid.String
+	return uuid.String, nil
+}
+
+// NewUUID generates a random (version 4) UUID in the lowercase form used by rekordbox.
+//
+// Returns:
+//   - The new UUID
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand failed: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// randomRowID returns a random 28-bit number, the ID range used by rekordbox.
+func randomRowID() (uint32, error) {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(b[:]) >> 4, nil
+}
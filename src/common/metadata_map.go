@@ -0,0 +1,120 @@
+// common/metadata_map.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the mapping of metadata fields between audio formats, loaded from the embedded metadata_map.csv.
+
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"MetaRekordFixer/assets"
+	"MetaRekordFixer/locales"
+)
+
+// MetadataMap represents the mapping between metadata fields for different formats.
+// It provides translation tables between internal field names and format-specific field names.
+type MetadataMap struct {
+	// InternalToMP3 maps internal field names to MP3 (ID3) field names
+	InternalToMP3 map[string]string
+	// InternalToFLAC maps internal field names to FLAC field names
+	InternalToFLAC map[string]string
+	// InternalToWAV maps internal field names to WAV field names
+	InternalToWAV map[string]string
+}
+
+// LoadMetadataMap loads the metadata mapping from the embedded CSV file.
+// The CSV file defines how metadata fields should be mapped between different audio formats.
+//
+// Returns:
+//   - A populated MetadataMap structure and nil error on success
+//   - nil and an error if loading or parsing fails
+func LoadMetadataMap() (*MetadataMap, error) {
+	// Load the CSV content from the embedded file
+	csvContent := assets.ResourceMetadataMapCSV.Content()
+
+	// Create a new CSV reader from the content
+	reader := csv.NewReader(bytes.NewReader(csvContent))
+
+	// Read the header row
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.metamapreadheader"), err)
+	}
+
+	// Initialize maps
+	result := &MetadataMap{
+		InternalToMP3:  make(map[string]string),
+		InternalToFLAC: make(map[string]string),
+		InternalToWAV:  make(map[string]string),
+	}
+
+	// Find column indices
+	mpIndex := -1
+	flacIndex := -1
+	wavIndex := -1
+	for i, col := range header {
+		switch col {
+		case "MP3":
+			mpIndex = i
+		case "FLAC":
+			flacIndex = i
+		case "WAV":
+			wavIndex = i
+		}
+	}
+
+	if mpIndex == -1 || flacIndex == -1 || wavIndex == -1 {
+		return nil, errors.New(locales.Translate("common.err.metamapheader"))
+	}
+
+	// Read and process each row
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New(locales.Translate("common.err.metamapreadrow"))
+		}
+
+		// Skip empty rows
+		if len(record) == 0 || record[0] == "" {
+			continue
+		}
+
+		// Map the fields
+		internalName := record[0]
+		result.InternalToMP3[internalName] = record[mpIndex]
+		result.InternalToFLAC[internalName] = record[flacIndex]
+		result.InternalToWAV[internalName] = record[wavIndex]
+	}
+
+	return result, nil
+}
+
+// TagKey returns the tag key of an internal field name for the format of a file extension.
+//
+// Parameters:
+//   - extension: The file extension including the dot, e.g. ".flac"
+//   - field: The internal field name from the first column of the mapping
+//
+// Returns:
+//   - The tag key, empty if the field has no key in the format or the format is not mapped
+func (mm *MetadataMap) TagKey(extension string, field string) string {
+	switch strings.ToLower(extension) {
+	case ExtensionMP3:
+		return mm.InternalToMP3[field]
+	case ExtensionFLAC:
+		return mm.InternalToFLAC[field]
+	case ExtensionWAV:
+		return mm.InternalToWAV[field]
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,100 @@
+// common/metadata_report.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the per-file report of folder metadata processing and its export to CSV and JSON.
+
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// Results of a file processed by ProcessFolderMetadata.
+const (
+	// FileResultUpdated means at least one field of the track changes
+	FileResultUpdated = "updated"
+	// FileResultNoChange means the database already holds the values of the tags
+	FileResultNoChange = "nochange"
+	// FileResultSkippedZero means the file is empty and was skipped
+	FileResultSkippedZero = "skippedzero"
+	// FileResultMetadataError means the file or its tags cannot be read
+	FileResultMetadataError = "metadataerror"
+	// FileResultDbMiss means the file is not in the rekordbox collection
+	FileResultDbMiss = "dbmiss"
+	// FileResultUpdateError means the changes of the track cannot be prepared
+	FileResultUpdateError = "updateerror"
+)
+
+// FileReport is the result of one file processed by ProcessFolderMetadata.
+type FileReport struct {
+	Path       string   `json:"path"`
+	TrackID    string   `json:"trackId"`
+	Result     string   `json:"result"`     // One of the FileResult constants
+	Updated    []string `json:"updated"`    // Fields with changed values
+	NotUpdated []string `json:"notUpdated"` // Selected fields which keep their values
+	Reason     string   `json:"reason"`     // Error message of failed files
+}
+
+// FileReportColumns returns the values of a file report in the column order of the report table and CSV export.
+//
+// Parameters:
+//   - report: The file report
+//
+// Returns:
+//   - Path, track ID, result, updated fields, not updated fields and reason
+func FileReportColumns(report FileReport) []string {
+	return []string{
+		report.Path,
+		report.TrackID,
+		report.Result,
+		strings.Join(report.Updated, ", "),
+		strings.Join(report.NotUpdated, ", "),
+		report.Reason,
+	}
+}
+
+// ExportFileReports writes the per-file report of a run to a file. The format is chosen by the file extension:
+// ".json" produces a JSON array of file reports, anything else produces CSV with one line per file.
+//
+// Parameters:
+//   - path: The path of the export file
+//   - reports: The file reports of the run
+//
+// Returns:
+//   - An error if the file cannot be written
+func ExportFileReports(path string, reports []FileReport) error {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.reportexport"), err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.reportexport"), err)
+		}
+		return nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.reportexport"), err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"path", "trackId", "result", "updated", "notUpdated", "reason"})
+	for _, report := range reports {
+		writer.Write(FileReportColumns(report))
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.reportexport"), err)
+	}
+	return nil
+}
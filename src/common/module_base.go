@@ -8,6 +8,7 @@ package common
 import (
 	"MetaRekordFixer/locales"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync"
 
@@ -18,12 +19,16 @@ import (
 )
 
 // PlaylistItem represents a playlist item from Rekordbox database.
-// It contains the playlist's identifier, name, parent ID, and full path.
+// It contains the playlist's identifier, name, parent ID, full path and its place in the playlist tree.
 type PlaylistItem struct {
-	ID       string         // Unique identifier of the playlist
-	Name     string         // Display name of the playlist
-	ParentID sql.NullString // Parent playlist ID (nullable for root playlists)
-	Path     string         // Full path of the playlist including parent folders
+	ID         string         // Unique identifier of the playlist
+	Name       string         // Display name of the playlist
+	ParentID   sql.NullString // Parent playlist ID (nullable for root playlists)
+	Path       string         // Full path of the playlist including parent folders
+	Type       PlaylistType   // Folder, playlist or smart playlist
+	Seq        int            // Order of the playlist among its siblings
+	Depth      int            // Nesting level, 0 for top level playlists
+	TrackCount int            // Number of tracks, for folders the sum of all playlists below
 }
 
 // Module defines the interface that all modules must implement.
@@ -510,3 +515,87 @@ func (m *ModuleBase) HandleProcessCancellation(message string, params ...interfa
 	// Complete progress dialog and update UI
 	m.CompleteProgressDialog()
 }
+
+// ReviewAndApplyChanges lets the user review the changes collected by a module and writes them on approval.
+// It must be called from the processing goroutine while the progress dialog is shown.
+// If the changeset is empty, onApplied is called immediately with zero applied changes.
+// Otherwise the progress dialog is replaced by the review dialog; Apply writes the changes
+// in a single write session with a new progress dialog, Discard leaves the database untouched.
+// The write permission of the run is held by the review, so it survives the Finalize of the processing goroutine;
+// it is granted again only if the changes are applied.
+// Errors and cancellation while writing are reported here and onApplied is not called.
+//
+// Parameters:
+//   - moduleName: Name of the module used in error reports
+//   - title: Title of the progress dialog shown while the changes are written
+//   - cs: The changeset filled by the module
+//   - onApplied: Callback invoked with the number of written row changes after a successful commit
+func (m *ModuleBase) ReviewAndApplyChanges(moduleName string, title string, cs *Changeset, onApplied func(applied int)) {
+	writable := cs.DB().TakeWrites()
+	if cs.Len() == 0 {
+		onApplied(0)
+		return
+	}
+
+	// The module name is recorded with the changes in the change journal
+	cs.SetSource(moduleName)
+
+	// Stamp before the review, so the preview shows the USN and timestamps that will be written
+	if err := cs.Stamp(); err != nil {
+		m.CloseProgressDialog()
+		context := &ErrorContext{
+			Module:      moduleName,
+			Operation:   "Apply Changes",
+			Severity:    SeverityCritical,
+			Recoverable: false,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+		return
+	}
+
+	m.CloseProgressDialog()
+	m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.changesprepared"), cs.Len()))
+
+	ShowChangesetReviewDialog(m.Window, cs,
+		func() {
+			m.ShowProgressDialog(title)
+			m.StartProcessing(locales.Translate("common.status.updating"))
+
+			go func() {
+				// Close the writable connection when done, later reads reconnect read-only
+				defer cs.DB().Finalize()
+
+				if writable {
+					cs.DB().EnableWrites()
+				}
+
+				applied, err := cs.Apply(m.IsCancelled, func(applied, total int) {
+					m.UpdateProcessingProgress(applied, total, fmt.Sprintf(locales.Translate("common.status.progress"), applied, total))
+				})
+				if errors.Is(err, ErrCancelled) {
+					m.HandleProcessCancellation("common.status.stopped", 0, cs.Len())
+					m.AddWarningMessage(locales.Translate("common.status.rolledback"))
+					return
+				}
+				if err != nil {
+					m.CloseProgressDialog()
+					context := &ErrorContext{
+						Module:      moduleName,
+						Operation:   "Apply Changes",
+						Severity:    SeverityCritical,
+						Recoverable: false,
+					}
+					m.ErrorHandler.ShowStandardError(err, context)
+					m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+					m.AddWarningMessage(locales.Translate("common.status.rolledback"))
+					return
+				}
+				onApplied(applied)
+			}()
+		},
+		func() {
+			m.AddInfoMessage(locales.Translate("common.status.changesdiscarded"))
+		},
+	)
+}
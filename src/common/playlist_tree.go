@@ -0,0 +1,238 @@
+// common/playlist_tree.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the playlist tree model built from the djmdPlaylist table.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// PlaylistType identifies the kind of a djmdPlaylist row, stored in its Attribute column.
+type PlaylistType int
+
+const (
+	// PlaylistTypePlaylist is a regular playlist with tracks in djmdSongPlaylist
+	PlaylistTypePlaylist PlaylistType = 0
+
+	// PlaylistTypeFolder is a folder containing other playlists and folders
+	PlaylistTypeFolder PlaylistType = 1
+
+	// PlaylistTypeSmart is a smart playlist whose tracks are defined by conditions
+	PlaylistTypeSmart PlaylistType = 4
+)
+
+// PlaylistPathSeparator separates the names of parent folders in a playlist path.
+const PlaylistPathSeparator = " > "
+
+// PlaylistNode is a single node of the playlist tree.
+type PlaylistNode struct {
+	PlaylistItem
+	Parent   *PlaylistNode   // Parent folder, nil for top level nodes
+	Children []*PlaylistNode // Child nodes ordered by Seq
+}
+
+// PlaylistTree is the complete hierarchy of folders, playlists and smart playlists.
+type PlaylistTree struct {
+	Roots []*PlaylistNode          // Top level nodes ordered by Seq
+	byID  map[string]*PlaylistNode // Lookup of all nodes by ID
+}
+
+// IsFolder reports whether the playlist item is a folder.
+func (p PlaylistItem) IsFolder() bool {
+	return p.Type == PlaylistTypeFolder
+}
+
+// IsSmart reports whether the playlist item is a smart playlist.
+func (p PlaylistItem) IsSmart() bool {
+	return p.Type == PlaylistTypeSmart
+}
+
+// Selectable reports whether the playlist item can be used as a source of tracks.
+// Folders only group other playlists and hold no tracks of their own.
+func (p PlaylistItem) Selectable() bool {
+	return !p.IsFolder()
+}
+
+// Node returns the node with the given ID.
+//
+// Parameters:
+//   - id: The ID of the playlist
+//
+// Returns:
+//   - The node, or nil if the tree contains no playlist with this ID
+func (t *PlaylistTree) Node(id string) *PlaylistNode {
+	return t.byID[id]
+}
+
+// Walk visits all nodes depth-first in the order they are shown in rekordbox.
+// Children of a node are skipped when visit returns false.
+//
+// Parameters:
+//   - visit: Function called for every node
+func (t *PlaylistTree) Walk(visit func(node *PlaylistNode) bool) {
+	var walk func(nodes []*PlaylistNode)
+	walk = func(nodes []*PlaylistNode) {
+		for _, node := range nodes {
+			if visit(node) {
+				walk(node.Children)
+			}
+		}
+	}
+	walk(t.Roots)
+}
+
+// Items returns all nodes of the tree as a flat list in display order.
+//
+// Returns:
+//   - A slice of PlaylistItem structures
+func (t *PlaylistTree) Items() []PlaylistItem {
+	items := make([]PlaylistItem, 0, len(t.byID))
+	t.Walk(func(node *PlaylistNode) bool {
+		items = append(items, node.PlaylistItem)
+		return true
+	})
+	return items
+}
+
+// GetPlaylistTree loads all folders, playlists and smart playlists with their full hierarchy.
+// Every node carries its type, full path, depth, Seq order and number of tracks;
+// the track counts of smart playlists are evaluated from their conditions in a single query,
+// the track count of a folder is the sum of the playlists below it.
+// Rows whose parent does not exist are shown at the top level, cycles in ParentID are broken.
+//
+// Returns:
+//   - The playlist tree and nil if successful
+//   - nil and an error if the database is not connected or the query fails
+func (m *DBManager) GetPlaylistTree() (*PlaylistTree, error) {
+	err := m.EnsureConnected(false)
+	if err != nil {
+		return nil, err // EnsureConnected (and thus Connect) already provides a localized error.
+	}
+
+	rows, err := m.Query("SELECT ID, Name, ParentID, Seq, Attribute, SmartList FROM djmdPlaylist")
+	if err != nil {
+		return nil, fmt.Errorf(locales.Translate("common.err.playlistload"), err)
+	}
+	defer rows.Close()
+
+	tree := &PlaylistTree{byID: make(map[string]*PlaylistNode)}
+	var nodes, smartNodes []*PlaylistNode
+	smartLists := make(map[string]string)
+	for rows.Next() {
+		var name, smartList NullString
+		var seq, attribute NullInt64
+		node := &PlaylistNode{}
+		if err := rows.Scan(&node.ID, &name, &node.ParentID, &seq, &attribute, &smartList); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
+		}
+		node.Name = name.String
+		node.Seq = int(seq.Int64)
+		node.Type = PlaylistType(attribute.Int64)
+		if node.IsSmart() {
+			smartNodes = append(smartNodes, node)
+			smartLists[node.ID] = smartList.String
+		}
+		tree.byID[node.ID] = node
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
+	}
+
+	counts, err := m.playlistTrackCounts()
+	if err != nil {
+		return nil, err
+	}
+	smartCounts := m.smartPlaylistTrackCounts(smartNodes, smartLists)
+
+	// Link nodes to their parents, rekordbox uses "root" as ParentID of top level nodes
+	for _, node := range nodes {
+		parent := tree.byID[node.ParentID.String]
+		if !node.ParentID.Valid || parent == nil || parent == node || isPlaylistAncestor(node, parent) {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	sortPlaylistNodes(tree.Roots)
+	var finish func(nodes []*PlaylistNode, parentPath string, depth int) int
+	finish = func(nodes []*PlaylistNode, parentPath string, depth int) int {
+		total := 0
+		for _, node := range nodes {
+			node.Depth = depth
+			node.Path = node.Name
+			if parentPath != "" {
+				node.Path = parentPath + PlaylistPathSeparator + node.Name
+			}
+			sortPlaylistNodes(node.Children)
+			childTotal := finish(node.Children, node.Path, depth+1)
+			switch node.Type {
+			case PlaylistTypeFolder:
+				node.TrackCount = childTotal
+			case PlaylistTypeSmart:
+				node.TrackCount = smartCounts[node.ID]
+			default:
+				node.TrackCount = counts[node.ID]
+			}
+			total += node.TrackCount
+		}
+		return total
+	}
+	finish(tree.Roots, "", 0)
+
+	return tree, nil
+}
+
+// playlistTrackCounts returns the number of entries of every playlist in djmdSongPlaylist.
+func (m *DBManager) playlistTrackCounts() (map[string]int, error) {
+	rows, err := m.Query("SELECT PlaylistID, COUNT(*) FROM djmdSongPlaylist GROUP BY PlaylistID")
+	if err != nil {
+		return nil, fmt.Errorf(locales.Translate("common.err.playlistload"), err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var playlistID NullString
+		var count int
+		if err := rows.Scan(&playlistID, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
+		}
+		counts[playlistID.String] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.dbplaylistscan"), err)
+	}
+	return counts, nil
+}
+
+// isPlaylistAncestor reports whether node is reachable by following the parents linked so far from start.
+// Linking node below start would then close a cycle.
+func isPlaylistAncestor(node, start *PlaylistNode) bool {
+	seen := make(map[*PlaylistNode]bool)
+	for current := start; current != nil && !seen[current]; current = current.Parent {
+		if current == node {
+			return true
+		}
+		seen[current] = true
+	}
+	return false
+}
+
+// sortPlaylistNodes orders sibling nodes by Seq, ties are ordered by name.
+func sortPlaylistNodes(nodes []*PlaylistNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Seq != nodes[j].Seq {
+			return nodes[i].Seq < nodes[j].Seq
+		}
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+}
@@ -0,0 +1,268 @@
+// common/playlist_writer.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the playlist write primitives: creating folders and playlists, ordering them
+// and maintaining their track entries. Like all other writes, they are recorded in a changeset.
+
+package common
+
+import (
+	"fmt"
+
+	"MetaRekordFixer/locales"
+)
+
+// PlaylistRootID is the ParentID rekordbox uses for top level folders and playlists.
+const PlaylistRootID = "root"
+
+// PlaylistEntry is a single track entry of a playlist.
+type PlaylistEntry struct {
+	ID         string // ID of the djmdSongPlaylist row
+	ContentID  string // ID of the track in djmdContent
+	TrackNo    int    // Position of the track in the playlist, starting at 1
+	FolderPath string // Path of the track file, empty if the track no longer exists
+}
+
+// GetPlaylistEntries returns the track entries of a regular playlist ordered by TrackNo.
+//
+// Parameters:
+//   - playlistID: The ID of the playlist
+//
+// Returns:
+//   - A slice of PlaylistEntry structures and nil if successful
+//   - nil and an error if the query fails
+func (m *DBManager) GetPlaylistEntries(playlistID string) ([]PlaylistEntry, error) {
+	err := m.EnsureConnected(false)
+	if err != nil {
+		return nil, fmt.Errorf(locales.Translate("common.err.dbconnect"), err)
+	}
+
+	rows, err := m.Query(`
+		SELECT sp.ID, COALESCE(sp.ContentID, ''), COALESCE(sp.TrackNo, 0), COALESCE(c.FolderPath, '')
+		FROM djmdSongPlaylist sp
+		LEFT JOIN djmdContent c ON c.ID = sp.ContentID
+		WHERE sp.PlaylistID = ?
+		ORDER BY sp.TrackNo, sp.ID`, playlistID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.playlistentries"), err)
+	}
+	defer rows.Close()
+
+	var entries []PlaylistEntry
+	for rows.Next() {
+		var entry PlaylistEntry
+		if err := rows.Scan(&entry.ID, &entry.ContentID, &entry.TrackNo, &entry.FolderPath); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.playlistentries"), err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.playlistentries"), err)
+	}
+
+	return entries, nil
+}
+
+// CreatePlaylist records the creation of a folder or a regular playlist.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - parentID: The ID of the parent folder, empty for the top level
+//   - name: The name of the new folder or playlist
+//   - playlistType: PlaylistTypeFolder or PlaylistTypePlaylist
+//   - seq: The position among its siblings
+//
+// Returns:
+//   - The ID of the new folder or playlist
+//   - An error if no ID can be allocated
+func CreatePlaylist(cs *Changeset, parentID, name string, playlistType PlaylistType, seq int) (string, error) {
+	if parentID == "" {
+		parentID = PlaylistRootID
+	}
+
+	id, err := cs.InsertNew(SQLTableDJMDPlaylist, name,
+		FieldChange{Column: "Name", New: name},
+		FieldChange{Column: "ParentID", New: parentID},
+		FieldChange{Column: "Seq", New: int64(seq)},
+		FieldChange{Column: "Attribute", New: int64(playlistType)},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	cs.DB().logger.Info("Playlist prepared for creation: %s (parent %s)", name, parentID)
+	return id, nil
+}
+
+// SetPlaylistSeq records a new position of a folder or playlist among its siblings.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - playlistID: The ID of the folder or playlist
+//   - label: Human readable description of the playlist
+//   - seq: The new position
+//
+// Returns:
+//   - true if the position changes
+//   - An error if the current position cannot be read
+func SetPlaylistSeq(cs *Changeset, playlistID, label string, seq int) (bool, error) {
+	return cs.Update(SQLTableDJMDPlaylist, playlistID, label, FieldChange{Column: "Seq", New: int64(seq)})
+}
+
+// NextPlaylistSeq returns the position after the last child of a folder.
+//
+// Parameters:
+//   - dbMgr: The database manager instance
+//   - parentID: The ID of the folder, empty for the top level
+//
+// Returns:
+//   - The next free position
+//   - An error if the query fails
+func NextPlaylistSeq(dbMgr *DBManager, parentID string) (int, error) {
+	if parentID == "" {
+		parentID = PlaylistRootID
+	}
+
+	row := dbMgr.QueryRow("SELECT COALESCE(MAX(Seq), 0) FROM djmdPlaylist WHERE ParentID = ?", parentID)
+	if row == nil {
+		return 0, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	var seq int
+	if err := row.Scan(&seq); err != nil {
+		return 0, fmt.Errorf("%s: %w", locales.Translate("common.err.dbquery"), err)
+	}
+	return seq + 1, nil
+}
+
+// AddPlaylistEntry records the addition of a track to a regular playlist.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - playlistID: The ID of the playlist
+//   - contentID: The ID of the track
+//   - trackNo: The position of the track in the playlist, starting at 1
+//   - label: Human readable description of the entry
+//
+// Returns:
+//   - The ID of the new entry
+//   - An error if no ID can be allocated
+func AddPlaylistEntry(cs *Changeset, playlistID, contentID string, trackNo int, label string) (string, error) {
+	return cs.InsertNew(SQLTableDJMDSongPlaylist, label,
+		FieldChange{Column: "PlaylistID", New: playlistID},
+		FieldChange{Column: "ContentID", New: contentID},
+		FieldChange{Column: "TrackNo", New: int64(trackNo)},
+	)
+}
+
+// SetPlaylistEntryTrackNo records a new position of a track entry in its playlist.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - entry: The entry with its current position
+//   - trackNo: The new position, starting at 1
+//   - label: Human readable description of the entry
+//
+// Returns:
+//   - true if the position changes
+func SetPlaylistEntryTrackNo(cs *Changeset, entry PlaylistEntry, trackNo int, label string) bool {
+	return cs.UpdateKnown(SQLTableDJMDSongPlaylist, "ID", entry.ID, label,
+		FieldChange{Column: "TrackNo", Old: int64(entry.TrackNo), New: int64(trackNo)},
+	)
+}
+
+// RemovePlaylistEntry records the removal of a track entry from its playlist.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - entryID: The ID of the djmdSongPlaylist row
+//   - label: Human readable description of the entry
+func RemovePlaylistEntry(cs *Changeset, entryID, label string) {
+	cs.Delete(SQLTableDJMDSongPlaylist, entryID, label)
+}
+
+// SyncPlaylistEntries records the changes needed to make a regular playlist contain exactly the given tracks
+// in the given order. Existing entries are reused and renumbered, missing tracks are added and
+// entries of tracks not in the list are removed. Duplicate track IDs are added only once.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - playlistID: The ID of the playlist, may be a playlist created by the same changeset
+//   - label: Human readable description of the playlist
+//   - contentIDs: The IDs of the tracks in their desired order
+//
+// Returns:
+//   - The number of added, removed and renumbered entries
+//   - An error if the current entries cannot be read or no ID can be allocated
+func SyncPlaylistEntries(cs *Changeset, playlistID, label string, contentIDs []string) (added, removed, moved int, err error) {
+	entries, err := cs.DB().GetPlaylistEntries(playlistID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	// Existing entries per track, a track listed twice keeps its first entry
+	existing := make(map[string]PlaylistEntry)
+	for _, entry := range entries {
+		if _, ok := existing[entry.ContentID]; !ok {
+			existing[entry.ContentID] = entry
+		}
+	}
+
+	used := make(map[string]bool)
+	trackNo := 0
+	for _, contentID := range contentIDs {
+		if used[contentID] {
+			continue
+		}
+		used[contentID] = true
+		trackNo++
+
+		if entry, ok := existing[contentID]; ok {
+			if SetPlaylistEntryTrackNo(cs, entry, trackNo, label) {
+				moved++
+			}
+			continue
+		}
+		if _, err := AddPlaylistEntry(cs, playlistID, contentID, trackNo, label); err != nil {
+			return added, removed, moved, err
+		}
+		added++
+	}
+
+	for _, entry := range entries {
+		if keep, ok := existing[entry.ContentID]; ok && keep.ID == entry.ID && used[entry.ContentID] {
+			continue
+		}
+		RemovePlaylistEntry(cs, entry.ID, label)
+		removed++
+	}
+
+	return added, removed, moved, nil
+}
+
+// DeletePlaylist records the removal of a folder or playlist including all its entries and,
+// for folders, all folders and playlists below it.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - node: The node of the playlist tree to remove
+//
+// Returns:
+//   - An error if the entries of a playlist cannot be read
+func DeletePlaylist(cs *Changeset, node *PlaylistNode) error {
+	for _, child := range node.Children {
+		if err := DeletePlaylist(cs, child); err != nil {
+			return err
+		}
+	}
+
+	entries, err := cs.DB().GetPlaylistEntries(node.ID)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		RemovePlaylistEntry(cs, entry.ID, node.Path)
+	}
+
+	cs.Delete(SQLTableDJMDPlaylist, node.ID, node.Path)
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+// common/process_lister_darwin.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains macOS-specific listing of running processes.
+
+package common
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// listProcessNames returns the command names of all running processes using ps.
+func listProcessNames() ([]string, error) {
+	output, err := exec.Command("ps", "-axco", "comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,38 @@
+//go:build windows
+
+// common/process_lister_windows.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains Windows-specific listing of running processes.
+
+package common
+
+import (
+	"encoding/csv"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// listProcessNames returns the image names of all running processes using tasklist.
+func listProcessNames() ([]string, error) {
+	cmd := exec.Command("tasklist", "/FO", "CSV", "/NH")
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(strings.NewReader(string(output))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(records))
+	for _, record := range records {
+		if len(record) > 0 {
+			names = append(names, record[0])
+		}
+	}
+	return names, nil
+}
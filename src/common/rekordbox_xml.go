@@ -0,0 +1,778 @@
+// common/rekordbox_xml.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the export of the collection and playlists to the rekordbox XML library format
+// (rekordbox.xml) and the import of cues and metadata from such a file.
+
+package common
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// XMLLibraryVersion is the version of the rekordbox XML format written by the export.
+const XMLLibraryVersion = "1.0.0"
+
+const (
+	// xmlNodeFolder is the NODE Type of a folder in the PLAYLISTS section
+	xmlNodeFolder = 0
+
+	// xmlNodePlaylist is the NODE Type of a playlist in the PLAYLISTS section
+	xmlNodePlaylist = 1
+
+	// xmlMarkCue is the POSITION_MARK Type of a cue point
+	xmlMarkCue = 0
+
+	// xmlMarkLoop is the POSITION_MARK Type of a loop
+	xmlMarkLoop = 4
+
+	// cueFramesPerSecond is the resolution of the InFrame and OutFrame columns of djmdCue
+	cueFramesPerSecond = 150
+)
+
+// hotCueKinds maps hot cue numbers (A = 0, B = 1, ...) to djmdCue.Kind, memory cues have Kind 0.
+// rekordbox skips Kind 4 for historical reasons.
+var hotCueKinds = []int{1, 2, 3, 5, 6, 7, 8, 9}
+
+// XMLLibrary is the root element (DJ_PLAYLISTS) of a rekordbox XML file.
+type XMLLibrary struct {
+	XMLName    xml.Name      `xml:"DJ_PLAYLISTS"`
+	Version    string        `xml:"Version,attr"`
+	Product    XMLProduct    `xml:"PRODUCT"`
+	Collection XMLCollection `xml:"COLLECTION"`
+	Playlists  XMLPlaylists  `xml:"PLAYLISTS"`
+}
+
+// XMLInt is a numeric attribute of a rekordbox XML file. Other applications write empty attributes
+// or decimal numbers (e.g. BitRate="" or TotalTime="245.0"); they are read leniently instead of failing the whole file.
+type XMLInt int64
+
+// UnmarshalXMLAttr parses the attribute as an integer. Decimal numbers are truncated,
+// empty and unparsable values read as 0.
+func (n *XMLInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	value := strings.TrimSpace(attr.Value)
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		*n = XMLInt(number)
+		return nil
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(number) && !math.IsInf(number, 0) {
+		*n = XMLInt(number)
+		return nil
+	}
+	*n = 0
+	return nil
+}
+
+// XMLProduct identifies the application that wrote the file.
+type XMLProduct struct {
+	Name    string `xml:"Name,attr"`
+	Version string `xml:"Version,attr"`
+	Company string `xml:"Company,attr"`
+}
+
+// XMLCollection holds all exported tracks.
+type XMLCollection struct {
+	Entries XMLInt     `xml:"Entries,attr"`
+	Tracks  []XMLTrack `xml:"TRACK"`
+}
+
+// XMLTrack is a single track of the collection with its beat grid and cue points.
+type XMLTrack struct {
+	TrackID     string            `xml:"TrackID,attr"`
+	Name        string            `xml:"Name,attr"`
+	Artist      string            `xml:"Artist,attr"`
+	Composer    string            `xml:"Composer,attr"`
+	Album       string            `xml:"Album,attr"`
+	Grouping    string            `xml:"Grouping,attr"`
+	Genre       string            `xml:"Genre,attr"`
+	Kind        string            `xml:"Kind,attr"`
+	Size        XMLInt            `xml:"Size,attr"`
+	TotalTime   XMLInt            `xml:"TotalTime,attr"`
+	DiscNumber  XMLInt            `xml:"DiscNumber,attr"`
+	TrackNumber XMLInt            `xml:"TrackNumber,attr"`
+	Year        XMLInt            `xml:"Year,attr"`
+	AverageBpm  string            `xml:"AverageBpm,attr"`
+	DateAdded   string            `xml:"DateAdded,attr"`
+	BitRate     XMLInt            `xml:"BitRate,attr"`
+	SampleRate  XMLInt            `xml:"SampleRate,attr"`
+	Comments    string            `xml:"Comments,attr"`
+	PlayCount   XMLInt            `xml:"PlayCount,attr"`
+	Rating      XMLInt            `xml:"Rating,attr"`
+	Location    string            `xml:"Location,attr"`
+	Remixer     string            `xml:"Remixer,attr"`
+	Tonality    string            `xml:"Tonality,attr"`
+	Label       string            `xml:"Label,attr"`
+	Mix         string            `xml:"Mix,attr"`
+	Tempos      []XMLTempo        `xml:"TEMPO"`
+	Marks       []XMLPositionMark `xml:"POSITION_MARK"`
+}
+
+// XMLTempo is a beat grid anchor of a track.
+type XMLTempo struct {
+	Inizio  string `xml:"Inizio,attr"`
+	Bpm     string `xml:"Bpm,attr"`
+	Metro   string `xml:"Metro,attr"`
+	Battito string `xml:"Battito,attr"`
+}
+
+// XMLPositionMark is a memory cue, hot cue or loop of a track. Start and End are in seconds,
+// Num is the hot cue number (0 = A) or -1 for memory cues.
+type XMLPositionMark struct {
+	Name  string `xml:"Name,attr"`
+	Type  XMLInt `xml:"Type,attr"`
+	Start string `xml:"Start,attr"`
+	End   string `xml:"End,attr,omitempty"`
+	Num   XMLInt `xml:"Num,attr"`
+}
+
+// XMLPlaylists holds the playlist tree below its ROOT node.
+type XMLPlaylists struct {
+	Root XMLNode `xml:"NODE"`
+}
+
+// XMLNode is a folder (Type 0) or playlist (Type 1) of the playlist tree.
+type XMLNode struct {
+	Type    XMLInt             `xml:"Type,attr"`
+	Name    string             `xml:"Name,attr"`
+	Count   *XMLInt            `xml:"Count,attr,omitempty"`
+	KeyType *XMLInt            `xml:"KeyType,attr,omitempty"`
+	Entries *XMLInt            `xml:"Entries,attr,omitempty"`
+	Nodes   []XMLNode          `xml:"NODE"`
+	Tracks  []XMLPlaylistTrack `xml:"TRACK"`
+}
+
+// XMLPlaylistTrack references a collection track by its TrackID.
+type XMLPlaylistTrack struct {
+	Key string `xml:"Key,attr"`
+}
+
+// XMLImportOptions selects what is imported from a rekordbox XML file.
+type XMLImportOptions struct {
+	Cues     bool // Import memory cues, hot cues and loops
+	Metadata bool // Import title, artist, album, genre, comments, rating, year, track and disc numbers
+}
+
+// XMLImportSummary holds aggregated metrics of an XML import.
+type XMLImportSummary struct {
+	Tracks        int      // Tracks in the XML collection
+	Matched       int      // Tracks found in the database by their location
+	TracksChanged int      // Matched tracks with changed metadata
+	CuesAdded     int      // Inserted cues
+	CuesReplaced  int      // Hot cues replaced by a different cue of the same slot
+	Unmatched     []string // Locations of tracks not found in the database
+}
+
+// BuildRekordboxXML reads the collection and the playlist tree into the rekordbox XML structure.
+// Smart playlists are exported as regular playlists with their current tracks.
+// Beat grids are stored in the analysis files, not in the database, so no TEMPO elements are written.
+//
+// Parameters:
+//   - dbMgr: The database manager instance
+//   - playlistID: The folder or playlist to export, empty to export the whole collection and all playlists
+//
+// Returns:
+//   - The XML library and nil if successful
+//   - nil and an error if the database cannot be read
+func BuildRekordboxXML(dbMgr *DBManager, playlistID string) (*XMLLibrary, error) {
+	tree, err := dbMgr.GetPlaylistTree()
+	if err != nil {
+		return nil, err
+	}
+
+	roots := tree.Roots
+	if playlistID != "" {
+		node := tree.Node(playlistID)
+		if node == nil {
+			return nil, fmt.Errorf("%s: %s", locales.Translate("common.err.xmlplaylist"), playlistID)
+		}
+		roots = []*PlaylistNode{node}
+	}
+
+	// Playlists are built first, so a partial export knows which tracks it needs
+	used := make(map[string]bool)
+	var buildNode func(node *PlaylistNode) (XMLNode, error)
+	buildNode = func(node *PlaylistNode) (XMLNode, error) {
+		if node.IsFolder() {
+			folder := XMLNode{Type: xmlNodeFolder, Name: node.Name}
+			for _, child := range node.Children {
+				childNode, err := buildNode(child)
+				if err != nil {
+					return XMLNode{}, err
+				}
+				folder.Nodes = append(folder.Nodes, childNode)
+			}
+			count := XMLInt(len(folder.Nodes))
+			folder.Count = &count
+			return folder, nil
+		}
+
+		var contentIDs []string
+		if node.IsSmart() {
+			tracks, err := dbMgr.GetTracksBasedOnPlaylist(node.ID)
+			if err != nil {
+				return XMLNode{}, err
+			}
+			for _, track := range tracks {
+				contentIDs = append(contentIDs, track.ID)
+			}
+		} else {
+			entries, err := dbMgr.GetPlaylistEntries(node.ID)
+			if err != nil {
+				return XMLNode{}, err
+			}
+			for _, entry := range entries {
+				if entry.FolderPath != "" {
+					contentIDs = append(contentIDs, entry.ContentID)
+				}
+			}
+		}
+
+		keyType := XMLInt(0)
+		playlist := XMLNode{Type: xmlNodePlaylist, Name: node.Name, KeyType: &keyType}
+		for _, contentID := range contentIDs {
+			playlist.Tracks = append(playlist.Tracks, XMLPlaylistTrack{Key: contentID})
+			used[contentID] = true
+		}
+		entries := XMLInt(len(playlist.Tracks))
+		playlist.Entries = &entries
+		return playlist, nil
+	}
+
+	root := XMLNode{Type: xmlNodeFolder, Name: "ROOT"}
+	for _, node := range roots {
+		xmlNode, err := buildNode(node)
+		if err != nil {
+			return nil, err
+		}
+		root.Nodes = append(root.Nodes, xmlNode)
+	}
+	count := XMLInt(len(root.Nodes))
+	root.Count = &count
+
+	var only map[string]bool
+	if playlistID != "" {
+		only = used
+	}
+	tracks, err := loadXMLTracks(dbMgr, only)
+	if err != nil {
+		return nil, err
+	}
+
+	return &XMLLibrary{
+		Version:    XMLLibraryVersion,
+		Product:    XMLProduct{Name: AppName, Version: "", Company: ""},
+		Collection: XMLCollection{Entries: XMLInt(len(tracks)), Tracks: tracks},
+		Playlists:  XMLPlaylists{Root: root},
+	}, nil
+}
+
+// loadXMLTracks reads tracks and their cues into XML track elements.
+// If only is not nil, only tracks with IDs in only are read.
+func loadXMLTracks(dbMgr *DBManager, only map[string]bool) ([]XMLTrack, error) {
+	rows, err := dbMgr.Query(`
+		SELECT c.ID, COALESCE(c.Title, ''), COALESCE(a.Name, ''), COALESCE(cp.Name, ''), COALESCE(al.Name, ''),
+			COALESCE(g.Name, ''), COALESCE(c.FileType, 0), COALESCE(c.FileSize, 0), COALESCE(c.Length, 0),
+			COALESCE(c.DiscNo, 0), COALESCE(c.TrackNo, 0), COALESCE(c.ReleaseYear, 0), COALESCE(c.BPM, 0),
+			COALESCE(c.StockDate, ''), COALESCE(c.BitRate, 0), COALESCE(c.SampleRate, 0), COALESCE(c.Commnt, ''),
+			COALESCE(c.DJPlayCount, 0), COALESCE(c.Rating, 0), COALESCE(c.FolderPath, ''), COALESCE(r.Name, ''),
+			COALESCE(k.ScaleName, ''), COALESCE(l.Name, ''), COALESCE(c.Subtitle, '')
+		FROM djmdContent c
+		LEFT JOIN djmdArtist a ON a.ID = c.ArtistID
+		LEFT JOIN djmdArtist cp ON cp.ID = c.ComposerID
+		LEFT JOIN djmdArtist r ON r.ID = c.RemixerID
+		LEFT JOIN djmdAlbum al ON al.ID = c.AlbumID
+		LEFT JOIN djmdGenre g ON g.ID = c.GenreID
+		LEFT JOIN djmdKey k ON k.ID = c.KeyID
+		LEFT JOIN djmdLabel l ON l.ID = c.LabelID
+		ORDER BY c.FolderPath`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+	defer rows.Close()
+
+	var tracks []XMLTrack
+	index := make(map[string]int)
+	for rows.Next() {
+		var track XMLTrack
+		var fileType, bpm int
+		var folderPath, stockDate string
+		if err := rows.Scan(&track.TrackID, &track.Name, &track.Artist, &track.Composer, &track.Album,
+			&track.Genre, &fileType, &track.Size, &track.TotalTime,
+			&track.DiscNumber, &track.TrackNumber, &track.Year, &bpm,
+			&stockDate, &track.BitRate, &track.SampleRate, &track.Comments,
+			&track.PlayCount, &track.Rating, &folderPath, &track.Remixer,
+			&track.Tonality, &track.Label, &track.Mix); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+		}
+		if only != nil && !only[track.TrackID] {
+			continue
+		}
+		track.Kind = xmlFileKind(fileType)
+		track.AverageBpm = fmt.Sprintf("%.2f", float64(bpm)/100)
+		track.DateAdded = stockDate
+		track.Rating = XMLInt(xmlRating(int(track.Rating)))
+		track.Location = xmlLocation(folderPath)
+		index[track.TrackID] = len(tracks)
+		tracks = append(tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+
+	cueRows, err := dbMgr.Query("SELECT ContentID, COALESCE(InMsec, 0), COALESCE(OutMsec, -1), COALESCE(Kind, 0), COALESCE(Comment, '') FROM djmdCue ORDER BY ContentID, Kind, InMsec")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+	defer cueRows.Close()
+
+	for cueRows.Next() {
+		var contentID, comment string
+		var inMsec, outMsec int64
+		var kind int
+		if err := cueRows.Scan(&contentID, &inMsec, &outMsec, &kind, &comment); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+		}
+		pos, ok := index[contentID]
+		if !ok {
+			continue
+		}
+		mark := XMLPositionMark{Name: comment, Type: xmlMarkCue, Start: xmlSeconds(inMsec), Num: XMLInt(hotCueNum(kind))}
+		if outMsec > inMsec {
+			mark.Type = xmlMarkLoop
+			mark.End = xmlSeconds(outMsec)
+		}
+		tracks[pos].Marks = append(tracks[pos].Marks, mark)
+	}
+	if err := cueRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+
+	return tracks, nil
+}
+
+// WriteRekordboxXML writes the XML library to a file.
+//
+// Parameters:
+//   - path: The path of the XML file
+//   - library: The library to write
+//
+// Returns:
+//   - An error if the file cannot be written
+func WriteRekordboxXML(path string, library *XMLLibrary) error {
+	data, err := xml.MarshalIndent(library, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.xmlwrite"), err)
+	}
+	data = append([]byte(xml.Header), data...)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.xmlwrite"), err)
+	}
+	return nil
+}
+
+// ReadRekordboxXML reads a rekordbox XML file.
+//
+// Parameters:
+//   - path: The path of the XML file
+//
+// Returns:
+//   - The XML library and nil if successful
+//   - nil and an error if the file cannot be read or is not a rekordbox XML file
+func ReadRekordboxXML(path string) (*XMLLibrary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+
+	var library XMLLibrary
+	if err := xml.Unmarshal(data, &library); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+	return &library, nil
+}
+
+// PrepareXMLImport records the changes needed to import cues and metadata of the XML tracks into
+// the matching database tracks. Tracks are matched by their location. Nothing is written to the database;
+// the review of the changeset is the diff report of the import.
+//
+// Hot cues replace the cue of the same slot if it differs, memory cues and loops are added
+// unless a cue of the same kind already exists at the same position. Metadata is only taken
+// from attributes that are set in the XML file.
+//
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - library: The XML library to import
+//   - options: What to import
+//   - isCancelled: Function reporting whether the user cancelled the run (can be nil)
+//
+// Returns:
+//   - XMLImportSummary with counters and unmatched locations
+//   - An error if the database cannot be read or the run was cancelled
+func PrepareXMLImport(cs *Changeset, library *XMLLibrary, options XMLImportOptions, isCancelled func() bool) (XMLImportSummary, error) {
+	dbMgr := cs.DB()
+	summary := XMLImportSummary{Tracks: len(library.Collection.Tracks)}
+
+	rows, err := dbMgr.Query("SELECT ID, FolderPath FROM djmdContent")
+	if err != nil {
+		return summary, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+	}
+	trackMap := make(map[string]string)
+	for rows.Next() {
+		var id string
+		var folderPath NullString
+		if err := rows.Scan(&id, &folderPath); err != nil {
+			rows.Close()
+			return summary, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+		}
+		trackMap[NormalizePath(folderPath.String)] = id
+	}
+	rows.Close()
+
+	// Cues of every track as they will be after the import, tracks may appear several times in the file
+	trackCues := make(map[string]*xmlTrackCues)
+
+	for _, track := range library.Collection.Tracks {
+		if isCancelled != nil && isCancelled() {
+			return summary, ErrCancelled
+		}
+
+		path, err := pathFromXMLLocation(track.Location)
+		trackID, ok := trackMap[NormalizePath(path)]
+		if err != nil || path == "" || !ok {
+			summary.Unmatched = append(summary.Unmatched, track.Location)
+			continue
+		}
+		summary.Matched++
+
+		if options.Metadata {
+			changed, err := importXMLMetadata(cs, trackID, track)
+			if err != nil {
+				return summary, err
+			}
+			if changed {
+				summary.TracksChanged++
+			}
+		}
+
+		if options.Cues && len(track.Marks) > 0 {
+			added, replaced, err := importXMLMarks(cs, trackID, track, trackCues)
+			if err != nil {
+				return summary, err
+			}
+			summary.CuesAdded += added
+			summary.CuesReplaced += replaced
+		}
+	}
+
+	return summary, nil
+}
+
+// importXMLMetadata records the metadata of an XML track set in the file into the changeset.
+func importXMLMetadata(cs *Changeset, trackID string, track XMLTrack) (bool, error) {
+	label := track.Name
+	var fields []FieldChange
+
+	if track.Name != "" {
+		fields = append(fields, FieldChange{Column: "Title", New: track.Name})
+	}
+	if track.Comments != "" {
+		fields = append(fields, FieldChange{Column: "Commnt", New: track.Comments})
+	}
+	if track.Mix != "" {
+		fields = append(fields, FieldChange{Column: "Subtitle", New: track.Mix})
+	}
+	if track.Rating > 0 {
+		fields = append(fields, FieldChange{Column: "Rating", New: int64(ratingFromXML(int(track.Rating)))})
+	}
+	if track.Year > 0 {
+		fields = append(fields, FieldChange{Column: "ReleaseYear", New: int64(track.Year)})
+	}
+	if track.TrackNumber > 0 {
+		fields = append(fields, FieldChange{Column: "TrackNo", New: int64(track.TrackNumber)})
+	}
+	if track.DiscNumber > 0 {
+		fields = append(fields, FieldChange{Column: "DiscNo", New: int64(track.DiscNumber)})
+	}
+
+	artistID, err := AddOrGetArtist(cs, track.Artist)
+	if err != nil {
+		return false, err
+	}
+	if artistID != "" {
+		fields = append(fields, FieldChange{Column: "ArtistID", New: artistID})
+	}
+	albumID, err := xmlAlbumID(cs, trackID, track.Album, artistID)
+	if err != nil {
+		return false, err
+	}
+	if albumID != "" {
+		fields = append(fields, FieldChange{Column: "AlbumID", New: albumID})
+	}
+	genreID, err := AddOrGetGenre(cs, track.Genre)
+	if err != nil {
+		return false, err
+	}
+	if genreID != "" {
+		fields = append(fields, FieldChange{Column: "GenreID", New: genreID})
+	}
+
+	return cs.Update(SQLTableDJMDContent, trackID, label, fields...)
+}
+
+// xmlAlbumID returns the album of an XML track. rekordbox XML has no album artist, the artist of the track
+// takes its place: the current album of the track is kept if it has the same name, otherwise the album
+// of the same name by the artist (or without album artist) is used or created, like ImportTrack does.
+// Albums of the same name by other album artists are never used.
+func xmlAlbumID(cs *Changeset, trackID string, albumName string, artistID string) (string, error) {
+	if albumName == "" {
+		return "", nil
+	}
+	dbMgr := cs.DB()
+
+	row := dbMgr.QueryRow(`SELECT a.ID FROM djmdContent c JOIN djmdAlbum a ON a.ID = c.AlbumID
+		WHERE c.ID = ? AND a.Name = ? COLLATE NOCASE`, trackID, albumName)
+	if row == nil {
+		return "", fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+	}
+	var albumID string
+	err := row.Scan(&albumID)
+	if err == nil {
+		return albumID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("%s: %w", locales.Translate("common.err.dbalbumcheck"), err)
+	}
+
+	return AddOrGetAlbum(cs, albumName, artistID)
+}
+
+// xmlTrackCues holds the cues of a track as they will be after the changeset is applied.
+type xmlTrackCues struct {
+	cues  []map[string]interface{} // Existing cues not deleted by the changeset and the inserted cues
+	taken map[int]bool             // Hot cue slots (djmdCue.Kind) already set by the XML file
+}
+
+// importXMLMarks records the cues of an XML track into the changeset.
+// The cues of the track are kept in trackCues, so replaced hot cues are deleted only once and a later
+// mark of a hot cue slot already set by the file is skipped, also if the track appears several times.
+// It returns the number of added cues and the number of replaced hot cues.
+func importXMLMarks(cs *Changeset, trackID string, track XMLTrack, trackCues map[string]*xmlTrackCues) (int, int, error) {
+	state, ok := trackCues[trackID]
+	if !ok {
+		existing, err := cs.DB().GetTrackHotCues(trackID)
+		if err != nil {
+			return 0, 0, fmt.Errorf("%s: %w", locales.Translate("common.err.xmlread"), err)
+		}
+		state = &xmlTrackCues{cues: existing, taken: make(map[int]bool)}
+		trackCues[trackID] = state
+	}
+
+	contentUUID, err := cs.IDs().ContentUUID(trackID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	label := track.Name
+	added, replaced := 0, 0
+	for _, mark := range track.Marks {
+		kind, ok := cueKind(int(mark.Num))
+		if !ok || (kind != 0 && state.taken[kind]) {
+			continue
+		}
+		inMsec, err := xmlMilliseconds(mark.Start)
+		if err != nil {
+			continue
+		}
+		outMsec := int64(-1)
+		if mark.Type == xmlMarkLoop && mark.End != "" {
+			if value, err := xmlMilliseconds(mark.End); err == nil {
+				outMsec = value
+			}
+		}
+		if kind != 0 {
+			state.taken[kind] = true
+		}
+
+		// Skip cues already present, a different hot cue in the same slot is replaced
+		duplicate := false
+		replace := make(map[string]bool)
+		for _, cue := range state.cues {
+			if FormatChangeValue(cue["Kind"]) != strconv.Itoa(kind) {
+				continue
+			}
+			sameStart := math.Abs(float64(cueInt(cue["InMsec"])-inMsec)) <= 1
+			sameEnd := math.Abs(float64(cueInt(cue["OutMsec"])-outMsec)) <= 1 || (cueInt(cue["OutMsec"]) <= 0 && outMsec <= 0)
+			if sameStart && sameEnd && FormatChangeValue(cue["Comment"]) == mark.Name {
+				duplicate = true
+				break
+			}
+			if kind != 0 {
+				replace[FormatChangeValue(cue["ID"])] = true
+			}
+		}
+		if duplicate {
+			continue
+		}
+		if len(replace) > 0 {
+			kept := state.cues[:0]
+			for _, cue := range state.cues {
+				id := FormatChangeValue(cue["ID"])
+				if !replace[id] {
+					kept = append(kept, cue)
+					continue
+				}
+				cs.Delete(SQLTableDJMDCue, id, label)
+			}
+			state.cues = kept
+			replaced++
+		}
+
+		outFrame := int64(0)
+		if outMsec > 0 {
+			outFrame = outMsec * cueFramesPerSecond / 1000
+		}
+		activeLoop := int64(0)
+		if outMsec > 0 {
+			activeLoop = 1
+		}
+		cueID, err := cs.InsertNew(SQLTableDJMDCue, label,
+			FieldChange{Column: "ContentID", New: trackID},
+			FieldChange{Column: "ContentUUID", New: contentUUID},
+			FieldChange{Column: "InMsec", New: inMsec},
+			FieldChange{Column: "InFrame", New: inMsec * cueFramesPerSecond / 1000},
+			FieldChange{Column: "InMpegFrame", New: int64(0)},
+			FieldChange{Column: "InMpegAbs", New: int64(0)},
+			FieldChange{Column: "OutMsec", New: outMsec},
+			FieldChange{Column: "OutFrame", New: outFrame},
+			FieldChange{Column: "OutMpegFrame", New: int64(0)},
+			FieldChange{Column: "OutMpegAbs", New: int64(0)},
+			FieldChange{Column: "Kind", New: int64(kind)},
+			FieldChange{Column: "Color", New: int64(-1)},
+			FieldChange{Column: "ColorTableIndex", New: int64(0)},
+			FieldChange{Column: "ActiveLoop", New: activeLoop},
+			FieldChange{Column: "Comment", New: mark.Name},
+			FieldChange{Column: "BeatLoopSize", New: int64(0)},
+			FieldChange{Column: "CueMicrosec", New: int64(0)},
+		)
+		if err != nil {
+			return added, replaced, err
+		}
+		state.cues = append(state.cues, map[string]interface{}{
+			"ID": cueID, "Kind": int64(kind), "InMsec": inMsec, "OutMsec": outMsec, "Comment": mark.Name,
+		})
+		added++
+	}
+
+	return added, replaced, nil
+}
+
+// hotCueNum converts djmdCue.Kind to the POSITION_MARK Num attribute, -1 for memory cues.
+func hotCueNum(kind int) int {
+	for num, hotCueKind := range hotCueKinds {
+		if hotCueKind == kind {
+			return num
+		}
+	}
+	return -1
+}
+
+// cueKind converts the POSITION_MARK Num attribute to djmdCue.Kind.
+func cueKind(num int) (int, bool) {
+	if num < 0 {
+		return 0, true
+	}
+	if num < len(hotCueKinds) {
+		return hotCueKinds[num], true
+	}
+	return 0, false
+}
+
+// cueInt returns a numeric cue column value, -1 if the value is NULL or not a number.
+func cueInt(value interface{}) int64 {
+	number, err := strconv.ParseInt(FormatChangeValue(value), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// xmlSeconds formats milliseconds as seconds with three decimals.
+func xmlSeconds(msec int64) string {
+	return strconv.FormatFloat(float64(msec)/1000, 'f', 3, 64)
+}
+
+// xmlMilliseconds parses seconds as used in POSITION_MARK into milliseconds.
+func xmlMilliseconds(seconds string) (int64, error) {
+	value, err := strconv.ParseFloat(strings.TrimSpace(seconds), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(math.Round(value * 1000)), nil
+}
+
+// xmlFileKind returns the Kind attribute of a track for djmdContent.FileType.
+func xmlFileKind(fileType int) string {
+	switch fileType {
+	case GetFileType(ExtensionMP3):
+		return "MP3 File"
+	case GetFileType(ExtensionM4A):
+		return "M4A File"
+	case GetFileType(ExtensionFLAC):
+		return "FLAC File"
+	case GetFileType(ExtensionWAV):
+		return "WAV File"
+	case GetFileType(ExtensionAIFF):
+		return "AIFF File"
+	default:
+		return ""
+	}
+}
+
+// xmlRating converts the star rating of djmdContent (0-5) to the XML scale (0-255).
+func xmlRating(stars int) int {
+	if stars <= 0 {
+		return 0
+	}
+	if stars >= 5 {
+		return 255
+	}
+	return stars * 51
+}
+
+// ratingFromXML converts the XML rating (0-255) to stars (0-5).
+func ratingFromXML(rating int) int {
+	return int(math.Round(float64(rating) / 51))
+}
+
+// xmlLocation converts a FolderPath of the database to the file URL used as track Location.
+func xmlLocation(folderPath string) string {
+	if folderPath == "" {
+		return ""
+	}
+	location := url.URL{Scheme: "file", Host: "localhost", Path: "/" + strings.TrimPrefix(ToDbPath(folderPath, false), "/")}
+	return location.String()
+}
+
+// pathFromXMLLocation converts a track Location back to a path in the FolderPath format.
+func pathFromXMLLocation(location string) (string, error) {
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	path := parsed.Path
+	// Windows paths are written as /C:/...
+	if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+	return path, nil
+}
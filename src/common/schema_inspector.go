@@ -0,0 +1,331 @@
+// common/schema_inspector.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the schema inspector that guards database writes against unknown Rekordbox schemas.
+
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// CueColumns lists the djmdCue columns read and written by the application, in table order.
+var CueColumns = []string{
+	"ID", "ContentID", "InMsec", "InFrame", "InMpegFrame", "InMpegAbs",
+	"OutMsec", "OutFrame", "OutMpegFrame", "OutMpegAbs",
+	"Kind", "Color", "ColorTableIndex", "ActiveLoop", "Comment",
+	"BeatLoopSize", "CueMicrosec", "InPointSeekInfo", "OutPointSeekInfo",
+	"ContentUUID", "UUID", "rb_data_status", "rb_local_data_status",
+	"rb_local_deleted", "rb_local_synced",
+}
+
+// SchemaProfile describes a database schema the application is known to work with.
+type SchemaProfile struct {
+	Name         string              // Human readable name of the profile
+	Versions     []string            // Accepted djmdProperty.DBVersion values, see matchSchemaProfile
+	Columns      map[string][]string // Table -> columns the application relies on
+	InsertTables []string            // Tables the application inserts rows into
+}
+
+// KnownSchemaProfiles lists all schema profiles the application can safely write to.
+var KnownSchemaProfiles = []SchemaProfile{
+	{
+		Name:     "rekordbox 6/7",
+		Versions: []string{"6.", "7."},
+		Columns: map[string][]string{
+			"agentRegistry": {"registry_id", "int_1"},
+			"djmdProperty":  {"DBVersion"},
+			SQLTableDJMDContent: {
+				"ID", "FolderPath", "FileNameL", "FileType", "StockDate", "DateCreated", "ReleaseDate",
+				"ColorID", "DJPlayCount", "AlbumID", "OrgArtistID", "Subtitle", "UUID", "ImagePath",
+				"FileSize", "Length", "BitRate", "SampleRate", "BitDepth", "Title", "ArtistID", "GenreID",
+				"ComposerID", "RemixerID", "KeyID", "LabelID", "BPM", "Rating", "ReleaseYear", "TrackNo", "DiscNo", "Commnt", "ISRC",
+				"rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			SQLTableDJMDCue: append(append([]string(nil), CueColumns...), "rb_local_usn", "created_at", "updated_at"),
+			SQLTableDJMDArtist: {
+				"ID", "Name", "UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			SQLTableDJMDAlbum: {
+				"ID", "Name", "AlbumArtistID", "UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			SQLTableDJMDGenre: {
+				"ID", "Name", "UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			"djmdKey": {"ID", "ScaleName"},
+			SQLTableDJMDLabel: {
+				"ID", "Name", "UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			SQLTableDJMDPlaylist: {
+				"ID", "Name", "ParentID", "Seq", "Attribute", "SmartList",
+				"UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+			SQLTableDJMDSongPlaylist: {
+				"ID", "PlaylistID", "ContentID", "TrackNo",
+				"UUID", "rb_data_status", "rb_local_data_status", "rb_local_deleted", "rb_local_synced",
+				"rb_local_usn", "created_at", "updated_at",
+			},
+		},
+		InsertTables: []string{
+			SQLTableDJMDCue, SQLTableDJMDArtist, SQLTableDJMDPlaylist, SQLTableDJMDSongPlaylist,
+			SQLTableDJMDContent, SQLTableDJMDAlbum, SQLTableDJMDGenre, SQLTableDJMDLabel,
+		},
+	},
+}
+
+// SchemaColumn describes a single column as reported by PRAGMA table_info.
+type SchemaColumn struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	HasDefault bool
+	PrimaryKey bool
+}
+
+// SchemaReport is the result of a schema inspection.
+type SchemaReport struct {
+	DBVersion  string                    // djmdProperty.DBVersion, empty if unknown
+	AppVersion string                    // Version recorded in agentRegistry, empty if not present
+	Profile    string                    // Name of the matching profile, empty if none matches
+	Tables     map[string][]SchemaColumn // Table -> columns present in the database
+	Problems   []string                  // Localized descriptions of incompatibilities
+}
+
+// Compatible reports whether the database matches a known profile without any problem.
+func (r *SchemaReport) Compatible() bool {
+	return r.Profile != "" && len(r.Problems) == 0
+}
+
+// HasColumn reports whether the inspected database contains the given column.
+//
+// Parameters:
+//   - table: The name of the table
+//   - column: The name of the column
+//
+// Returns:
+//   - true if the column exists in the table
+func (r *SchemaReport) HasColumn(table, column string) bool {
+	for _, col := range r.Tables[table] {
+		if strings.EqualFold(col.Name, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the incompatibility of the report as a localized error, or nil if the schema is compatible.
+func (r *SchemaReport) Err() error {
+	if r.Compatible() {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", locales.Translate("common.err.schemaincompatible"), strings.Join(r.Problems, " "))
+}
+
+// InspectSchema reads the database version and the structure of all tables used by the application
+// and compares them with the known schema profiles.
+//
+// Parameters:
+//   - dbMgr: Database manager used to read the schema
+//
+// Returns:
+//   - A SchemaReport describing the database and nil if the inspection succeeded
+//   - nil and an error if the schema cannot be read
+func InspectSchema(dbMgr *DBManager) (*SchemaReport, error) {
+	report := &SchemaReport{Tables: make(map[string][]SchemaColumn)}
+
+	// Collect all tables referenced by any profile
+	tableSet := make(map[string]bool)
+	for _, profile := range KnownSchemaProfiles {
+		for table := range profile.Columns {
+			tableSet[table] = true
+		}
+	}
+	tables := make([]string, 0, len(tableSet))
+	for table := range tableSet {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		columns, err := readTableInfo(dbMgr, table)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.schemainspect"), err)
+		}
+		if len(columns) > 0 {
+			report.Tables[table] = columns
+		}
+	}
+
+	if report.HasColumn("djmdProperty", "DBVersion") {
+		row := dbMgr.QueryRow("SELECT DBVersion FROM djmdProperty LIMIT 1")
+		if row == nil {
+			return nil, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), dbMgr.GetDatabasePath())
+		}
+		var version NullString
+		if err := row.Scan(&version); err == nil && version.Valid {
+			report.DBVersion = strings.TrimSpace(version.String)
+		}
+	}
+
+	if report.HasColumn("agentRegistry", "str_1") {
+		row := dbMgr.QueryRow("SELECT str_1 FROM agentRegistry WHERE registry_id LIKE '%version%' AND str_1 IS NOT NULL LIMIT 1")
+		if row != nil {
+			var version NullString
+			if err := row.Scan(&version); err == nil && version.Valid {
+				report.AppVersion = strings.TrimSpace(version.String)
+			}
+		}
+	}
+
+	profile := matchSchemaProfile(report.DBVersion)
+	if profile == nil {
+		report.Problems = append(report.Problems, fmt.Sprintf(locales.Translate("common.schema.unknownversion"), report.DBVersion))
+		return report, nil
+	}
+	report.Profile = profile.Name
+
+	profileTables := make([]string, 0, len(profile.Columns))
+	for table := range profile.Columns {
+		profileTables = append(profileTables, table)
+	}
+	sort.Strings(profileTables)
+
+	for _, table := range profileTables {
+		if _, ok := report.Tables[table]; !ok {
+			report.Problems = append(report.Problems, fmt.Sprintf(locales.Translate("common.schema.missingtable"), table))
+			continue
+		}
+		for _, column := range profile.Columns[table] {
+			if !report.HasColumn(table, column) {
+				report.Problems = append(report.Problems, fmt.Sprintf(locales.Translate("common.schema.missingcolumn"), table, column))
+			}
+		}
+	}
+
+	// New mandatory columns without a default would make our inserts fail or write incomplete rows
+	for _, table := range profile.InsertTables {
+		known := make(map[string]bool)
+		for _, column := range profile.Columns[table] {
+			known[strings.ToLower(column)] = true
+		}
+		for _, col := range report.Tables[table] {
+			if col.NotNull && !col.HasDefault && !col.PrimaryKey && !known[strings.ToLower(col.Name)] {
+				report.Problems = append(report.Problems, fmt.Sprintf(locales.Translate("common.schema.newrequired"), table, col.Name))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// CheckWriteCompatibility inspects the connected database and refuses writes to unknown schemas.
+// It is called before every write session, so a database replaced or upgraded in the meantime is never written blindly.
+//
+// Returns:
+//   - The schema report and nil if the database matches a known profile
+//   - The schema report (if available) and a localized error describing why writing is refused
+func (m *DBManager) CheckWriteCompatibility() (*SchemaReport, error) {
+	report, err := InspectSchema(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := report.Err(); err != nil {
+		m.logger.Error("Database schema is not compatible (DBVersion %q): %s", report.DBVersion, strings.Join(report.Problems, " "))
+		return report, err
+	}
+
+	m.logger.Info("Database schema compatible with profile %s (DBVersion %s)", report.Profile, report.DBVersion)
+	return report, nil
+}
+
+// matchSchemaProfile returns the first known profile accepting the given database version.
+// A profile version ending with a dot accepts the major version itself and all its releases
+// ("6." accepts "6" and "6.7.1" but not "60"), any other profile version must match exactly.
+func matchSchemaProfile(version string) *SchemaProfile {
+	if version == "" {
+		return nil
+	}
+	for i := range KnownSchemaProfiles {
+		for _, accepted := range KnownSchemaProfiles[i].Versions {
+			major, isMajor := strings.CutSuffix(accepted, ".")
+			if version == accepted || (isMajor && (version == major || strings.HasPrefix(version, accepted))) {
+				return &KnownSchemaProfiles[i]
+			}
+		}
+	}
+	return nil
+}
+
+// readTableInfo returns the columns of a table using PRAGMA table_info.
+// A missing table yields an empty slice and no error.
+func readTableInfo(dbMgr *DBManager, table string) ([]SchemaColumn, error) {
+	rows, err := dbMgr.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []SchemaColumn
+	for rows.Next() {
+		var (
+			cid          int
+			name         string
+			colType      NullString
+			notNull      int
+			defaultValue interface{}
+			primaryKey   int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &primaryKey); err != nil {
+			return nil, err
+		}
+		columns = append(columns, SchemaColumn{
+			Name:       name,
+			Type:       colType.String,
+			NotNull:    notNull != 0,
+			HasDefault: defaultValue != nil,
+			PrimaryKey: primaryKey != 0,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+// checkChangesetColumns verifies that every table and column written by the changes exists in the database.
+func checkChangesetColumns(report *SchemaReport, changes []RowChange) error {
+	var problems []string
+	seen := make(map[string]bool)
+	for _, change := range changes {
+		columns := []string{change.KeyColumn}
+		for _, field := range change.Fields {
+			columns = append(columns, field.Column)
+		}
+		for _, column := range columns {
+			key := change.Table + "." + column
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if !report.HasColumn(change.Table, column) {
+				problems = append(problems, fmt.Sprintf(locales.Translate("common.schema.missingcolumn"), change.Table, column))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %s", locales.Translate("common.err.schemaincompatible"), strings.Join(problems, " "))
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+// common/smartlist.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the parser and evaluator of smart playlist conditions stored in djmdPlaylist.SmartList.
+
+package common
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// SmartListOperator is the comparison used by a smart playlist condition.
+type SmartListOperator int
+
+const (
+	SmartListEqual       SmartListOperator = 1  // Value equals ValueLeft
+	SmartListNotEqual    SmartListOperator = 2  // Value differs from ValueLeft
+	SmartListGreater     SmartListOperator = 3  // Value is greater (later) than ValueLeft
+	SmartListLess        SmartListOperator = 4  // Value is less (earlier) than ValueLeft
+	SmartListInRange     SmartListOperator = 5  // Value lies between ValueLeft and ValueRight
+	SmartListInLast      SmartListOperator = 6  // Date lies within the last ValueLeft units
+	SmartListNotInLast   SmartListOperator = 7  // Date lies before the last ValueLeft units
+	SmartListContains    SmartListOperator = 8  // Text contains ValueLeft
+	SmartListNotContains SmartListOperator = 9  // Text does not contain ValueLeft
+	SmartListStartsWith  SmartListOperator = 10 // Text starts with ValueLeft
+	SmartListEndsWith    SmartListOperator = 11 // Text ends with ValueLeft
+)
+
+const (
+	// smartListAll combines conditions with AND
+	smartListAll = 1
+	// smartListAny combines conditions with OR
+	smartListAny = 2
+)
+
+// smartListValueKind selects how the values of a property are compared.
+type smartListValueKind int
+
+const (
+	smartListText smartListValueKind = iota
+	smartListNumber
+	smartListDate
+	smartListMyTag
+)
+
+// smartListProperty describes how a condition property maps to djmdContent.
+type smartListProperty struct {
+	expr  string             // SQL expression over djmdContent aliased as c
+	kind  smartListValueKind // How the values are compared
+	scale float64            // Factor applied to numeric condition values, 0 means 1
+}
+
+// smartListProperties maps the PropertyName of a condition to its SQL expression.
+var smartListProperties = map[string]smartListProperty{
+	"artist":         {expr: "(SELECT Name FROM djmdArtist WHERE ID = c.ArtistID)", kind: smartListText},
+	"album":          {expr: "(SELECT Name FROM djmdAlbum WHERE ID = c.AlbumID)", kind: smartListText},
+	"albumArtist":    {expr: "(SELECT a.Name FROM djmdAlbum al JOIN djmdArtist a ON a.ID = al.AlbumArtistID WHERE al.ID = c.AlbumID)", kind: smartListText},
+	"originalArtist": {expr: "(SELECT Name FROM djmdArtist WHERE ID = c.OrgArtistID)", kind: smartListText},
+	"remixedBy":      {expr: "(SELECT Name FROM djmdArtist WHERE ID = c.RemixerID)", kind: smartListText},
+	"composer":       {expr: "(SELECT Name FROM djmdArtist WHERE ID = c.ComposerID)", kind: smartListText},
+	"genre":          {expr: "(SELECT Name FROM djmdGenre WHERE ID = c.GenreID)", kind: smartListText},
+	"label":          {expr: "(SELECT Name FROM djmdLabel WHERE ID = c.LabelID)", kind: smartListText},
+	"key":            {expr: "(SELECT ScaleName FROM djmdKey WHERE ID = c.KeyID)", kind: smartListText},
+	"name":           {expr: "c.Title", kind: smartListText},
+	"mixName":        {expr: "c.Subtitle", kind: smartListText},
+	"comments":       {expr: "c.Commnt", kind: smartListText},
+	"fileName":       {expr: "c.FileNameL", kind: smartListText},
+	"bpm":            {expr: "c.BPM", kind: smartListNumber, scale: 100},
+	"rating":         {expr: "c.Rating", kind: smartListNumber},
+	"counter":        {expr: "c.DJPlayCount", kind: smartListNumber},
+	"duration":       {expr: "c.Length", kind: smartListNumber},
+	"year":           {expr: "c.ReleaseYear", kind: smartListNumber},
+	"dateReleased":   {expr: "c.ReleaseDate", kind: smartListDate},
+	"stockDate":      {expr: "c.StockDate", kind: smartListDate},
+	"dateCreated":    {expr: "c.DateCreated", kind: smartListDate},
+	"myTag":          {expr: "c.ID", kind: smartListMyTag},
+}
+
+// SmartListCondition is a single condition of a smart playlist.
+type SmartListCondition struct {
+	Property   string            `xml:"PropertyName,attr"`
+	Operator   SmartListOperator `xml:"Operator,attr"`
+	ValueUnit  string            `xml:"ValueUnit,attr"`
+	ValueLeft  string            `xml:"ValueLeft,attr"`
+	ValueRight string            `xml:"ValueRight,attr"`
+}
+
+// SmartList is the parsed condition tree of a smart playlist.
+type SmartList struct {
+	XMLName         xml.Name             `xml:"NODE"`
+	ID              string               `xml:"Id,attr"`
+	LogicalOperator int                  `xml:"LogicalOperator,attr"`
+	AutomaticUpdate int                  `xml:"AutomaticUpdate,attr"`
+	Conditions      []SmartListCondition `xml:"CONDITION"`
+	Nodes           []SmartList          `xml:"NODE"`
+}
+
+// ParseSmartList parses the condition XML stored in djmdPlaylist.SmartList.
+//
+// Parameters:
+//   - data: The content of the SmartList column
+//
+// Returns:
+//   - The parsed smart list and nil if successful
+//   - nil and an error if the XML cannot be parsed
+func ParseSmartList(data string) (*SmartList, error) {
+	var list SmartList
+	if err := xml.Unmarshal([]byte(strings.TrimSpace(data)), &list); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.smartlistparse"), err)
+	}
+	return &list, nil
+}
+
+// Where translates the smart list into an SQL condition over djmdContent aliased as c.
+// A smart list without conditions matches no tracks.
+//
+// Returns:
+//   - The SQL condition and its arguments
+//   - An error if the smart list contains a property or operator that cannot be evaluated
+func (s *SmartList) Where() (string, []interface{}, error) {
+	var parts []string
+	var args []interface{}
+
+	for _, condition := range s.Conditions {
+		part, partArgs, err := condition.where()
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, part)
+		args = append(args, partArgs...)
+	}
+	for i := range s.Nodes {
+		part, partArgs, err := s.Nodes[i].Where()
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, part)
+		args = append(args, partArgs...)
+	}
+
+	if len(parts) == 0 {
+		return "0", nil, nil
+	}
+
+	joiner := " AND "
+	if s.LogicalOperator == smartListAny {
+		joiner = " OR "
+	}
+	return "(" + strings.Join(parts, joiner) + ")", args, nil
+}
+
+// where translates a single condition into an SQL condition.
+func (sc SmartListCondition) where() (string, []interface{}, error) {
+	property, ok := smartListProperties[sc.Property]
+	if !ok {
+		return "", nil, sc.unsupported()
+	}
+
+	switch property.kind {
+	case smartListText:
+		return sc.textWhere(property.expr)
+	case smartListNumber:
+		return sc.numberWhere(property)
+	case smartListDate:
+		return sc.dateWhere(property.expr)
+	case smartListMyTag:
+		exists := "EXISTS (SELECT 1 FROM djmdSongMyTag t WHERE t.ContentID = c.ID AND t.MyTagID = ?)"
+		switch sc.Operator {
+		case SmartListEqual:
+			return exists, []interface{}{sc.ValueLeft}, nil
+		case SmartListNotEqual:
+			return "NOT " + exists, []interface{}{sc.ValueLeft}, nil
+		}
+	}
+	return "", nil, sc.unsupported()
+}
+
+// textWhere translates a condition on a text property; comparisons ignore case like rekordbox does.
+func (sc SmartListCondition) textWhere(expr string) (string, []interface{}, error) {
+	value := sc.ValueLeft
+	like := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+	switch sc.Operator {
+	case SmartListEqual:
+		return expr + " = ? COLLATE NOCASE", []interface{}{value}, nil
+	case SmartListNotEqual:
+		return "COALESCE(" + expr + ", '') <> ? COLLATE NOCASE", []interface{}{value}, nil
+	case SmartListContains:
+		return expr + ` LIKE ? ESCAPE '\'`, []interface{}{"%" + like + "%"}, nil
+	case SmartListNotContains:
+		return "COALESCE(" + expr + `, '') NOT LIKE ? ESCAPE '\'`, []interface{}{"%" + like + "%"}, nil
+	case SmartListStartsWith:
+		return expr + ` LIKE ? ESCAPE '\'`, []interface{}{like + "%"}, nil
+	case SmartListEndsWith:
+		return expr + ` LIKE ? ESCAPE '\'`, []interface{}{"%" + like}, nil
+	}
+	return "", nil, sc.unsupported()
+}
+
+// numberWhere translates a condition on a numeric property.
+func (sc SmartListCondition) numberWhere(property smartListProperty) (string, []interface{}, error) {
+	left, err := sc.number(sc.ValueLeft, property.scale)
+	if err != nil {
+		return "", nil, err
+	}
+	expr := property.expr
+	switch sc.Operator {
+	case SmartListEqual:
+		return expr + " = ?", []interface{}{left}, nil
+	case SmartListNotEqual:
+		return "COALESCE(" + expr + ", 0) <> ?", []interface{}{left}, nil
+	case SmartListGreater:
+		return expr + " > ?", []interface{}{left}, nil
+	case SmartListLess:
+		return expr + " < ?", []interface{}{left}, nil
+	case SmartListInRange:
+		right, err := sc.number(sc.ValueRight, property.scale)
+		if err != nil {
+			return "", nil, err
+		}
+		return expr + " BETWEEN ? AND ?", []interface{}{left, right}, nil
+	}
+	return "", nil, sc.unsupported()
+}
+
+// dateWhere translates a condition on a date property stored as text (YYYY-MM-DD).
+func (sc SmartListCondition) dateWhere(expr string) (string, []interface{}, error) {
+	value := "date(" + expr + ")"
+	switch sc.Operator {
+	case SmartListEqual:
+		return value + " = date(?)", []interface{}{sc.ValueLeft}, nil
+	case SmartListNotEqual:
+		return "COALESCE(" + value + ", '') <> date(?)", []interface{}{sc.ValueLeft}, nil
+	case SmartListGreater:
+		return value + " > date(?)", []interface{}{sc.ValueLeft}, nil
+	case SmartListLess:
+		return value + " < date(?)", []interface{}{sc.ValueLeft}, nil
+	case SmartListInRange:
+		return value + " BETWEEN date(?) AND date(?)", []interface{}{sc.ValueLeft, sc.ValueRight}, nil
+	case SmartListInLast, SmartListNotInLast:
+		modifier, err := sc.dateModifier()
+		if err != nil {
+			return "", nil, err
+		}
+		if sc.Operator == SmartListInLast {
+			return value + " >= date('now', ?)", []interface{}{modifier}, nil
+		}
+		return value + " < date('now', ?)", []interface{}{modifier}, nil
+	}
+	return "", nil, sc.unsupported()
+}
+
+// dateModifier converts ValueLeft and ValueUnit of an "in the last" condition into an SQLite date modifier.
+func (sc SmartListCondition) dateModifier() (string, error) {
+	count, err := strconv.Atoi(strings.TrimSpace(sc.ValueLeft))
+	if err != nil {
+		return "", sc.unsupported()
+	}
+	switch strings.ToLower(sc.ValueUnit) {
+	case "", "day", "days":
+		return fmt.Sprintf("-%d days", count), nil
+	case "week", "weeks":
+		return fmt.Sprintf("-%d days", count*7), nil
+	case "month", "months":
+		return fmt.Sprintf("-%d months", count), nil
+	case "year", "years":
+		return fmt.Sprintf("-%d years", count), nil
+	}
+	return "", sc.unsupported()
+}
+
+// number parses a numeric condition value and applies the storage scale of the property.
+func (sc SmartListCondition) number(value string, scale float64) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, sc.unsupported()
+	}
+	if scale != 0 {
+		number *= scale
+	}
+	return number, nil
+}
+
+// unsupported returns the error reported for a condition that cannot be evaluated.
+func (sc SmartListCondition) unsupported() error {
+	return fmt.Errorf(locales.Translate("common.err.smartlistcondition"), fmt.Sprintf("%s %d %q", sc.Property, sc.Operator, sc.ValueLeft))
+}
+
+// smartListWhere reads the conditions of a smart playlist and translates them into an SQL condition.
+func (m *DBManager) smartListWhere(playlistID string) (string, []interface{}, error) {
+	row := m.QueryRow("SELECT SmartList FROM djmdPlaylist WHERE ID = ?", playlistID)
+	if row == nil {
+		return "", nil, fmt.Errorf(locales.Translate("common.err.dbnotconnected"), m.GetDatabasePath())
+	}
+	var data NullString
+	if err := row.Scan(&data); err != nil {
+		return "", nil, fmt.Errorf("%s: %w", locales.Translate("common.err.smartlistparse"), err)
+	}
+	return smartListCondition(data.String)
+}
+
+// smartListCondition translates the stored conditions of a smart playlist into an SQL condition on djmdContent c.
+// Tracks deleted from the collection (rb_local_deleted) never match, like in rekordbox.
+func smartListCondition(data string) (string, []interface{}, error) {
+	list, err := ParseSmartList(data)
+	if err != nil {
+		return "", nil, err
+	}
+	where, args, err := list.Where()
+	if err != nil {
+		return "", nil, err
+	}
+	return "COALESCE(c.rb_local_deleted, 0) = 0 AND (" + where + ")", args, nil
+}
+
+// smartPlaylistTrackCounts returns the number of tracks matching the conditions of smart playlists.
+// All playlists are counted by a single query over djmdContent; a playlist whose conditions cannot be
+// evaluated is logged and counts 0 tracks.
+//
+// Parameters:
+//   - nodes: The smart playlists to count
+//   - lists: The stored conditions (djmdPlaylist.SmartList) by playlist ID
+//
+// Returns:
+//   - The number of matching tracks by playlist ID
+func (m *DBManager) smartPlaylistTrackCounts(nodes []*PlaylistNode, lists map[string]string) map[string]int {
+	counts := make(map[string]int)
+	var ids []string
+	var columns []string
+	var args []interface{}
+	for _, node := range nodes {
+		where, whereArgs, err := smartListCondition(lists[node.ID])
+		if err != nil {
+			m.logger.Warning("Smart playlist %s cannot be evaluated: %v", node.Name, err)
+			continue
+		}
+		ids = append(ids, node.ID)
+		columns = append(columns, "COALESCE(SUM(CASE WHEN "+where+" THEN 1 ELSE 0 END), 0)")
+		args = append(args, whereArgs...)
+	}
+	if len(ids) == 0 {
+		return counts
+	}
+
+	row := m.QueryRow("SELECT "+strings.Join(columns, ", ")+" FROM djmdContent c", args...)
+	if row == nil {
+		m.logger.Warning("Smart playlists cannot be counted: %s", fmt.Sprintf(locales.Translate("common.err.dbnotconnected"), m.GetDatabasePath()))
+		return counts
+	}
+	values := make([]int, len(ids))
+	dest := make([]interface{}, len(ids))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := row.Scan(dest...); err != nil {
+		m.logger.Warning("Smart playlists cannot be counted: %v", err)
+		return counts
+	}
+	for i, id := range ids {
+		counts[id] = values[i]
+	}
+	return counts
+}
@@ -0,0 +1,266 @@
+// common/tag_export.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the export of metadata curated in rekordbox from the database into file tags.
+
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"MetaRekordFixer/locales"
+)
+
+// TagExportMyTags is the export field of My Tags. My Tags have no own tag; like rekordbox does,
+// they are added to the comment as "/* Tag / Tag */".
+const TagExportMyTags = "mytags"
+
+// TagExportFields lists the fields which can be exported from the database into file tags, in display order.
+// Except for TagExportMyTags, they are internal field names of metadata_map.csv.
+var TagExportFields = []string{
+	"title", "artist", "album", "albumartist", "genre", "comment", "rating", "color", TagExportMyTags,
+	"label", "composer", "remixer", "origartist", "isrc", "bpm", "initialkey", "tracknumber", "discnumber",
+	"releasedate", "subtitle",
+}
+
+// DefaultTagExportFields lists the fields exported unless the user selects others.
+var DefaultTagExportFields = []string{"title", "genre", "comment", "rating", "color", TagExportMyTags}
+
+// myTagCommentPattern matches a My Tag block added to a comment by rekordbox or by the export.
+var myTagCommentPattern = regexp.MustCompile(`\s*/\*.*?\*/`)
+
+// TagChange describes the change of one tag value of a file.
+type TagChange struct {
+	Field string // Internal field name
+	Old   string // Current tag value
+	New   string // Value from the database
+}
+
+// TagExportItem holds the tag values to write into one file.
+type TagExportItem struct {
+	File    string            // Path to the audio file
+	Values  map[string]string // Values to write keyed by internal field name
+	Changes []TagChange       // Changes shown in the preview
+}
+
+// TagExportSummary contains the results of preparing a tag export.
+type TagExportSummary struct {
+	Tracks      int             // Number of tracks in the source
+	Unchanged   int             // Number of files whose tags already match the database
+	Unsupported int             // Number of tracks in formats without tag writing
+	Missing     []string        // Files of tracks which do not exist
+	Failed      []string        // Files whose tags cannot be read
+	Items       []TagExportItem // Files with changed tags
+}
+
+// PrepareTagExport compares the database values of tracks with the tags of their files.
+// Nothing is written; the returned items are shown for review and written with ApplyTagExport.
+// Empty database values are skipped, so tags are never cleared.
+//
+// Parameters:
+//   - dbMgr: The database manager
+//   - tracks: The tracks to export
+//   - fields: The fields to export, see TagExportFields
+//   - mapping: The field mapping loaded from metadata_map.csv
+//   - isCancelled: Optional function reporting whether the user stopped the run
+//
+// Returns:
+//   - The summary with the files to write
+//   - ErrCancelled if the run was cancelled, or an error if the database cannot be read
+func PrepareTagExport(dbMgr *DBManager, tracks []TrackItem, fields []string, mapping *MetadataMap, isCancelled func() bool) (TagExportSummary, error) {
+	summary := TagExportSummary{Tracks: len(tracks)}
+
+	// My Tags are written as part of the comment
+	tagFields := slices.DeleteFunc(slices.Clone(fields), func(field string) bool { return field == TagExportMyTags })
+	myTags := slices.Contains(fields, TagExportMyTags)
+	if myTags && !slices.Contains(tagFields, "comment") {
+		tagFields = append(tagFields, "comment")
+	}
+
+	for _, track := range tracks {
+		if isCancelled != nil && isCancelled() {
+			return summary, ErrCancelled
+		}
+
+		filePath := filepath.FromSlash(track.FolderPath)
+		if !slices.Contains(TagWriteExtensions, strings.ToLower(filepath.Ext(filePath))) {
+			summary.Unsupported++
+			continue
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			summary.Missing = append(summary.Missing, filePath)
+			continue
+		}
+
+		values, err := trackTagValues(dbMgr, track.ID, myTags)
+		if err != nil {
+			return summary, err
+		}
+		current, err := ReadFileTags(filePath, mapping, tagFields)
+		if err != nil {
+			dbMgr.logger.Warning("%s %v", fmt.Sprintf(locales.Translate("common.log.file"), filepath.Base(filePath)), err)
+			summary.Failed = append(summary.Failed, filePath)
+			continue
+		}
+
+		item := TagExportItem{File: filePath, Values: make(map[string]string)}
+		for _, field := range tagFields {
+			value := values[field]
+			if value == "" || mapping.TagKey(filepath.Ext(filePath), field) == "" || value == current[field] {
+				continue
+			}
+			item.Values[field] = value
+			item.Changes = append(item.Changes, TagChange{Field: field, Old: current[field], New: value})
+		}
+		if len(item.Changes) == 0 {
+			summary.Unchanged++
+			continue
+		}
+		summary.Items = append(summary.Items, item)
+	}
+
+	return summary, nil
+}
+
+// ApplyTagExport writes prepared tag values into the files.
+// A file which cannot be written does not stop the export; its error is returned with the others.
+//
+// Parameters:
+//   - items: The files and values prepared by PrepareTagExport
+//   - mapping: The field mapping loaded from metadata_map.csv
+//   - isCancelled: Optional function reporting whether the user stopped the run
+//   - onProgress: Optional callback receiving the number of processed and all files
+//
+// Returns:
+//   - The number of written files
+//   - The errors of files which could not be written
+//   - ErrCancelled if the run was cancelled; files written before remain written
+func ApplyTagExport(items []TagExportItem, mapping *MetadataMap, isCancelled func() bool, onProgress func(done, total int)) (int, []error, error) {
+	written := 0
+	var failed []error
+	for i, item := range items {
+		if isCancelled != nil && isCancelled() {
+			return written, failed, ErrCancelled
+		}
+		if err := WriteFileTags(item.File, mapping, item.Values); err != nil {
+			failed = append(failed, err)
+		} else {
+			written++
+		}
+		if onProgress != nil {
+			onProgress(i+1, len(items))
+		}
+	}
+	return written, failed, nil
+}
+
+// trackTagValues reads the exportable values of a track keyed by internal field name.
+// If myTags is true, the My Tags of the track are added to its comment.
+func trackTagValues(dbMgr *DBManager, trackID string, myTags bool) (map[string]string, error) {
+	var title, artist, album, albumArtist, genre, comment, color, label, composer, remixer, origArtist string
+	var isrc, key, releaseDate, subtitle string
+	var rating, bpm, trackNo, discNo int64
+	err := dbMgr.QueryRow(`
+		SELECT COALESCE(c.Title, ''), COALESCE(a.Name, ''), COALESCE(al.Name, ''), COALESCE(aa.Name, ''),
+			COALESCE(g.Name, ''), COALESCE(c.Commnt, ''), COALESCE(col.Commnt, ''), COALESCE(l.Name, ''),
+			COALESCE(cp.Name, ''), COALESCE(r.Name, ''), COALESCE(oa.Name, ''), COALESCE(c.ISRC, ''),
+			COALESCE(k.ScaleName, ''), COALESCE(c.ReleaseDate, ''), COALESCE(c.Subtitle, ''),
+			COALESCE(c.Rating, 0), COALESCE(c.BPM, 0), COALESCE(c.TrackNo, 0), COALESCE(c.DiscNo, 0)
+		FROM djmdContent c
+		LEFT JOIN djmdArtist a ON a.ID = c.ArtistID
+		LEFT JOIN djmdAlbum al ON al.ID = c.AlbumID
+		LEFT JOIN djmdArtist aa ON aa.ID = al.AlbumArtistID
+		LEFT JOIN djmdGenre g ON g.ID = c.GenreID
+		LEFT JOIN djmdColor col ON col.ID = c.ColorID
+		LEFT JOIN djmdLabel l ON l.ID = c.LabelID
+		LEFT JOIN djmdArtist cp ON cp.ID = c.ComposerID
+		LEFT JOIN djmdArtist r ON r.ID = c.RemixerID
+		LEFT JOIN djmdArtist oa ON oa.ID = c.OrgArtistID
+		LEFT JOIN djmdKey k ON k.ID = c.KeyID
+		WHERE c.ID = ?`, trackID).Scan(&title, &artist, &album, &albumArtist,
+		&genre, &comment, &color, &label,
+		&composer, &remixer, &origArtist, &isrc,
+		&key, &releaseDate, &subtitle,
+		&rating, &bpm, &trackNo, &discNo)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagexportread"), err)
+	}
+
+	values := map[string]string{
+		"title": title, "artist": artist, "album": album, "albumartist": albumArtist,
+		"genre": genre, "comment": comment, "color": color, "label": label,
+		"composer": composer, "remixer": remixer, "origartist": origArtist, "isrc": isrc,
+		"initialkey": key, "releasedate": releaseDate, "subtitle": subtitle,
+	}
+	if rating > 0 {
+		values["rating"] = strconv.FormatInt(rating, 10)
+	}
+	if bpm > 0 {
+		// BPM is stored in hundredths
+		values["bpm"] = strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", float64(bpm)/100), "0"), ".")
+	}
+	if trackNo > 0 {
+		values["tracknumber"] = strconv.FormatInt(trackNo, 10)
+	}
+	if discNo > 0 {
+		values["discnumber"] = strconv.FormatInt(discNo, 10)
+	}
+
+	if myTags {
+		names, err := trackMyTags(dbMgr, trackID)
+		if err != nil {
+			return nil, err
+		}
+		values["comment"] = myTagComment(comment, names)
+	}
+	return values, nil
+}
+
+// trackMyTags returns the names of the My Tags assigned to a track.
+func trackMyTags(dbMgr *DBManager, trackID string) ([]string, error) {
+	rows, err := dbMgr.Query(`
+		SELECT t.Name
+		FROM djmdSongMyTag s
+		JOIN djmdMyTag t ON t.ID = s.MyTagID
+		WHERE s.ContentID = ?
+		ORDER BY t.Seq, t.Name`, trackID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagexportread"), err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name NullString
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagexportread"), err)
+		}
+		if name.Valid && name.String != "" {
+			names = append(names, name.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagexportread"), err)
+	}
+	return names, nil
+}
+
+// myTagComment replaces the My Tag block of a comment with the given My Tags.
+// Without My Tags, only the comment without the block is returned.
+func myTagComment(comment string, names []string) string {
+	comment = strings.TrimSpace(myTagCommentPattern.ReplaceAllString(comment, ""))
+	if len(names) == 0 {
+		return comment
+	}
+	block := "/* " + strings.Join(names, " / ") + " */"
+	if comment == "" {
+		return block
+	}
+	return comment + " " + block
+}
@@ -0,0 +1,190 @@
+// common/tag_mapping.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the user-defined mapping of tag keys to the metadata fields written to the database.
+
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Transforms applied to the tag values of a metadata field.
+const (
+	// TagTransformNone uses the tag values as read
+	TagTransformNone = "none"
+	// TagTransformTrim collapses runs of whitespace into single spaces
+	TagTransformTrim = "trim"
+	// TagTransformDate normalizes the values to YYYY-MM-DD, see NormalizeReleaseDate
+	TagTransformDate = "date"
+	// TagTransformSplit splits the values on ";", the first part is preferred
+	TagTransformSplit = "split"
+)
+
+// TagTransforms lists the available transforms.
+var TagTransforms = []string{TagTransformNone, TagTransformTrim, TagTransformDate, TagTransformSplit}
+
+// tagMappingHeader is the header row of a stored tag mapping.
+var tagMappingHeader = []string{"Field", "Keys", "Transform"}
+
+// TagMappingEntry declares the source tag keys of one metadata field.
+type TagMappingEntry struct {
+	Keys      []string // Tag keys in order of preference, empty uses the keys of TagFieldKeys
+	Transform string   // One of the TagTransform constants
+}
+
+// TagMapping maps metadata fields (TagField constants) to their source tag keys and transform.
+// Fields without an entry are read with the keys of TagFieldKeys and without transform.
+type TagMapping map[string]TagMappingEntry
+
+// ParseTagMapping parses a tag mapping stored in the configuration. The mapping is stored as CSV
+// with the columns Field, Keys and Transform, like metadata_map.csv; the keys of a field are separated by "|".
+// Rows of unknown fields are ignored, unknown transforms are read as TagTransformNone.
+//
+// Parameters:
+//   - value: The stored mapping, empty for the default mapping
+//
+// Returns:
+//   - The parsed mapping
+func ParseTagMapping(value string) TagMapping {
+	mapping := make(TagMapping)
+	reader := csv.NewReader(strings.NewReader(value))
+	reader.FieldsPerRecord = -1
+	for {
+		// A malformed row ends the mapping, the rows before it are kept
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		if len(record) < 2 || slices.Equal(record, tagMappingHeader) {
+			continue
+		}
+
+		field := strings.ToUpper(strings.TrimSpace(record[0]))
+		if _, ok := TagFieldKeys[field]; !ok {
+			continue
+		}
+		entry := TagMappingEntry{Keys: ParseTagKeys(record[1]), Transform: TagTransformNone}
+		if len(record) > 2 && slices.Contains(TagTransforms, strings.TrimSpace(record[2])) {
+			entry.Transform = strings.TrimSpace(record[2])
+		}
+		mapping[field] = entry
+	}
+	return mapping
+}
+
+// FormatTagMapping formats a tag mapping for storing in the configuration, see ParseTagMapping.
+// Entries without keys and transform equal the default and are not stored.
+//
+// Parameters:
+//   - mapping: The mapping to store
+//
+// Returns:
+//   - The mapping as CSV sorted by field, empty if all entries are default
+func FormatTagMapping(mapping TagMapping) string {
+	fields := make([]string, 0, len(mapping))
+	for field, entry := range mapping {
+		if len(entry.Keys) > 0 || (entry.Transform != "" && entry.Transform != TagTransformNone) {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	sort.Strings(fields)
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write(tagMappingHeader)
+	for _, field := range fields {
+		entry := mapping[field]
+		transform := entry.Transform
+		if transform == "" {
+			transform = TagTransformNone
+		}
+		writer.Write([]string{field, strings.Join(entry.Keys, "|"), transform})
+	}
+	writer.Flush()
+	return buffer.String()
+}
+
+// ParseTagKeys splits a list of tag keys separated by "|" or ",", as entered by the user.
+//
+// Parameters:
+//   - value: The list of keys
+//
+// Returns:
+//   - The non-empty keys in the given order
+func ParseTagKeys(value string) []string {
+	var keys []string
+	for _, key := range strings.FieldsFunc(value, func(r rune) bool { return r == '|' || r == ',' }) {
+		if key = strings.TrimSpace(key); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Transform returns the transform of a metadata field, TagTransformNone if the field has no entry.
+//
+// Parameters:
+//   - field: The metadata field
+//
+// Returns:
+//   - One of the TagTransform constants
+func (tm TagMapping) Transform(field string) string {
+	if entry, ok := tm[field]; ok && entry.Transform != "" {
+		return entry.Transform
+	}
+	return TagTransformNone
+}
+
+// containerKeys returns the keys under which a metadata field is looked up in a tag container.
+// Keys declared by the user apply to all containers; in ID3v2 tags, keys which are no frame ID
+// are looked up as user defined text frames (e.g. ORIGINALDATE as TXXX:ORIGINALDATE).
+// A user defined frame named like a frame ID has to be given with its prefix, e.g. TXXX:ISRC.
+func (tm TagMapping) containerKeys(field string, container TagContainer) []string {
+	entry, ok := tm[field]
+	if !ok || len(entry.Keys) == 0 {
+		return TagFieldKeys[field][container]
+	}
+	if container != TagContainerID3v2 {
+		return entry.Keys
+	}
+	keys := make([]string, 0, len(entry.Keys))
+	for _, key := range entry.Keys {
+		if !strings.HasPrefix(key, tagKeyTXXX) && !isID3FrameID(key) {
+			key = tagKeyTXXX + key
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// transformValues applies the trim and split transforms of a metadata field to its tag values.
+// The date transform is applied by ProcessFolderMetadata, which knows the fallback for incomplete dates.
+func (tm TagMapping) transformValues(field string, values []string) []string {
+	var result []string
+	add := func(value string) {
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	for _, value := range values {
+		switch tm.Transform(field) {
+		case TagTransformTrim:
+			add(strings.Join(strings.Fields(value), " "))
+		case TagTransformSplit:
+			for _, part := range strings.Split(value, ";") {
+				add(strings.TrimSpace(part))
+			}
+		default:
+			add(value)
+		}
+	}
+	return result
+}
@@ -0,0 +1,355 @@
+// common/tag_reader.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the per-format tag reader: it knows in which tag container each audio format
+// stores its metadata and under which keys a metadata field is found in each container.
+
+package common
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dhowden/tag"
+)
+
+// TagContainer identifies the way tags are stored in an audio file.
+type TagContainer string
+
+const (
+	// TagContainerVorbis holds Vorbis comments (FLAC, OGG)
+	TagContainerVorbis TagContainer = "VORBIS"
+
+	// TagContainerID3v2 holds ID3v2 frames (MP3, ID3 chunk of WAV and AIFF)
+	TagContainerID3v2 TagContainer = "ID3v2"
+
+	// TagContainerMP4 holds iTunes atoms (M4A)
+	TagContainerMP4 TagContainer = "MP4"
+
+	// TagContainerRIFF holds the RIFF INFO chunk of WAV files
+	TagContainerRIFF TagContainer = "RIFF"
+)
+
+// Audio formats accepted by ReadMetadataFromFile.
+const (
+	AudioFormatFLAC = "FLAC"
+	AudioFormatMP3  = "MP3"
+	AudioFormatM4A  = "M4A"
+	AudioFormatWAV  = "WAV"
+	AudioFormatAIFF = "AIFF"
+)
+
+// tagKeyTXXX prefixes keys of user defined ID3v2 text frames, e.g. "TXXX:RELEASEDATE".
+const tagKeyTXXX = "TXXX:"
+
+// Metadata fields read from the file tags and written to the database.
+const (
+	TagFieldAlbum       = "ALBUM"
+	TagFieldAlbumArtist = "ALBUMARTIST"
+	TagFieldOrigArtist  = "ORIGARTIST"
+	TagFieldReleaseDate = "RELEASEDATE"
+	TagFieldSubtitle    = "SUBTITLE"
+	TagFieldLabel       = "LABEL"
+	TagFieldComposer    = "COMPOSER"
+	TagFieldISRC        = "ISRC"
+	TagFieldRemixer     = "REMIXER"
+	TagFieldGenre       = "GENRE"
+	TagFieldTrackNumber = "TRACKNUMBER"
+	TagFieldDiscNumber  = "DISCNUMBER"
+
+	// TagFieldArtwork is the embedded picture, imported as artwork into the share folder
+	TagFieldArtwork = "ARTWORK"
+)
+
+// TagFieldKeys lists for each metadata field the keys under which the field is stored in each tag container,
+// in order of preference. rekordbox ignores several of these keys (e.g. TDRL and TDOR in MP3 files,
+// or everything but the INFO chunk in WAV files), which is why the fields have to be copied to the database.
+var TagFieldKeys = map[string]map[TagContainer][]string{
+	TagFieldAlbum: {
+		TagContainerVorbis: {"album"},
+		TagContainerID3v2:  {"TALB", "TAL"},
+		TagContainerMP4:    {"\xa9alb"},
+		TagContainerRIFF:   {"IPRD"},
+	},
+	TagFieldAlbumArtist: {
+		TagContainerVorbis: {"albumartist", "album artist", "album_artist"},
+		TagContainerID3v2:  {"TPE2", "TP2"},
+		TagContainerMP4:    {"aART"},
+	},
+	TagFieldOrigArtist: {
+		TagContainerVorbis: {"origartist", "originalartist"},
+		TagContainerID3v2:  {"TOPE", "TOA", tagKeyTXXX + "ORIGARTIST"},
+		TagContainerMP4:    {"ORIGARTIST", "ORIGINALARTIST"},
+	},
+	TagFieldReleaseDate: {
+		TagContainerVorbis: {"releasedate", "date", "originaldate", "year"},
+		TagContainerID3v2:  {"TDRL", "TDOR", "TORY", "TOR", tagKeyTXXX + "RELEASEDATE", "TDRC", "TYER", "TYE"},
+		TagContainerMP4:    {"RELEASEDATE", "\xa9day", "ORIGINALDATE"},
+		TagContainerRIFF:   {"ICRD"},
+	},
+	TagFieldSubtitle: {
+		TagContainerVorbis: {"subtitle"},
+		TagContainerID3v2:  {"TIT3", "TT3"},
+		TagContainerMP4:    {"SUBTITLE"},
+	},
+	TagFieldLabel: {
+		TagContainerVorbis: {"label", "organization", "publisher"},
+		TagContainerID3v2:  {"TPUB", "TPB", tagKeyTXXX + "LABEL"},
+		TagContainerMP4:    {"LABEL", "publisher"},
+	},
+	TagFieldComposer: {
+		TagContainerVorbis: {"composer"},
+		TagContainerID3v2:  {"TCOM", "TCM"},
+		TagContainerMP4:    {"\xa9wrt"},
+	},
+	TagFieldISRC: {
+		TagContainerVorbis: {"isrc"},
+		TagContainerID3v2:  {"TSRC", "TRC"},
+		TagContainerMP4:    {"ISRC"},
+	},
+	TagFieldRemixer: {
+		TagContainerVorbis: {"remixer", "mixartist"},
+		TagContainerID3v2:  {"TPE4", "TP4"},
+		TagContainerMP4:    {"REMIXER", "MIXARTIST"},
+	},
+	TagFieldGenre: {
+		TagContainerVorbis: {"genre"},
+		TagContainerID3v2:  {"TCON", "TCO"},
+		TagContainerMP4:    {"\xa9gen"},
+		TagContainerRIFF:   {"IGNR"},
+	},
+	TagFieldTrackNumber: {
+		TagContainerVorbis: {"tracknumber"},
+		TagContainerID3v2:  {"TRCK", "TRK"},
+		TagContainerMP4:    {"trkn"},
+		TagContainerRIFF:   {"ITRK", "IPRT"},
+	},
+	TagFieldDiscNumber: {
+		TagContainerVorbis: {"discnumber"},
+		TagContainerID3v2:  {"TPOS", "TPA"},
+		TagContainerMP4:    {"disk"},
+	},
+}
+
+// AudioFormatFromPath returns the audio format of a file based on its extension.
+//
+// Parameters:
+//   - filePath: The path to the audio file
+//
+// Returns:
+//   - One of the AudioFormat constants, or the upper-case extension without the dot for unknown formats
+func AudioFormatFromPath(filePath string) string {
+	switch ext := strings.ToLower(filepath.Ext(filePath)); ext {
+	case ExtensionFLAC:
+		return AudioFormatFLAC
+	case ExtensionMP3:
+		return AudioFormatMP3
+	case ExtensionM4A, ".mp4":
+		return AudioFormatM4A
+	case ExtensionWAV:
+		return AudioFormatWAV
+	case ExtensionAIFF, ".aif":
+		return AudioFormatAIFF
+	default:
+		return strings.ToUpper(strings.TrimPrefix(ext, "."))
+	}
+}
+
+// readRawTags reads all tags of an audio file grouped by tag container.
+// Files without tags yield an empty result, not an error.
+//
+// Parameters:
+//   - filePath: The path to the audio file
+//   - format: The audio format, one of the AudioFormat constants
+//
+// Returns:
+//   - The raw tags per container
+//   - An error if the file cannot be opened or its tags cannot be parsed
+func readRawTags(filePath string, format string) (map[TagContainer]map[string]interface{}, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[TagContainer]map[string]interface{})
+
+	switch format {
+	case AudioFormatWAV:
+		return result, readChunkTags(file, result, binary.LittleEndian)
+	case AudioFormatAIFF:
+		return result, readChunkTags(file, result, binary.BigEndian)
+	}
+
+	metadata, err := tag.ReadFrom(file)
+	if err != nil {
+		if errors.Is(err, tag.ErrNoTagsFound) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	switch metadata.Format() {
+	case tag.VORBIS:
+		result[TagContainerVorbis] = metadata.Raw()
+	case tag.MP4:
+		result[TagContainerMP4] = metadata.Raw()
+	case tag.ID3v2_2, tag.ID3v2_3, tag.ID3v2_4:
+		result[TagContainerID3v2] = metadata.Raw()
+	}
+	return result, nil
+}
+
+// readChunkTags walks the chunks of a RIFF (WAV) or FORM (AIFF) file and reads the ID3v2 chunk
+// and the RIFF INFO list. WAV uses little-endian chunk sizes, AIFF big-endian.
+func readChunkTags(file *os.File, result map[TagContainer]map[string]interface{}, order binary.ByteOrder) error {
+	header := make([]byte, 12)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return err
+	}
+	if id := string(header[0:4]); id != "RIFF" && id != "FORM" {
+		return fmt.Errorf("unknown container %q", id)
+	}
+
+	offset := int64(12)
+	chunk := make([]byte, 8)
+	for {
+		if _, err := file.ReadAt(chunk, offset); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		id := string(chunk[0:4])
+		size := int64(order.Uint32(chunk[4:8]))
+		data := io.NewSectionReader(file, offset+8, size)
+
+		switch {
+		case strings.EqualFold(id, "id3 "):
+			metadata, err := tag.ReadID3v2Tags(data)
+			if err != nil {
+				return err
+			}
+			result[TagContainerID3v2] = metadata.Raw()
+		case id == "LIST":
+			info, err := readRIFFInfo(data, size)
+			if err != nil {
+				return err
+			}
+			if info != nil {
+				result[TagContainerRIFF] = info
+			}
+		}
+
+		// Chunks are padded to an even size
+		offset += 8 + size + size%2
+	}
+}
+
+// readRIFFInfo reads the text entries of a RIFF LIST chunk of type INFO (IART, INAM, ICRD, ...).
+// Lists of other types yield nil.
+func readRIFFInfo(r *io.SectionReader, size int64) (map[string]interface{}, error) {
+	listType := make([]byte, 4)
+	if _, err := r.ReadAt(listType, 0); err != nil {
+		return nil, err
+	}
+	if string(listType) != "INFO" {
+		return nil, nil
+	}
+
+	info := make(map[string]interface{})
+	offset := int64(4)
+	header := make([]byte, 8)
+	for offset+8 <= size {
+		if _, err := r.ReadAt(header, offset); err != nil {
+			return nil, err
+		}
+		entrySize := int64(binary.LittleEndian.Uint32(header[4:8]))
+		value := make([]byte, entrySize)
+		if _, err := r.ReadAt(value, offset+8); err != nil {
+			return nil, err
+		}
+		info[string(header[0:4])] = strings.TrimRight(string(value), "\x00 ")
+		offset += 8 + entrySize + entrySize%2
+	}
+	return info, nil
+}
+
+// rawTagValue returns the text value of a tag key in the raw tags of one container.
+// Keys are matched case-insensitively, "TXXX:<description>" matches user defined ID3v2 text frames.
+func rawTagValue(raw map[string]interface{}, key string) string {
+	if strings.HasPrefix(key, tagKeyTXXX) {
+		description := strings.TrimPrefix(key, tagKeyTXXX)
+		for name, value := range raw {
+			if !strings.HasPrefix(name, "TXXX") && !strings.HasPrefix(name, "TXX") {
+				continue
+			}
+			if comm, ok := value.(*tag.Comm); ok && strings.EqualFold(comm.Description, description) {
+				return strings.TrimSpace(comm.Text)
+			}
+		}
+		return ""
+	}
+
+	value, ok := raw[key]
+	if !ok {
+		for name, v := range raw {
+			if strings.EqualFold(name, key) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case *tag.Comm:
+		return strings.TrimSpace(v.Text)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return ""
+	}
+}
+
+// ParseTagNumber returns the number of a track or disc number tag. Values in the form "3/12"
+// (number and total count) are accepted, the total count is ignored.
+//
+// Parameters:
+//   - value: The text value of the tag
+//
+// Returns:
+//   - The number and true, or 0 and false if the value is not a positive number
+func ParseTagNumber(value string) (int64, bool) {
+	if i := strings.Index(value, "/"); i >= 0 {
+		value = value[:i]
+	}
+	number, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || number <= 0 {
+		return 0, false
+	}
+	return number, true
+}
+
+// cleanGenre removes the ID3v1 genre reference ("(17)Rock") ID3v2 taggers put in front of the genre name.
+// References without a name are kept, they cannot be resolved without the ID3v1 genre list.
+func cleanGenre(value string) string {
+	if strings.HasPrefix(value, "(") {
+		if end := strings.Index(value, ")"); end > 0 && end < len(value)-1 {
+			if _, err := strconv.Atoi(value[1:end]); err == nil {
+				return strings.TrimSpace(value[end+1:])
+			}
+		}
+	}
+	return value
+}
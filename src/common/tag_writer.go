@@ -0,0 +1,769 @@
+// common/tag_writer.go
+
+// Package common implements shared functionality used across the MetaRekordFixer application.
+// This file contains the reading and writing of tags in FLAC files (Vorbis comments) and MP3 files (ID3v2).
+// Tags are addressed by the internal field names of metadata_map.csv.
+
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"MetaRekordFixer/locales"
+)
+
+// TagWriteExtensions lists the extensions of files whose tags can be written.
+var TagWriteExtensions = []string{ExtensionFLAC, ExtensionMP3}
+
+// tagWritePadding is the padding reserved when a tag grows, so later writes can update the file in place.
+const tagWritePadding = 4096
+
+// FLAC metadata block types used by the tag writer and the artwork import.
+const (
+	flacBlockStreamInfo    = 0
+	flacBlockPadding       = 1
+	flacBlockVorbisComment = 4
+	flacBlockPicture       = 6
+)
+
+// flacMaxBlockLength is the largest length of a FLAC metadata block.
+const flacMaxBlockLength = 1<<24 - 1
+
+// vorbisCommentKeys translates the ffmpeg option names of the FLAC column of metadata_map.csv
+// to the Vorbis comment names ffmpeg writes for them. Other keys are Vorbis comment names already.
+var vorbisCommentKeys = map[string]string{
+	"album_artist": "albumartist",
+	"track":        "tracknumber",
+	"disc":         "discnumber",
+}
+
+// flacTagKey returns the Vorbis comment name of an internal field, empty if the field has no FLAC key.
+func flacTagKey(mapping *MetadataMap, field string) string {
+	key := mapping.TagKey(ExtensionFLAC, field)
+	if vorbisKey, ok := vorbisCommentKeys[strings.ToLower(key)]; ok {
+		return vorbisKey
+	}
+	return key
+}
+
+// ID3v2 text encodings.
+const (
+	id3EncodingLatin1  = 0
+	id3EncodingUTF16   = 1
+	id3EncodingUTF16BE = 2
+	id3EncodingUTF8    = 3
+)
+
+// ReadFileTags returns the current tag values of internal fields in a FLAC or MP3 file.
+// Fields without a tag key for the format or without a value in the file are returned empty.
+//
+// Parameters:
+//   - filePath: Path to the audio file
+//   - mapping: The field mapping loaded from metadata_map.csv
+//   - fields: The internal field names to read
+//
+// Returns:
+//   - The tag values keyed by internal field name
+//   - An error if the format is not supported or the tags cannot be read
+func ReadFileTags(filePath string, mapping *MetadataMap, fields []string) (map[string]string, error) {
+	extension := strings.ToLower(filepath.Ext(filePath))
+	values := make(map[string]string, len(fields))
+
+	switch extension {
+	case ExtensionFLAC:
+		blocks, _, err := readFlacMetadata(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagread"), err)
+		}
+		comment := &vorbisComment{}
+		if index := flacBlockIndex(blocks, flacBlockVorbisComment); index >= 0 {
+			if comment, err = parseVorbisComment(blocks[index].data); err != nil {
+				return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagread"), err)
+			}
+		}
+		for _, field := range fields {
+			if key := flacTagKey(mapping, field); key != "" {
+				values[field] = comment.get(key)
+			}
+		}
+	case ExtensionMP3:
+		tag, err := readID3Tag(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", locales.Translate("common.err.tagread"), err)
+		}
+		for _, field := range fields {
+			if key := mapping.TagKey(extension, field); key != "" {
+				values[field] = tag.get(key)
+			}
+		}
+	default:
+		return nil, fmt.Errorf(locales.Translate("common.err.tagformat"), extension)
+	}
+
+	return values, nil
+}
+
+// WriteFileTags writes values of internal fields into the tags of a FLAC or MP3 file.
+// Existing values of the fields are replaced, all other tags, pictures and the audio data are kept.
+// The file is updated in place when the tag fits into its current space including padding,
+// otherwise it is rewritten through a temporary file which replaces the original only when complete.
+//
+// Parameters:
+//   - filePath: Path to the audio file
+//   - mapping: The field mapping loaded from metadata_map.csv
+//   - values: The values to write keyed by internal field name; fields without a tag key are skipped
+//
+// Returns:
+//   - An error if the format is not supported or the file cannot be read or written
+func WriteFileTags(filePath string, mapping *MetadataMap, values map[string]string) error {
+	extension := strings.ToLower(filepath.Ext(filePath))
+
+	var err error
+	switch extension {
+	case ExtensionFLAC:
+		err = writeFlacTags(filePath, mapping, values)
+	case ExtensionMP3:
+		err = writeID3Tags(filePath, mapping, values)
+	default:
+		return fmt.Errorf(locales.Translate("common.err.tagformat"), extension)
+	}
+	if err != nil {
+		return fmt.Errorf("%s '%s': %w", locales.Translate("common.err.tagwrite"), filepath.Base(filePath), err)
+	}
+	return nil
+}
+
+// writeFileHeader replaces the first oldLength bytes of a file with header.
+// A header of the same length is written in place; otherwise the file is copied with the new header
+// to a temporary file in the same folder, which then replaces the original.
+func writeFileHeader(filePath string, header []byte, oldLength int64) error {
+	if int64(len(header)) == oldLength {
+		file, err := os.OpenFile(filePath, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		if _, err := file.WriteAt(header, 0); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	source, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := source.Seek(oldLength, io.SeekStart); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(filePath), "."+filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tempPath := temp.Name()
+	writeErr := func() error {
+		if _, err := temp.Write(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(temp, source); err != nil {
+			return err
+		}
+		if err := temp.Chmod(info.Mode()); err != nil {
+			return err
+		}
+		return temp.Sync()
+	}()
+	if closeErr := temp.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(tempPath)
+		return writeErr
+	}
+
+	// The source must be closed before it can be replaced on Windows
+	source.Close()
+	if err := os.Rename(tempPath, filePath); err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return nil
+}
+
+// flacBlock is a metadata block of a FLAC file.
+type flacBlock struct {
+	kind byte
+	data []byte
+}
+
+// readFlacMetadata reads the metadata blocks of a FLAC file.
+// Returns the blocks and the offset of the first audio frame.
+func readFlacMetadata(filePath string) ([]flacBlock, int64, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != "fLaC" {
+		return nil, 0, errors.New(locales.Translate("common.err.tagflac"))
+	}
+
+	offset := int64(4)
+	var blocks []flacBlock
+	for {
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(reader, header); err != nil {
+			return nil, 0, errors.New(locales.Translate("common.err.tagflac"))
+		}
+		length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, 0, errors.New(locales.Translate("common.err.tagflac"))
+		}
+		blocks = append(blocks, flacBlock{kind: header[0] & 0x7F, data: data})
+		offset += int64(4 + length)
+		if header[0]&0x80 != 0 {
+			break
+		}
+	}
+	return blocks, offset, nil
+}
+
+// flacBlockIndex returns the index of the first block of a type, -1 if there is none.
+func flacBlockIndex(blocks []flacBlock, kind byte) int {
+	for i, block := range blocks {
+		if block.kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+// encodeFlacMetadata encodes the stream marker and the metadata blocks.
+// A padding block of the given length is appended if padding is not negative.
+func encodeFlacMetadata(blocks []flacBlock, padding int) []byte {
+	if padding >= 0 {
+		blocks = append(blocks, flacBlock{kind: flacBlockPadding, data: make([]byte, padding)})
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString("fLaC")
+	for i, block := range blocks {
+		kind := block.kind
+		if i == len(blocks)-1 {
+			kind |= 0x80
+		}
+		length := len(block.data)
+		buffer.Write([]byte{kind, byte(length >> 16), byte(length >> 8), byte(length)})
+		buffer.Write(block.data)
+	}
+	return buffer.Bytes()
+}
+
+// writeFlacTags replaces the values of the fields in the Vorbis comment block of a FLAC file.
+func writeFlacTags(filePath string, mapping *MetadataMap, values map[string]string) error {
+	blocks, audioOffset, err := readFlacMetadata(filePath)
+	if err != nil {
+		return err
+	}
+
+	comment := &vorbisComment{vendor: AppName}
+	index := flacBlockIndex(blocks, flacBlockVorbisComment)
+	if index >= 0 {
+		if comment, err = parseVorbisComment(blocks[index].data); err != nil {
+			return err
+		}
+	}
+	for field, value := range values {
+		if key := flacTagKey(mapping, field); key != "" {
+			comment.set(key, value)
+		}
+	}
+	data := comment.bytes()
+	if len(data) > flacMaxBlockLength {
+		return errors.New(locales.Translate("common.err.tagsize"))
+	}
+
+	// Padding is dropped and added again at the end, sized to fill the space of the old metadata
+	var kept []flacBlock
+	for i, block := range blocks {
+		switch {
+		case block.kind == flacBlockPadding:
+			continue
+		case i == index:
+			block.data = data
+		}
+		kept = append(kept, block)
+		if index < 0 && block.kind == flacBlockStreamInfo {
+			kept = append(kept, flacBlock{kind: flacBlockVorbisComment, data: data})
+		}
+	}
+
+	size := int64(len(encodeFlacMetadata(kept, -1)))
+	padding := tagWritePadding
+	switch {
+	case size == audioOffset:
+		padding = -1
+	case size+4 <= audioOffset:
+		padding = int(audioOffset - size - 4)
+	}
+	return writeFileHeader(filePath, encodeFlacMetadata(kept, padding), audioOffset)
+}
+
+// vorbisComment is the content of a Vorbis comment block.
+type vorbisComment struct {
+	vendor   string
+	comments []string
+}
+
+// parseVorbisComment decodes a Vorbis comment block.
+func parseVorbisComment(data []byte) (*vorbisComment, error) {
+	invalid := errors.New(locales.Translate("common.err.tagflac"))
+	readString := func() (string, error) {
+		if len(data) < 4 {
+			return "", invalid
+		}
+		length := binary.LittleEndian.Uint32(data)
+		if uint64(length) > uint64(len(data)-4) {
+			return "", invalid
+		}
+		value := string(data[4 : 4+length])
+		data = data[4+length:]
+		return value, nil
+	}
+
+	vendor, err := readString()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, invalid
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	comment := &vorbisComment{vendor: vendor}
+	for i := uint32(0); i < count; i++ {
+		entry, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		comment.comments = append(comment.comments, entry)
+	}
+	return comment, nil
+}
+
+// bytes encodes the Vorbis comment block.
+func (vc *vorbisComment) bytes() []byte {
+	var buffer bytes.Buffer
+	writeString := func(value string) {
+		binary.Write(&buffer, binary.LittleEndian, uint32(len(value)))
+		buffer.WriteString(value)
+	}
+	writeString(vc.vendor)
+	binary.Write(&buffer, binary.LittleEndian, uint32(len(vc.comments)))
+	for _, entry := range vc.comments {
+		writeString(entry)
+	}
+	return buffer.Bytes()
+}
+
+// get returns the first value of a key, keys are case-insensitive.
+func (vc *vorbisComment) get(key string) string {
+	for _, entry := range vc.comments {
+		if name, value, ok := strings.Cut(entry, "="); ok && strings.EqualFold(name, key) {
+			return value
+		}
+	}
+	return ""
+}
+
+// set replaces all values of a key with a single value.
+func (vc *vorbisComment) set(key string, value string) {
+	kept := vc.comments[:0]
+	for _, entry := range vc.comments {
+		if name, _, ok := strings.Cut(entry, "="); ok && strings.EqualFold(name, key) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	vc.comments = append(kept, strings.ToUpper(key)+"="+value)
+}
+
+// id3Frame is a frame of an ID3v2 tag with its raw content.
+type id3Frame struct {
+	id    string
+	flags [2]byte
+	data  []byte
+}
+
+// id3Tag is an ID3v2 tag read from the start of a file.
+type id3Tag struct {
+	// version is the major version, 3 or 4
+	version byte
+	frames  []id3Frame
+	// length is the number of bytes the tag occupies in the file, 0 if the file has no tag
+	length int64
+}
+
+// readID3Tag reads the ID3v2 tag of a file.
+// Files without a tag return an empty ID3v2.3 tag; ID3v2.2 tags are not supported.
+func readID3Tag(filePath string) (*id3Tag, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 10)
+	if _, err := io.ReadFull(file, header); err != nil || string(header[:3]) != "ID3" {
+		return &id3Tag{version: 3}, nil
+	}
+	version := header[3]
+	if version != 3 && version != 4 {
+		return nil, fmt.Errorf(locales.Translate("common.err.tagid3version"), version)
+	}
+	flags := header[5]
+	size := syncsafeInt(header[6:10])
+	data := make([]byte, size)
+	if _, err := io.ReadFull(file, data); err != nil {
+		return nil, errors.New(locales.Translate("common.err.tagid3"))
+	}
+
+	tag := &id3Tag{version: version, length: int64(10 + size)}
+	if version == 4 && flags&0x10 != 0 {
+		tag.length += 10
+	}
+	// ID3v2.3 applies unsynchronisation to the whole tag, ID3v2.4 to single frames
+	if version == 3 && flags&0x80 != 0 {
+		data = removeUnsynchronisation(data)
+	}
+
+	pos := 0
+	if flags&0x40 != 0 && len(data) >= 4 {
+		if version == 3 {
+			pos = 4 + int(binary.BigEndian.Uint32(data))
+		} else {
+			pos = syncsafeInt(data[:4])
+		}
+	}
+	for pos+10 <= len(data) && data[pos] != 0 {
+		frameSize := int(binary.BigEndian.Uint32(data[pos+4:]))
+		if version == 4 {
+			frameSize = syncsafeInt(data[pos+4 : pos+8])
+		}
+		if pos+10+frameSize > len(data) {
+			return nil, errors.New(locales.Translate("common.err.tagid3"))
+		}
+		frame := id3Frame{id: string(data[pos : pos+4]), flags: [2]byte{data[pos+8], data[pos+9]}}
+		frame.data = append([]byte(nil), data[pos+10:pos+10+frameSize]...)
+		tag.frames = append(tag.frames, frame)
+		pos += 10 + frameSize
+	}
+	return tag, nil
+}
+
+// syncsafeInt decodes a 28 bit integer stored in four bytes of 7 bits.
+func syncsafeInt(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// syncsafeBytes encodes a 28 bit integer into four bytes of 7 bits.
+func syncsafeBytes(value int) []byte {
+	return []byte{byte(value>>21) & 0x7F, byte(value>>14) & 0x7F, byte(value>>7) & 0x7F, byte(value) & 0x7F}
+}
+
+// removeUnsynchronisation removes the zero bytes inserted after 0xFF bytes.
+func removeUnsynchronisation(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		result = append(result, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0x00 {
+			i++
+		}
+	}
+	return result
+}
+
+// content returns the decoded content of a frame.
+// Returns false for compressed and encrypted frames, which cannot be decoded.
+func (f id3Frame) content(version byte) ([]byte, bool) {
+	data := f.data
+	if version == 3 {
+		if f.flags[1]&0xC0 != 0 {
+			return nil, false
+		}
+		if f.flags[1]&0x20 != 0 && len(data) > 0 {
+			data = data[1:]
+		}
+		return data, true
+	}
+
+	if f.flags[1]&0x0C != 0 {
+		return nil, false
+	}
+	if f.flags[1]&0x40 != 0 && len(data) > 0 {
+		data = data[1:]
+	}
+	if f.flags[1]&0x01 != 0 && len(data) >= 4 {
+		data = data[4:]
+	}
+	if f.flags[1]&0x02 != 0 {
+		data = removeUnsynchronisation(data)
+	}
+	return data, true
+}
+
+// isID3FrameID reports whether a tag key of the mapping is a frame ID; other keys are stored in TXXX frames.
+func isID3FrameID(key string) bool {
+	if len(key) != 4 {
+		return false
+	}
+	for _, r := range key {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return key != "TXXX"
+}
+
+// matches reports whether a frame holds the value of a tag key.
+// Comments match only without a description, so player specific comments like iTunNORM are kept.
+func (f id3Frame) matches(version byte, key string) bool {
+	switch {
+	case key == "COMM":
+		if f.id != "COMM" {
+			return false
+		}
+		data, ok := f.content(version)
+		if !ok || len(data) < 4 {
+			return false
+		}
+		description, _ := splitID3Text(data[0], data[4:])
+		return len(description) == 0
+	case isID3FrameID(key):
+		return f.id == key
+	default:
+		if f.id != "TXXX" {
+			return false
+		}
+		data, ok := f.content(version)
+		if !ok || len(data) < 1 {
+			return false
+		}
+		description, _ := splitID3Text(data[0], data[1:])
+		return strings.EqualFold(decodeID3Text(data[0], description), key)
+	}
+}
+
+// get returns the value of a tag key; ratings are returned as stars (0-5).
+func (t *id3Tag) get(key string) string {
+	for _, frame := range t.frames {
+		if !frame.matches(t.version, key) {
+			continue
+		}
+		data, ok := frame.content(t.version)
+		if !ok || len(data) == 0 {
+			return ""
+		}
+		switch {
+		case key == "POPM":
+			_, rest, found := bytes.Cut(data, []byte{0})
+			if !found || len(rest) == 0 {
+				return ""
+			}
+			return strconv.Itoa(ratingFromXML(int(rest[0])))
+		case key == "COMM":
+			if len(data) < 4 {
+				return ""
+			}
+			_, text := splitID3Text(data[0], data[4:])
+			return decodeID3Text(data[0], text)
+		case isID3FrameID(key):
+			return decodeID3Text(data[0], data[1:])
+		default:
+			_, value := splitID3Text(data[0], data[1:])
+			return decodeID3Text(data[0], value)
+		}
+	}
+	return ""
+}
+
+// set replaces all frames of a tag key with a frame holding the value.
+// Ratings are given as stars (0-5) and written to a POPM frame on the 0-255 scale.
+func (t *id3Tag) set(key string, value string) {
+	kept := t.frames[:0]
+	for _, frame := range t.frames {
+		if !frame.matches(t.version, key) {
+			kept = append(kept, frame)
+		}
+	}
+	t.frames = kept
+
+	encoding, text, terminator := encodeID3Text(t.version, value)
+	var data []byte
+	id := key
+	switch {
+	case key == "POPM":
+		stars, _ := strconv.Atoi(value)
+		data = []byte{0, byte(xmlRating(stars))}
+	case key == "COMM":
+		data = append([]byte{encoding, 'e', 'n', 'g'}, terminator...)
+		data = append(data, text...)
+	case isID3FrameID(key):
+		data = append([]byte{encoding}, text...)
+	default:
+		// The description shares the encoding of the value
+		_, description, _ := encodeID3TextAs(encoding, key)
+		id = "TXXX"
+		data = append([]byte{encoding}, description...)
+		data = append(data, terminator...)
+		data = append(data, text...)
+	}
+	t.frames = append(t.frames, id3Frame{id: id, data: data})
+}
+
+// bytes encodes the tag with a padding of the given length.
+// Unsynchronisation, the extended header and the footer are not written.
+func (t *id3Tag) bytes(padding int) []byte {
+	var frames bytes.Buffer
+	for _, frame := range t.frames {
+		frames.WriteString(frame.id)
+		if t.version == 4 {
+			frames.Write(syncsafeBytes(len(frame.data)))
+		} else {
+			binary.Write(&frames, binary.BigEndian, uint32(len(frame.data)))
+		}
+		frames.Write(frame.flags[:])
+		frames.Write(frame.data)
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte{'I', 'D', '3', t.version, 0, 0})
+	buffer.Write(syncsafeBytes(frames.Len() + padding))
+	buffer.Write(frames.Bytes())
+	buffer.Write(make([]byte, padding))
+	return buffer.Bytes()
+}
+
+// writeID3Tags replaces the values of the fields in the ID3v2 tag of an MP3 file.
+// Files without a tag get a new ID3v2.3 tag.
+func writeID3Tags(filePath string, mapping *MetadataMap, values map[string]string) error {
+	tag, err := readID3Tag(filePath)
+	if err != nil {
+		return err
+	}
+	for field, value := range values {
+		if key := mapping.TagKey(ExtensionMP3, field); key != "" {
+			tag.set(key, value)
+		}
+	}
+
+	size := int64(len(tag.bytes(0)))
+	padding := tagWritePadding
+	if tag.length > 0 && size <= tag.length {
+		padding = int(tag.length - size)
+	}
+	if int(size)+padding-10 > 1<<28-1 {
+		return errors.New(locales.Translate("common.err.tagsize"))
+	}
+	return writeFileHeader(filePath, tag.bytes(padding), tag.length)
+}
+
+// splitID3Text splits text at the first terminator of the encoding.
+// Returns the text before the terminator and the rest after it.
+func splitID3Text(encoding byte, data []byte) ([]byte, []byte) {
+	if encoding == id3EncodingUTF16 || encoding == id3EncodingUTF16BE {
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				return data[:i], data[i+2:]
+			}
+		}
+		return data, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return data[:i], data[i+1:]
+	}
+	return data, nil
+}
+
+// decodeID3Text decodes text of an encoding. Of several values separated by terminators, the first is returned.
+func decodeID3Text(encoding byte, data []byte) string {
+	data, _ = splitID3Text(encoding, data)
+	switch encoding {
+	case id3EncodingUTF16, id3EncodingUTF16BE:
+		bigEndian := encoding == id3EncodingUTF16BE
+		if len(data) >= 2 {
+			switch {
+			case data[0] == 0xFF && data[1] == 0xFE:
+				bigEndian, data = false, data[2:]
+			case data[0] == 0xFE && data[1] == 0xFF:
+				bigEndian, data = true, data[2:]
+			}
+		}
+		units := make([]uint16, 0, len(data)/2)
+		for i := 0; i+1 < len(data); i += 2 {
+			if bigEndian {
+				units = append(units, binary.BigEndian.Uint16(data[i:]))
+			} else {
+				units = append(units, binary.LittleEndian.Uint16(data[i:]))
+			}
+		}
+		return string(utf16.Decode(units))
+	case id3EncodingUTF8:
+		return string(data)
+	default:
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+}
+
+// encodeID3Text encodes text for a tag version.
+// ID3v2.4 uses UTF-8; ID3v2.3 uses ISO-8859-1 for ASCII text and UTF-16 with BOM otherwise.
+// Returns the encoding, the encoded text and the terminator of the encoding.
+func encodeID3Text(version byte, value string) (byte, []byte, []byte) {
+	if version == 4 {
+		return encodeID3TextAs(id3EncodingUTF8, value)
+	}
+	for _, r := range value {
+		if r >= 0x80 {
+			return encodeID3TextAs(id3EncodingUTF16, value)
+		}
+	}
+	return encodeID3TextAs(id3EncodingLatin1, value)
+}
+
+// encodeID3TextAs encodes text in the given encoding.
+// Returns the encoding, the encoded text and the terminator of the encoding.
+func encodeID3TextAs(encoding byte, value string) (byte, []byte, []byte) {
+	if encoding == id3EncodingUTF16 {
+		data := []byte{0xFF, 0xFE}
+		for _, unit := range utf16.Encode([]rune(value)) {
+			data = binary.LittleEndian.AppendUint16(data, unit)
+		}
+		return encoding, data, []byte{0, 0}
+	}
+	return encoding, []byte(value), []byte{0}
+}
@@ -0,0 +1,340 @@
+// common/tag_writer_test.go
+
+// Round-trip tests of the tag writer: tags are written into copies of the FLAC and MP3 fixtures in testdata,
+// then read back by the tag writer and by the tag library used by the tag reader.
+
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dhowden/tag"
+)
+
+// copyFixture copies a file of testdata into a temporary folder and returns the path of the copy.
+func copyFixture(t *testing.T, name string, prefix []byte) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, append(append([]byte(nil), prefix...), data...), 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	return path
+}
+
+// loadTestMetadataMap loads the metadata mapping shipped with the application.
+func loadTestMetadataMap(t *testing.T) *MetadataMap {
+	t.Helper()
+	mapping, err := LoadMetadataMap()
+	if err != nil {
+		t.Fatalf("load metadata map: %v", err)
+	}
+	return mapping
+}
+
+// readTagLibrary reads a file with the tag library.
+func readTagLibrary(t *testing.T, path string) tag.Metadata {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer file.Close()
+	metadata, err := tag.ReadFrom(file)
+	if err != nil {
+		t.Fatalf("tag library cannot read %s: %v", filepath.Base(path), err)
+	}
+	return metadata
+}
+
+// flacAudio returns the audio frames of a FLAC file, everything after the metadata blocks.
+func flacAudio(t *testing.T, path string) []byte {
+	t.Helper()
+	_, offset, err := readFlacMetadata(path)
+	if err != nil {
+		t.Fatalf("read FLAC metadata: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return data[offset:]
+}
+
+// checkTags compares the tag values read back by ReadFileTags with the expected values.
+func checkTags(t *testing.T, path string, mapping *MetadataMap, want map[string]string) {
+	t.Helper()
+	fields := make([]string, 0, len(want))
+	for field := range want {
+		fields = append(fields, field)
+	}
+	got, err := ReadFileTags(path, mapping, fields)
+	if err != nil {
+		t.Fatalf("ReadFileTags: %v", err)
+	}
+	for field, value := range want {
+		if got[field] != value {
+			t.Errorf("%s = %q, want %q", field, got[field], value)
+		}
+	}
+}
+
+func TestFlacTagKeyUsesVorbisNames(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+	for field, want := range map[string]string{
+		"tracknumber": "tracknumber",
+		"discnumber":  "discnumber",
+		"albumartist": "albumartist",
+		"title":       "title",
+	} {
+		if got := flacTagKey(mapping, field); got != want {
+			t.Errorf("flacTagKey(%q) = %q, want %q", field, got, want)
+		}
+	}
+}
+
+func TestWriteFileTagsFLACRoundTrip(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+	path := copyFixture(t, "silence.flac", nil)
+	audio := flacAudio(t, path)
+
+	values := map[string]string{
+		"title":       "Nový název",
+		"albumartist": "Various Artists",
+		"tracknumber": "7",
+		"discnumber":  "2",
+		"genre":       "Techno",
+		"comment":     "Warmup /* Peak / Vocal */",
+	}
+	if err := WriteFileTags(path, mapping, values); err != nil {
+		t.Fatalf("WriteFileTags: %v", err)
+	}
+	checkTags(t, path, mapping, values)
+
+	metadata := readTagLibrary(t, path)
+	if metadata.Format() != tag.VORBIS {
+		t.Fatalf("format = %v, want VORBIS", metadata.Format())
+	}
+	if metadata.Title() != values["title"] || metadata.Artist() != "Fixture Artist" || metadata.Genre() != "Techno" {
+		t.Errorf("tag library read title %q, artist %q, genre %q", metadata.Title(), metadata.Artist(), metadata.Genre())
+	}
+	if track, _ := metadata.Track(); track != 7 {
+		t.Errorf("tag library read track %d, want 7", track)
+	}
+	if disc, _ := metadata.Disc(); disc != 2 {
+		t.Errorf("tag library read disc %d, want 2", disc)
+	}
+	raw := metadata.Raw()
+	if raw["albumartist"] != "Various Artists" {
+		t.Errorf("albumartist = %v, want Various Artists", raw["albumartist"])
+	}
+	for _, key := range []string{"track", "disc", "album_artist"} {
+		if _, ok := raw[key]; ok {
+			t.Errorf("ffmpeg option name %q written as Vorbis comment", key)
+		}
+	}
+
+	if !bytes.Equal(flacAudio(t, path), audio) {
+		t.Fatal("audio frames changed")
+	}
+
+	// Writing the same values again fits into the padding and keeps the file size
+	info, _ := os.Stat(path)
+	if err := WriteFileTags(path, mapping, values); err != nil {
+		t.Fatalf("second WriteFileTags: %v", err)
+	}
+	again, _ := os.Stat(path)
+	if again.Size() != info.Size() {
+		t.Errorf("size changed from %d to %d on an unchanged write", info.Size(), again.Size())
+	}
+}
+
+func TestWriteFileTagsFLACGrowsAndKeepsBlocks(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+
+	// A PICTURE block is added to the fixture and has to survive the rewrite
+	path := copyFixture(t, "silence.flac", nil)
+	blocks, offset, err := readFlacMetadata(path)
+	if err != nil {
+		t.Fatalf("read FLAC metadata: %v", err)
+	}
+	var picture []byte
+	picture = binary.BigEndian.AppendUint32(picture, 3)
+	picture = binary.BigEndian.AppendUint32(picture, uint32(len("image/png")))
+	picture = append(picture, "image/png"...)
+	picture = binary.BigEndian.AppendUint32(picture, 0)
+	picture = append(picture, make([]byte, 16)...)
+	picture = binary.BigEndian.AppendUint32(picture, 4)
+	picture = append(picture, 0x89, 'P', 'N', 'G')
+	blocks = append(blocks, flacBlock{kind: flacBlockPicture, data: picture})
+	data, _ := os.ReadFile(path)
+	if err := os.WriteFile(path, append(encodeFlacMetadata(blocks, -1), data[offset:]...), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	audio := flacAudio(t, path)
+
+	// The comment is larger than the padding, so the file is rewritten
+	values := map[string]string{"comment": strings.Repeat("long comment ", 1000)}
+	if err := WriteFileTags(path, mapping, values); err != nil {
+		t.Fatalf("WriteFileTags: %v", err)
+	}
+	checkTags(t, path, mapping, values)
+	checkTags(t, path, mapping, map[string]string{"title": "Old title", "tracknumber": "1"})
+
+	blocks, _, err = readFlacMetadata(path)
+	if err != nil {
+		t.Fatalf("read FLAC metadata: %v", err)
+	}
+	if blocks[0].kind != flacBlockStreamInfo {
+		t.Errorf("first block is %d, want STREAMINFO", blocks[0].kind)
+	}
+	index := flacBlockIndex(blocks, flacBlockPicture)
+	if index < 0 || !bytes.Equal(blocks[index].data, picture) {
+		t.Error("PICTURE block lost")
+	}
+	if !bytes.Equal(flacAudio(t, path), audio) {
+		t.Fatal("audio frames changed")
+	}
+	readTagLibrary(t, path)
+}
+
+func TestWriteFileTagsMP3RoundTrip(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+	path := copyFixture(t, "silence.mp3", nil)
+	audio, _ := os.ReadFile(path)
+
+	values := map[string]string{
+		"title":       "Nový název",
+		"artist":      "Fixture Artist",
+		"tracknumber": "7",
+		"comment":     "Warmup",
+		"rating":      "4",
+		"label":       "Fixture Records",
+	}
+	if err := WriteFileTags(path, mapping, values); err != nil {
+		t.Fatalf("WriteFileTags: %v", err)
+	}
+	checkTags(t, path, mapping, values)
+
+	metadata := readTagLibrary(t, path)
+	if metadata.Format() != tag.ID3v2_3 {
+		t.Fatalf("format = %v, want ID3v2.3", metadata.Format())
+	}
+	if metadata.Title() != values["title"] || metadata.Artist() != values["artist"] || metadata.Comment() != values["comment"] {
+		t.Errorf("tag library read title %q, artist %q, comment %q", metadata.Title(), metadata.Artist(), metadata.Comment())
+	}
+	if track, _ := metadata.Track(); track != 7 {
+		t.Errorf("tag library read track %d, want 7", track)
+	}
+
+	id3, err := readID3Tag(path)
+	if err != nil {
+		t.Fatalf("readID3Tag: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.Equal(data[id3.length:], audio) {
+		t.Fatal("audio frames changed")
+	}
+
+	// A second write of shorter values is done in place
+	values["title"] = "Short"
+	if err := WriteFileTags(path, mapping, values); err != nil {
+		t.Fatalf("second WriteFileTags: %v", err)
+	}
+	again, _ := os.ReadFile(path)
+	if len(again) != len(data) {
+		t.Errorf("size changed from %d to %d", len(data), len(again))
+	}
+	checkTags(t, path, mapping, values)
+}
+
+// id3v23Frame encodes an ID3v2.3 frame.
+func id3v23Frame(id string, data []byte) []byte {
+	frame := []byte(id)
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(data)))
+	frame = append(frame, 0, 0)
+	return append(frame, data...)
+}
+
+// id3Header encodes an ID3v2 header for a tag body.
+func id3Header(version byte, flags byte, body []byte) []byte {
+	header := []byte{'I', 'D', '3', version, 0, flags}
+	header = append(header, syncsafeBytes(len(body))...)
+	return append(header, body...)
+}
+
+func TestWriteFileTagsID3v23KeepsOtherFrames(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+
+	// An extended header, a player specific comment and a frame of the artist, followed by padding
+	var body []byte
+	body = append(body, 0, 0, 0, 6, 0, 0, 0, 0, 0, 0)
+	body = append(body, id3v23Frame("TPE1", append([]byte{id3EncodingLatin1}, "Fixture Artist"...))...)
+	body = append(body, id3v23Frame("COMM", append([]byte{id3EncodingLatin1, 'e', 'n', 'g'}, "iTunNORM\x00 000001"...))...)
+	body = append(body, make([]byte, 32)...)
+	path := copyFixture(t, "silence.mp3", id3Header(3, 0x40, body))
+
+	if err := WriteFileTags(path, mapping, map[string]string{"comment": "Warmup", "title": "New title"}); err != nil {
+		t.Fatalf("WriteFileTags: %v", err)
+	}
+	checkTags(t, path, mapping, map[string]string{"comment": "Warmup", "title": "New title", "artist": "Fixture Artist"})
+
+	metadata := readTagLibrary(t, path)
+	found := false
+	for _, value := range metadata.Raw() {
+		if comm, ok := value.(*tag.Comm); ok && comm.Description == "iTunNORM" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("comment with description iTunNORM lost")
+	}
+}
+
+func TestWriteFileTagsID3v24Unsynchronisation(t *testing.T) {
+	mapping := loadTestMetadataMap(t)
+
+	// The artist contains 0xFF in Latin-1, so unsynchronisation inserts a zero byte after it
+	artist := append([]byte{id3EncodingLatin1}, "Art\xff\x00st"...)
+	frame := []byte("TPE1")
+	frame = append(frame, syncsafeBytes(len(artist))...)
+	frame = append(frame, 0, 0x02)
+	frame = append(frame, artist...)
+
+	var body []byte
+	body = append(body, syncsafeBytes(6)...)
+	body = append(body, 1, 0)
+	body = append(body, frame...)
+	body = append(body, make([]byte, 16)...)
+	path := copyFixture(t, "silence.mp3", id3Header(4, 0x80|0x40, body))
+	audio, _ := os.ReadFile(filepath.Join("testdata", "silence.mp3"))
+
+	checkTags(t, path, mapping, map[string]string{"artist": "Artÿst"})
+
+	if err := WriteFileTags(path, mapping, map[string]string{"title": "Nový název"}); err != nil {
+		t.Fatalf("WriteFileTags: %v", err)
+	}
+	checkTags(t, path, mapping, map[string]string{"artist": "Artÿst", "title": "Nový název"})
+
+	metadata := readTagLibrary(t, path)
+	if metadata.Format() != tag.ID3v2_4 || metadata.Title() != "Nový název" {
+		t.Errorf("tag library read format %v, title %q", metadata.Format(), metadata.Title())
+	}
+
+	id3, err := readID3Tag(path)
+	if err != nil {
+		t.Fatalf("readID3Tag: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !bytes.Equal(data[id3.length:], audio) {
+		t.Fatal("audio frames changed")
+	}
+}
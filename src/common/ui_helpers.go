@@ -11,6 +11,8 @@ import (
 	"image/color"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -153,6 +155,37 @@ func CreateFolderSelectionField(title string, entryField *widget.Entry, changeHa
 	return container.NewBorder(nil, nil, nil, browseBtn, entryField)
 }
 
+// CreateFileSelectionField creates a standardized file selection field with browse button.
+// The browse button opens a native open dialog filtered to the given extensions (without dot).
+func CreateFileSelectionField(title string, entryField *widget.Entry, filterDesc string, extensions []string, changeHandler func(string)) fyne.CanvasObject {
+	// Create entry field if not provided
+	if entryField == nil {
+		entryField = widget.NewEntry()
+	}
+	entryField.SetPlaceHolder(locales.Translate("common.entry.placeholderfile"))
+
+	// Set change handler if provided
+	if changeHandler != nil {
+		entryField.OnChanged = func(value string) {
+			changeHandler(value)
+		}
+	}
+
+	// Create browse button (icon only)
+	browseBtn := widget.NewButtonWithIcon("", theme.FileIcon(), func() {
+		filename, err := nativedialog.File().Filter(filterDesc, extensions...).Title(title).Load()
+		if err == nil && filename != "" {
+			entryField.SetText(filename)
+			if changeHandler != nil {
+				changeHandler(filename)
+			}
+		}
+	})
+
+	// Create container with entry field and browse button
+	return container.NewBorder(nil, nil, nil, browseBtn, entryField)
+}
+
 // CreateFolderSelectionFieldWithDelete creates a standardized folder selection field with browse and delete buttons
 func CreateFolderSelectionFieldWithDelete(title string, entryField *widget.Entry, changeHandler func(string), deleteHandler func()) fyne.CanvasObject {
 	// Create entry field if not provided
@@ -351,6 +384,102 @@ func SetPlaylistSelectState(selectWidget *widget.Select, enabled bool, selectedV
 	}
 }
 
+// PlaylistSelectOptions builds the options of a playlist select showing the playlist tree.
+// Every option is indented by the depth of the playlist and marks folders and smart playlists,
+// regular playlists show their number of tracks. Options follow the order of playlists,
+// so the index of the selected option is the index of the playlist.
+//
+// Parameters:
+//   - playlists: Playlists in tree order as returned by DBManager.GetPlaylists
+//
+// Returns:
+//   - The option labels, one per playlist
+func PlaylistSelectOptions(playlists []PlaylistItem) []string {
+	options := make([]string, len(playlists))
+	used := make(map[string]bool, len(playlists))
+	for i, playlist := range playlists {
+		var label string
+		switch playlist.Type {
+		case PlaylistTypeFolder:
+			label = fmt.Sprintf(locales.Translate("common.select.plsfolder"), playlist.Name)
+		case PlaylistTypeSmart:
+			label = fmt.Sprintf(locales.Translate("common.select.plssmart"), playlist.Name, playlist.TrackCount)
+		default:
+			label = fmt.Sprintf(locales.Translate("common.select.plsitem"), playlist.Name, playlist.TrackCount)
+		}
+		label = strings.Repeat("    ", playlist.Depth) + label
+
+		// Select options must be unique, siblings with the same name are told apart by their ID
+		if used[label] {
+			label = fmt.Sprintf("%s #%s", label, playlist.ID)
+		}
+		used[label] = true
+		options[i] = label
+	}
+	return options
+}
+
+// SelectedPlaylist returns the playlist chosen in a select filled by PlaylistSelectOptions.
+//
+// Parameters:
+//   - playlists: The playlists the select options were built from
+//   - selectWidget: The playlist select
+//
+// Returns:
+//   - The selected playlist and true, or an empty PlaylistItem and false if nothing is selected
+func SelectedPlaylist(playlists []PlaylistItem, selectWidget *widget.Select) (PlaylistItem, bool) {
+	index := selectWidget.SelectedIndex()
+	if index < 0 || index >= len(playlists) {
+		return PlaylistItem{}, false
+	}
+	return playlists[index], true
+}
+
+// PlaylistOptionByID returns the option of a select filled by PlaylistSelectOptions for a playlist ID.
+//
+// Parameters:
+//   - playlists: The playlists the select options were built from
+//   - selectWidget: The playlist select
+//   - id: The ID of the playlist
+//
+// Returns:
+//   - The option label, or an empty string if the playlist is not in the list
+func PlaylistOptionByID(playlists []PlaylistItem, selectWidget *widget.Select, id string) string {
+	if id == "" {
+		return ""
+	}
+	for i, playlist := range playlists {
+		if playlist.ID == id && i < len(selectWidget.Options) {
+			return selectWidget.Options[i]
+		}
+	}
+	return ""
+}
+
+// GuardPlaylistSelection keeps folders from being selected in a playlist select.
+// Folders hold no tracks of their own; when one is chosen, the previous selection is restored.
+//
+// Parameters:
+//   - playlists: The playlists the select options were built from
+//   - selectWidget: The playlist select
+//   - previousID: The ID of the playlist selected before the change
+//
+// Returns:
+//   - true if the current selection may be used, false if a folder was rejected
+func GuardPlaylistSelection(playlists []PlaylistItem, selectWidget *widget.Select, previousID string) bool {
+	playlist, ok := SelectedPlaylist(playlists, selectWidget)
+	if !ok || playlist.Selectable() {
+		return true
+	}
+
+	if previous := PlaylistOptionByID(playlists, selectWidget, previousID); previous != "" {
+		selectWidget.SetSelected(previous)
+	} else {
+		selectWidget.ClearSelected()
+	}
+	return false
+}
+
 // CreateCheckbox creates a standardized checkbox with a label.
 // The checkbox is created with a given label text and change handler.
 // Parameters:
@@ -613,3 +742,338 @@ func CreateActionButton(initialText string, onAction func(), completedText strin
 
 	return btn
 }
+
+// ShowChangesetReviewDialog displays all pending changes of a changeset in a table with their old and new values.
+// The user can export the changes to a CSV or JSON file, apply them or discard them.
+//
+// Parameters:
+//   - window: The parent window for the dialog
+//   - cs: The changeset to review
+//   - onApply: Callback invoked when the user confirms the changes
+//   - onDiscard: Callback invoked when the user rejects the changes
+func ShowChangesetReviewDialog(window fyne.Window, cs *Changeset, onApply func(), onDiscard func()) {
+	// Flatten changes to one table line per changed column
+	headers := []string{
+		locales.Translate("common.changeset.table"),
+		locales.Translate("common.changeset.action"),
+		locales.Translate("common.changeset.item"),
+		locales.Translate("common.changeset.column"),
+		locales.Translate("common.changeset.old"),
+		locales.Translate("common.changeset.new"),
+	}
+	var lines [][]string
+	for _, change := range cs.Changes() {
+		item := change.Label
+		if item == "" {
+			item = fmt.Sprintf("%s = %s", change.KeyColumn, change.Key)
+		}
+		action := locales.Translate("common.changeset." + string(change.Action))
+		if len(change.Fields) == 0 {
+			lines = append(lines, []string{change.Table, action, item, "", "", ""})
+			continue
+		}
+		for _, field := range change.Fields {
+			oldValue := FormatChangeValue(field.Old)
+			if change.Action == ChangeActionInsert {
+				oldValue = ""
+			}
+			lines = append(lines, []string{change.Table, action, item, field.Column, oldValue, FormatChangeValue(field.New)})
+		}
+	}
+
+	table := newReviewTable(headers, lines, []float32{110, 80, 260, 130, 160, 160})
+
+	summaryLabel := widget.NewLabel(fmt.Sprintf(locales.Translate("common.changeset.summary"), cs.Len(), cs.SummaryText()))
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	var dlg *dialog.CustomDialog
+
+	exportBtn := widget.NewButtonWithIcon(locales.Translate("common.button.export"), theme.DocumentSaveIcon(), func() {
+		path, err := nativedialog.File().
+			Filter(locales.Translate("common.changeset.filtercsv"), "csv").
+			Filter(locales.Translate("common.changeset.filterjson"), "json").
+			Title(locales.Translate("common.changeset.exporttitle")).
+			Save()
+		if err != nil || path == "" {
+			return
+		}
+		if filepath.Ext(path) == "" {
+			path += ".csv"
+		}
+		if err := cs.Export(path); err != nil {
+			ShowStandardError(window, err, &ErrorContext{
+				Module:      "Changeset",
+				Operation:   "Export Changes",
+				Severity:    SeverityWarning,
+				Recoverable: true,
+			})
+		}
+	})
+
+	discardBtn := widget.NewButtonWithIcon(locales.Translate("common.button.discard"), theme.CancelIcon(), func() {
+		dlg.Hide()
+		if onDiscard != nil {
+			onDiscard()
+		}
+	})
+	discardBtn.Importance = widget.DangerImportance
+
+	applyBtn := widget.NewButtonWithIcon(locales.Translate("common.button.apply"), theme.ConfirmIcon(), func() {
+		dlg.Hide()
+		if onApply != nil {
+			onApply()
+		}
+	})
+	applyBtn.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		summaryLabel,
+		container.NewHBox(exportBtn, layout.NewSpacer(), discardBtn, applyBtn),
+		nil,
+		nil,
+		table,
+	)
+
+	dlg = dialog.NewCustomWithoutButtons(locales.Translate("common.changeset.header"), content, window)
+	dlg.Resize(fyne.NewSize(950, 600))
+	dlg.Show()
+}
+
+// newReviewTable creates a read-only table with a header row for review dialogs.
+//
+// Parameters:
+//   - headers: The column titles
+//   - lines: The table rows, each with one value per column
+//   - widths: The column widths
+//
+// Returns:
+//   - The table widget
+func newReviewTable(headers []string, lines [][]string, widths []float32) *widget.Table {
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(lines), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, object fyne.CanvasObject) {
+			object.(*widget.Label).SetText(lines[id.Row][id.Col])
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.TextStyle = fyne.TextStyle{Bold: true}
+		return label
+	}
+	table.UpdateHeader = func(id widget.TableCellID, object fyne.CanvasObject) {
+		if id.Col >= 0 {
+			object.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	for col, width := range widths {
+		table.SetColumnWidth(col, width)
+	}
+	return table
+}
+
+// ShowTagExportReviewDialog displays the tag changes of a tag export in a table with their old and new values.
+// The user can write the tags into the files or discard the export.
+//
+// Parameters:
+//   - window: The parent window for the dialog
+//   - items: The files and tag changes to review
+//   - onApply: Callback invoked when the user confirms the changes
+//   - onDiscard: Callback invoked when the user rejects the changes
+func ShowTagExportReviewDialog(window fyne.Window, items []TagExportItem, onApply func(), onDiscard func()) {
+	// Flatten changes to one table line per changed tag
+	headers := []string{
+		locales.Translate("common.tagreview.file"),
+		locales.Translate("common.tagreview.field"),
+		locales.Translate("common.changeset.old"),
+		locales.Translate("common.changeset.new"),
+	}
+	var lines [][]string
+	changes := 0
+	for _, item := range items {
+		for _, change := range item.Changes {
+			lines = append(lines, []string{filepath.Base(item.File), change.Field, change.Old, change.New})
+			changes++
+		}
+	}
+
+	table := newReviewTable(headers, lines, []float32{300, 120, 220, 220})
+
+	summaryLabel := widget.NewLabel(fmt.Sprintf(locales.Translate("common.tagreview.summary"), changes, len(items)))
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	var dlg *dialog.CustomDialog
+
+	discardBtn := widget.NewButtonWithIcon(locales.Translate("common.button.discard"), theme.CancelIcon(), func() {
+		dlg.Hide()
+		if onDiscard != nil {
+			onDiscard()
+		}
+	})
+	discardBtn.Importance = widget.DangerImportance
+
+	applyBtn := widget.NewButtonWithIcon(locales.Translate("common.tagreview.write"), theme.ConfirmIcon(), func() {
+		dlg.Hide()
+		if onApply != nil {
+			onApply()
+		}
+	})
+	applyBtn.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		summaryLabel,
+		container.NewHBox(layout.NewSpacer(), discardBtn, applyBtn),
+		nil,
+		nil,
+		table,
+	)
+
+	dlg = dialog.NewCustomWithoutButtons(locales.Translate("common.tagreview.header"), content, window)
+	dlg.Resize(fyne.NewSize(950, 600))
+	dlg.Show()
+}
+
+// lessReportValue orders two cells of a report table. Numbers (e.g. track IDs) are compared by value,
+// so "9" sorts before "10"; other values are compared as text ignoring case.
+func lessReportValue(a, b string) bool {
+	numberA, errA := strconv.ParseFloat(a, 64)
+	numberB, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return numberA < numberB
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// ShowFileReportDialog displays the per-file report of a run in a table. Clicking a column header sorts
+// the table by the column, clicking it again reverses the order. The user can export the report to a CSV or JSON file.
+//
+// Parameters:
+//   - window: The parent window for the dialog
+//   - reports: The file reports of the run
+func ShowFileReportDialog(window fyne.Window, reports []FileReport) {
+	headers := []string{
+		locales.Translate("common.report.path"),
+		locales.Translate("common.report.trackid"),
+		locales.Translate("common.report.result"),
+		locales.Translate("common.report.updated"),
+		locales.Translate("common.report.notupdated"),
+		locales.Translate("common.report.reason"),
+	}
+	lines := make([][]string, 0, len(reports))
+	for _, report := range reports {
+		columns := FileReportColumns(report)
+		columns[2] = locales.Translate("common.report.result." + report.Result)
+		lines = append(lines, columns)
+	}
+
+	sortColumn, ascending := -1, true
+	var table *widget.Table
+	table = widget.NewTable(
+		func() (int, int) {
+			return len(lines), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, object fyne.CanvasObject) {
+			object.(*widget.Label).SetText(lines[id.Row][id.Col])
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		button := widget.NewButton("", nil)
+		button.Importance = widget.LowImportance
+		button.Alignment = widget.ButtonAlignLeading
+		return button
+	}
+	table.UpdateHeader = func(id widget.TableCellID, object fyne.CanvasObject) {
+		if id.Col < 0 {
+			return
+		}
+		button := object.(*widget.Button)
+		switch {
+		case id.Col != sortColumn:
+			button.SetIcon(nil)
+		case ascending:
+			button.SetIcon(theme.MenuDropUpIcon())
+		default:
+			button.SetIcon(theme.MenuDropDownIcon())
+		}
+		button.SetText(headers[id.Col])
+		button.OnTapped = func() {
+			if sortColumn == id.Col {
+				ascending = !ascending
+			} else {
+				sortColumn, ascending = id.Col, true
+			}
+			sort.SliceStable(lines, func(i, j int) bool {
+				if ascending {
+					return lessReportValue(lines[i][sortColumn], lines[j][sortColumn])
+				}
+				return lessReportValue(lines[j][sortColumn], lines[i][sortColumn])
+			})
+			table.Refresh()
+		}
+	}
+	for col, width := range []float32{320, 90, 110, 170, 170, 220} {
+		table.SetColumnWidth(col, width)
+	}
+
+	counts := make(map[string]int)
+	for _, report := range reports {
+		counts[report.Result]++
+	}
+	summaryLabel := widget.NewLabel(fmt.Sprintf(locales.Translate("common.report.summary"), len(reports),
+		counts[FileResultUpdated], counts[FileResultNoChange],
+		counts[FileResultSkippedZero]+counts[FileResultMetadataError]+counts[FileResultDbMiss]+counts[FileResultUpdateError]))
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	var dlg *dialog.CustomDialog
+
+	exportBtn := widget.NewButtonWithIcon(locales.Translate("common.button.export"), theme.DocumentSaveIcon(), func() {
+		path, err := nativedialog.File().
+			Filter(locales.Translate("common.changeset.filtercsv"), "csv").
+			Filter(locales.Translate("common.changeset.filterjson"), "json").
+			Title(locales.Translate("common.report.exporttitle")).
+			Save()
+		if err != nil || path == "" {
+			return
+		}
+		if filepath.Ext(path) == "" {
+			path += ".csv"
+		}
+		if err := ExportFileReports(path, reports); err != nil {
+			ShowStandardError(window, err, &ErrorContext{
+				Module:      "Report",
+				Operation:   "Export Report",
+				Severity:    SeverityWarning,
+				Recoverable: true,
+			})
+		}
+	})
+
+	closeBtn := widget.NewButtonWithIcon(locales.Translate("common.button.close"), theme.CancelIcon(), func() {
+		dlg.Hide()
+	})
+
+	content := container.NewBorder(
+		summaryLabel,
+		container.NewHBox(exportBtn, layout.NewSpacer(), closeBtn),
+		nil,
+		nil,
+		table,
+	)
+
+	dlg = dialog.NewCustomWithoutButtons(locales.Translate("common.report.header"), content, window)
+	dlg.Resize(fyne.NewSize(1100, 600))
+	dlg.Show()
+}
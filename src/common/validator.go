@@ -18,22 +18,30 @@ import (
 
 // Validator handles validation of module inputs and database operations.
 type Validator struct {
-	module       Module         // The module being validated
-	configMgr    *ConfigManager // For configuration management
-	dbMgr        *DBManager     // For database operations
-	errorHandler *ErrorHandler  // For error handling
+	module        Module         // The module being validated
+	configMgr     *ConfigManager // For configuration management
+	dbMgr         *DBManager     // For database operations
+	errorHandler  *ErrorHandler  // For error handling
+	processLister ProcessLister  // For detecting a running rekordbox, nil skips the process check
 }
 
 // NewValidator creates a new instance of Validator.
 func NewValidator(module Module, configMgr *ConfigManager, dbMgr *DBManager, errorHandler *ErrorHandler) *Validator {
 	return &Validator{
-		module:       module,
-		configMgr:    configMgr,
-		dbMgr:        dbMgr,
-		errorHandler: errorHandler,
+		module:        module,
+		configMgr:     configMgr,
+		dbMgr:         dbMgr,
+		errorHandler:  errorHandler,
+		processLister: DefaultProcessLister,
 	}
 }
 
+// SetProcessLister replaces the function used to detect a running rekordbox.
+// Passing nil disables the process check, the lock and journal file checks still run.
+func (v *Validator) SetProcessLister(lister ProcessLister) {
+	v.processLister = lister
+}
+
 // Validate performs all necessary validations and returns an error if any validation fails.
 // The action parameter specifies which validation rules should be applied based on
 // the action being performed (e.g., "standard", "custom", etc.). If a field has
@@ -99,7 +107,7 @@ func (v *Validator) Validate(action string) error {
 			// Parse extensions if present
 			var extensions []string
 			if e, ok := fields["extensions"]; ok {
-				extensions = parseExtensionsCSV(e.Value)
+				extensions = ParseExtensionsCSV(e.Value)
 			}
 
 			if !IsEmptyString(sourceFolder) {
@@ -330,6 +338,16 @@ func (v *Validator) validateDatabase() error {
 		return err
 	}
 
+	// Validate that rekordbox or another program does not hold the database
+	if err := v.validateDatabaseNotInUse(); err != nil {
+		return err
+	}
+
+	// Validate database schema, writing to an unknown schema could corrupt the library
+	if err := v.validateDatabaseSchema(); err != nil {
+		return err
+	}
+
 	// Note: We only run the preflight DB connection test when the module does NOT require
 	// immediate access (NeedsImmediateAccess == false). Modules that read from the DB right
 	// after the GUI opens skip this pre-test; the first real DB call handles a lazy connect.
@@ -393,6 +411,40 @@ func (v *Validator) validateDatabaseAccess() error {
 	return nil
 }
 
+// validateDatabaseNotInUse checks that rekordbox is not running and no other program holds the database.
+// Changes written while rekordbox is running are overwritten by rekordbox, a lock held by another
+// program would make the write session fail mid-run.
+func (v *Validator) validateDatabaseNotInUse() error {
+	if err := v.dbMgr.CheckNotInUse(PreflightLockTimeout, v.processLister); err != nil {
+		context := &ErrorContext{
+			Module:      v.module.GetName(),
+			Operation:   "ValidateDatabaseNotInUse",
+			Severity:    SeverityCritical,
+			Recoverable: false,
+		}
+		v.errorHandler.ShowStandardError(err, context)
+		return err
+	}
+
+	return nil
+}
+
+// validateDatabaseSchema checks that the database version and structure match a known schema profile.
+func (v *Validator) validateDatabaseSchema() error {
+	if _, err := v.dbMgr.CheckWriteCompatibility(); err != nil {
+		context := &ErrorContext{
+			Module:      v.module.GetName(),
+			Operation:   "ValidateDatabaseSchema",
+			Severity:    SeverityCritical,
+			Recoverable: false,
+		}
+		v.errorHandler.ShowStandardError(err, context)
+		return err
+	}
+
+	return nil
+}
+
 // validateDatabaseConnection tests database connection.
 func (v *Validator) validateDatabaseConnection() error {
 	if err := v.dbMgr.Connect(); err != nil {
@@ -465,9 +517,9 @@ func extractFieldConfigs(config interface{}) (map[string]FieldCfg, error) {
 	return result, nil
 }
 
-// parseExtensionsCSV parses CSV/space/semicolon/pipe-separated extensions into normalized dot-prefixed, lowercased list.
+// ParseExtensionsCSV parses CSV/space/semicolon/pipe-separated extensions into normalized dot-prefixed, lowercased list.
 // Empty or whitespace-only input yields nil (meaning: no filter configured).
-func parseExtensionsCSV(value string) []string {
+func ParseExtensionsCSV(value string) []string {
 	value = strings.TrimSpace(value)
 	if value == "" {
 		return nil
@@ -524,8 +576,10 @@ func (v *Validator) GetSkippedDirs() []string {
 
 // backupDatabase creates a backup of the database.
 // It uses DBManager to create a backup of the current database file.
-// The backup is created in the same directory as the original database
-// with a timestamp suffix.
+// The backup is created in the configured backup directory (the database
+// directory by default) with a timestamp suffix, and old backups are pruned
+// according to the retention policy from the global configuration.
+// Only after a successful backup the database manager may upgrade to a writable connection.
 // Returns error if backup creation fails.
 func (v *Validator) backupDatabase() error {
 	context := &ErrorContext{
@@ -535,10 +589,13 @@ func (v *Validator) backupDatabase() error {
 		Recoverable: false,
 	}
 
+	// Always apply the current policy, settings may have changed since the manager was created
+	v.dbMgr.SetBackupSettings(v.configMgr.GetBackupSettings())
 	if err := v.dbMgr.BackupDatabase(); err != nil {
 		v.errorHandler.ShowStandardError(err, context)
 		return err
 	}
+	v.dbMgr.EnableWrites()
 
 	return nil
 }
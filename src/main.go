@@ -8,6 +8,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"runtime/debug"
 
@@ -31,6 +32,7 @@ type RekordboxTools struct {
 	mainWindow      fyne.Window
 	configMgr       *common.ConfigManager
 	dbManager       *common.DBManager
+	journal         *common.ChangeJournal
 	modules         []*moduleInfo
 	logger          *common.Logger
 	errorHandler    *common.ErrorHandler
@@ -92,6 +94,8 @@ func NewRekordboxTools() *RekordboxTools {
 			logger.Error("%s", rt.configInitError.Error())
 		} else {
 			rt.configMgr = configMgr
+			// The change journal lives next to the configuration file
+			rt.journal = common.NewChangeJournal(filepath.Join(filepath.Dir(configPath), common.FileNameJournal), logger)
 			// Flush any early logs captured during initialization (after ConfigManager is initialized)
 			common.FlushEarlyLogs(logger)
 			logger.Info("Configuration initialized successfully at: %s", configPath)
@@ -162,6 +166,11 @@ func (rt *RekordboxTools) Run() {
 			rt.logger.Error("%s: %v", locales.Translate("common.err.dbclosing"), err)
 		}
 	}
+	if rt.journal != nil {
+		if err := rt.journal.Close(); err != nil {
+			rt.logger.Error("%s: %v", locales.Translate("common.err.journalclose"), err)
+		}
+	}
 }
 
 // initModules prepares module definitions without initializing them.
@@ -177,6 +186,13 @@ func (rt *RekordboxTools) initModules() {
 				return m
 			},
 		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewTrackImporterModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, false)
+				return m
+			},
+		},
 		{
 			createFn: func() common.Module {
 				m := modules.NewDataDuplicatorModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
@@ -198,6 +214,41 @@ func (rt *RekordboxTools) initModules() {
 				return m
 			},
 		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewDbAuditModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, false)
+				return m
+			},
+		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewPlaylistMirrorModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, true)
+				return m
+			},
+		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewRekordboxXmlModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, true)
+				return m
+			},
+		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewArtistMergeModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, false)
+				return m
+			},
+		},
+		{
+			createFn: func() common.Module {
+				m := modules.NewTagWriterModule(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+				m.SetDatabaseRequirements(true, true)
+				return m
+			},
+		},
 		{
 			createFn: func() common.Module {
 				m := modules.NewFormatConverterModule(rt.mainWindow, rt.configMgr, rt.errorHandler)
@@ -286,17 +337,23 @@ func (rt *RekordboxTools) createMainContent() fyne.CanvasObject {
 	return content
 }
 
-// createMenuBar creates a simple horizontal bar with Settings and Help buttons.
-// These buttons open modal windows for application settings and help information.
+// createMenuBar creates a simple horizontal bar with Settings, Backups, History and Help buttons.
+// These buttons open modal windows for application settings, database backups, the change history and help information.
 func (rt *RekordboxTools) createMenuBar() fyne.CanvasObject {
 	settingsButton := widget.NewButton(locales.Translate("settings.win.title"), func() {
 		ui.ShowSettingsWindow(rt.mainWindow, rt.configMgr, rt.errorHandler)
 	})
+	backupsButton := widget.NewButton(locales.Translate("backups.win.title"), func() {
+		ui.ShowBackupsWindow(rt.mainWindow, rt.configMgr, rt.getDBManager(), rt.errorHandler)
+	})
+	historyButton := widget.NewButton(locales.Translate("history.win.title"), func() {
+		ui.ShowHistoryWindow(rt.mainWindow, rt.configMgr, rt.journal, rt.getDBManager(), rt.errorHandler)
+	})
 	helpButton := widget.NewButton(locales.Translate("main.menu.help"), func() {
 		ui.ShowHelpWindow(rt.mainWindow)
 	})
 
-	return container.NewHBox(settingsButton, helpButton)
+	return container.NewHBox(settingsButton, backupsButton, historyButton, helpButton)
 }
 
 // getDBManager returns the dbManager instance, initializing it if necessary.
@@ -316,6 +373,8 @@ func (rt *RekordboxTools) getDBManager() *common.DBManager {
 		if err != nil {
 			rt.logger.Error("DBManager: Failed to initialize for path '%s': %v", dbPath, err)
 		} else {
+			dbManagerInstance.SetBackupSettings(rt.configMgr.GetBackupSettings())
+			dbManagerInstance.SetJournal(rt.journal)
 			rt.dbManager = dbManagerInstance
 			rt.logger.Info("DBManager: Initialized for path: %s", dbPath)
 		}
@@ -0,0 +1,398 @@
+// modules/artistmerge.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module finds near-duplicate artists created by years of inconsistent tags (trailing spaces,
+// "feat." variants, diacritics, "&" vs "and") and merges each group into one canonical artist.
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// artistMergeSimilarities lists the selectable minimum similarities in percent.
+var artistMergeSimilarities = []string{"80", "85", "90", "95", "100"}
+
+// artistMergeRow is a duplicate group shown in the UI with its controls.
+type artistMergeRow struct {
+	group      common.ArtistDuplicateGroup
+	mergeCheck *widget.Check
+	nameEntry  *widget.SelectEntry
+}
+
+// ArtistMergeModule finds groups of likely duplicate artists and merges them.
+// The search only reads the database; the merge creates a backup first
+// and shows all changes for review before anything is written.
+type ArtistMergeModule struct {
+	// ModuleBase provides common module functionality like error handling and UI components
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// similaritySelect selects the minimum similarity of grouped names
+	similaritySelect *widget.Select
+	// groupsBox lists the found duplicate groups
+	groupsBox *fyne.Container
+	// rows holds the found duplicate groups with their controls
+	rows []*artistMergeRow
+	// searchBtn runs the read-only search for duplicates
+	searchBtn *widget.Button
+	// submitBtn merges the selected groups
+	submitBtn *widget.Button
+}
+
+// NewArtistMergeModule creates a new instance of ArtistMergeModule.
+// It initializes the module with the provided window, configuration manager, database manager,
+// and error handler, sets up the UI components, and loads any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized ArtistMergeModule instance
+func NewArtistMergeModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *ArtistMergeModule {
+	m := &ArtistMergeModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *ArtistMergeModule) GetName() string {
+	return locales.Translate("artistmerge.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *ArtistMergeModule) GetConfigName() string {
+	return common.ModuleKeyArtistMerge
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *ArtistMergeModule) GetIcon() fyne.Resource {
+	return theme.AccountIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// containing the similarity selection, the list of duplicate groups and the search and merge buttons.
+func (m *ArtistMergeModule) GetModuleContent() fyne.CanvasObject {
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: locales.Translate("artistmerge.label.similarity"), Widget: m.similaritySelect},
+		},
+	}
+
+	// The groups list scrolls, a large library can have hundreds of groups
+	groupsScroll := container.NewVScroll(m.groupsBox)
+	groupsScroll.SetMinSize(fyne.NewSize(0, 300))
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("artistmerge.label.info")),
+		widget.NewSeparator(),
+		form,
+		widget.NewLabel(locales.Translate("artistmerge.label.groups")),
+		groupsScroll,
+	)
+
+	// Add buttons with right alignment
+	buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.searchBtn, m.submitBtn)
+	moduleContent.Add(buttonBox)
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content.
+// If no database path is set, the controls are disabled.
+func (m *ArtistMergeModule) GetContent() fyne.CanvasObject {
+	if m.dbMgr == nil || m.dbMgr.GetDatabasePath() == "" {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "PathToDatabaseCheck",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		common.DisableModuleControls(m.searchBtn, m.submitBtn)
+	}
+
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *ArtistMergeModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyArtistMerge, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to ArtistMerge specific config
+	if cfg, ok := config.(common.ArtistMergeCfg); ok {
+		m.similaritySelect.SetSelected(similarityOption(cfg.Similarity.Value))
+	}
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *ArtistMergeModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultArtistMergeCfg()
+
+	// Update only the values from current UI state
+	cfg.Similarity.Value = strings.TrimSuffix(m.similaritySelect.Selected, " %")
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyArtistMerge, m.GetConfigName(), cfg)
+}
+
+// similarityOption returns the option of the similarity selection for a stored percentage.
+func similarityOption(percent string) string {
+	return percent + " %"
+}
+
+// initializeUI sets up the user interface components.
+// It creates the similarity selection, the empty groups list and the search and merge buttons.
+func (m *ArtistMergeModule) initializeUI() {
+	options := make([]string, 0, len(artistMergeSimilarities))
+	for _, percent := range artistMergeSimilarities {
+		options = append(options, similarityOption(percent))
+	}
+	m.similaritySelect = widget.NewSelect(options, nil)
+	m.similaritySelect.OnChanged = m.CreateSelectionChangeHandler(func() { m.SaveCfg() })
+
+	m.groupsBox = container.NewVBox()
+
+	m.searchBtn = widget.NewButtonWithIcon(locales.Translate("artistmerge.button.search"), theme.SearchIcon(), func() {
+		go m.runSearch()
+	})
+
+	m.submitBtn = common.CreateSubmitButton(locales.Translate("artistmerge.button.merge"), func() {
+		go m.Start()
+	})
+}
+
+// selectedSimilarity returns the selected minimum similarity between 0 and 1.
+func (m *ArtistMergeModule) selectedSimilarity() float64 {
+	percent, err := strconv.Atoi(strings.TrimSuffix(m.similaritySelect.Selected, " %"))
+	if err != nil {
+		return common.DefaultArtistSimilarity
+	}
+	return float64(percent) / 100
+}
+
+// runSearch looks for duplicate artists and lists the found groups without changing anything.
+// No backup is created, because the search only reads the database.
+func (m *ArtistMergeModule) runSearch() {
+	m.ClearStatusMessages()
+	m.ShowProgressDialog(locales.Translate("artistmerge.dialog.search"))
+	m.StartProcessing(locales.Translate("artistmerge.status.searching"))
+
+	defer m.dbMgr.Finalize()
+
+	groups, err := common.FindArtistDuplicates(m.dbMgr, m.selectedSimilarity(), m.IsCancelled)
+	if errors.Is(err, common.ErrCancelled) {
+		m.HandleProcessCancellation("artistmerge.status.stopped")
+		return
+	}
+	if err != nil {
+		m.showError("Artist Search", err)
+		return
+	}
+
+	m.showGroups(groups)
+
+	message := fmt.Sprintf(locales.Translate("artistmerge.status.found"), len(groups))
+	m.CompleteProcessing(message)
+	if len(groups) > 0 {
+		m.AddWarningMessage(message)
+	} else {
+		m.AddInfoMessage(message)
+	}
+	m.CompleteProgressDialog()
+}
+
+// showGroups replaces the listed duplicate groups. Each group is selected for merging,
+// its canonical name is preset to the most used artist and can be picked from the group or typed.
+func (m *ArtistMergeModule) showGroups(groups []common.ArtistDuplicateGroup) {
+	m.rows = nil
+	m.groupsBox.RemoveAll()
+
+	for _, group := range groups {
+		names := make([]string, 0, len(group.Artists))
+		members := make([]string, 0, len(group.Artists))
+		for _, artist := range group.Artists {
+			names = append(names, artist.Name)
+			members = append(members, fmt.Sprintf(locales.Translate("artistmerge.label.member"), artist.Name, artist.Uses))
+		}
+
+		row := &artistMergeRow{
+			group:      group,
+			mergeCheck: widget.NewCheck("", nil),
+			nameEntry:  widget.NewSelectEntry(names),
+		}
+		row.mergeCheck.SetChecked(true)
+		row.nameEntry.SetText(group.Canonical().Name)
+		m.rows = append(m.rows, row)
+
+		membersLabel := widget.NewLabel(strings.Join(members, ", "))
+		membersLabel.Wrapping = fyne.TextWrapWord
+		m.groupsBox.Add(container.NewBorder(nil, nil, row.mergeCheck, nil,
+			container.NewGridWithColumns(2, row.nameEntry, membersLabel)))
+	}
+	m.groupsBox.Refresh()
+}
+
+// Start performs the necessary steps before starting the merge.
+// It validates the inputs, which includes creating a database backup,
+// displays a progress dialog and prepares the merge in a goroutine.
+func (m *ArtistMergeModule) Start() {
+	selected := 0
+	for _, row := range m.rows {
+		if row.mergeCheck.Checked && strings.TrimSpace(row.nameEntry.Text) != "" {
+			selected++
+		}
+	}
+	if selected == 0 {
+		context := &common.ErrorContext{
+			Module:      m.GetName(),
+			Operation:   "Artist Merge",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("artistmerge.err.nothing")), context)
+		return
+	}
+
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	// Show the progress dialog
+	m.ShowProgressDialog(locales.Translate("artistmerge.dialog.merge"))
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "Artist Merge",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processMerge()
+	}()
+}
+
+// processMerge prepares the merge of all selected groups into their canonical artists.
+// If the canonical name matches an artist of the group, that artist is kept; otherwise the most used
+// artist is kept and renamed. The changes are shown for review and written in a single write session on approval.
+func (m *ArtistMergeModule) processMerge() {
+	defer m.dbMgr.Finalize()
+
+	m.StartProcessing(locales.Translate("artistmerge.status.preparing"))
+
+	// Collect all changes first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
+	repointed := 0
+	for i, row := range m.rows {
+		if m.IsCancelled() {
+			m.HandleProcessCancellation("artistmerge.status.stopped")
+			common.UpdateButtonToCompleted(m.submitBtn)
+			return
+		}
+		name := strings.TrimSpace(row.nameEntry.Text)
+		if !row.mergeCheck.Checked || name == "" {
+			continue
+		}
+
+		canonical := row.group.Canonical()
+		for _, artist := range row.group.Artists {
+			if strings.EqualFold(artist.Name, name) {
+				canonical = artist
+				break
+			}
+		}
+		rename := ""
+		if canonical.Name != name {
+			rename = name
+		}
+
+		count, err := common.PrepareArtistMerge(cs, canonical.ID, rename, row.group.Artists)
+		if err != nil {
+			m.showError("Artist Merge", err)
+			return
+		}
+		repointed += count
+		m.UpdateProcessingProgress(i+1, len(m.rows), locales.Translate("artistmerge.status.preparing"))
+	}
+
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("artistmerge.dialog.merge"), cs, func(applied int) {
+		message := fmt.Sprintf(locales.Translate("artistmerge.status.merged"), applied, repointed)
+		m.CompleteProcessing(message)
+		m.AddInfoMessage(message)
+
+		// The listed groups no longer match the database
+		m.showGroups(nil)
+
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
+
+// showError closes the progress dialog and reports an error of the given operation.
+func (m *ArtistMergeModule) showError(operation string, err error) {
+	m.CloseProgressDialog()
+	context := &common.ErrorContext{
+		Module:      m.GetName(),
+		Operation:   operation,
+		Severity:    common.SeverityCritical,
+		Recoverable: false,
+	}
+	m.ErrorHandler.ShowStandardError(err, context)
+	m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+}
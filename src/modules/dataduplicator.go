@@ -14,7 +14,6 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
@@ -218,24 +217,11 @@ func (m *DataDuplicatorModule) LoadCfg() {
 
 		// Load playlist selections if playlists are loaded
 		if len(m.playlists) > 0 {
-			// Find and set source playlist
-			for i, playlist := range m.playlists {
-				if playlist.ID == m.sourcePlaylistID {
-					if i < len(m.sourcePlaylistSelect.Options) {
-						m.sourcePlaylistSelect.SetSelected(m.sourcePlaylistSelect.Options[i])
-					}
-					break
-				}
+			if option := common.PlaylistOptionByID(m.playlists, m.sourcePlaylistSelect, m.sourcePlaylistID); option != "" {
+				m.sourcePlaylistSelect.SetSelected(option)
 			}
-
-			// Find and set target playlist
-			for i, playlist := range m.playlists {
-				if playlist.ID == m.targetPlaylistID {
-					if i < len(m.targetPlaylistSelect.Options) {
-						m.targetPlaylistSelect.SetSelected(m.targetPlaylistSelect.Options[i])
-					}
-					break
-				}
+			if option := common.PlaylistOptionByID(m.playlists, m.targetPlaylistSelect, m.targetPlaylistID); option != "" {
+				m.targetPlaylistSelect.SetSelected(option)
 			}
 		}
 	}
@@ -268,22 +254,16 @@ func (m *DataDuplicatorModule) SaveCfg() {
 
 	// Get playlist IDs if needed
 	sourcePlaylistID := ""
-	if sourceType == SourceTypePlaylist && m.sourcePlaylistSelect.Selected != "" {
-		for _, playlist := range m.playlists {
-			if playlist.Path == m.sourcePlaylistSelect.Selected {
-				sourcePlaylistID = playlist.ID
-				break
-			}
+	if sourceType == SourceTypePlaylist {
+		if playlist, ok := common.SelectedPlaylist(m.playlists, m.sourcePlaylistSelect); ok {
+			sourcePlaylistID = playlist.ID
 		}
 	}
 
 	targetPlaylistID := ""
-	if targetType == SourceTypePlaylist && m.targetPlaylistSelect.Selected != "" {
-		for _, playlist := range m.playlists {
-			if playlist.Path == m.targetPlaylistSelect.Selected {
-				targetPlaylistID = playlist.ID
-				break
-			}
+	if targetType == SourceTypePlaylist {
+		if playlist, ok := common.SelectedPlaylist(m.playlists, m.targetPlaylistSelect); ok {
+			targetPlaylistID = playlist.ID
 		}
 	}
 
@@ -373,12 +353,14 @@ func (m *DataDuplicatorModule) initializeUI() {
 	// Initialize source playlist selector
 	m.sourcePlaylistSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
 	m.sourcePlaylistSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
-		// Find the playlist ID for the selected name
-		for _, p := range m.playlists {
-			if p.Path == m.sourcePlaylistSelect.Selected {
-				m.sourcePlaylistID = p.ID
-				break
-			}
+		// Folders cannot be used as a source of tracks
+		if !common.GuardPlaylistSelection(m.playlists, m.sourcePlaylistSelect, m.sourcePlaylistID) {
+			return
+		}
+		// Find the playlist ID for the selected option
+		m.sourcePlaylistID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.sourcePlaylistSelect); ok {
+			m.sourcePlaylistID = p.ID
 		}
 		m.SaveCfg()
 	})
@@ -386,12 +368,14 @@ func (m *DataDuplicatorModule) initializeUI() {
 	// Initialize target playlist selector
 	m.targetPlaylistSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
 	m.targetPlaylistSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
-		// Find the playlist ID for the selected name
-		for _, p := range m.playlists {
-			if p.Path == m.targetPlaylistSelect.Selected {
-				m.targetPlaylistID = p.ID
-				break
-			}
+		// Folders cannot be used as a target of tracks
+		if !common.GuardPlaylistSelection(m.playlists, m.targetPlaylistSelect, m.targetPlaylistID) {
+			return
+		}
+		// Find the playlist ID for the selected option
+		m.targetPlaylistID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.targetPlaylistSelect); ok {
+			m.targetPlaylistID = p.ID
 		}
 		m.SaveCfg()
 	})
@@ -403,93 +387,86 @@ func (m *DataDuplicatorModule) initializeUI() {
 	)
 }
 
-// copyHotCues copies hot cues from the source track to the target track.
+// cueIdentityColumns lists djmdCue columns that identify the row or its track and the sync state of the row.
+// They are never copied from the source cue.
+var cueIdentityColumns = map[string]bool{
+	"ID": true, "ContentID": true, "ContentUUID": true, "UUID": true,
+	"rb_data_status": true, "rb_local_data_status": true, "rb_local_deleted": true, "rb_local_synced": true,
+}
+
+// copyHotCues records the copying of hot cues from the source track to the target track into the changeset.
 // It retrieves hot cues from the source track using the database manager,
-// and then applies them to the target track. The function handles both
-// the retrieval and update of hot cue data.
+// and then prepares them for the target track. Nothing is written until the changeset is applied.
 //
 // The method performs the following steps:
 // 1. Retrieves all hot cues from the source track
-// 2. For each hot cue, deletes any existing hot cue with the same Kind in the target track
-// 3. Generates a new ID for each hot cue
-// 4. Inserts the hot cue into the target track with updated timestamps
+// 2. Records the deletion of existing cues of the target track with the same Kind values
+// 3. Records the insertion of each hot cue into the target track with a new ID and UUID
 //
 // Parameters:
+//   - cs: The changeset collecting the changes
 //   - sourceID: The ID of the source track to copy hot cues from
 //   - targetID: The ID of the target track to copy hot cues to
+//   - label: Human readable name of the target track shown in the change preview
 //
 // Returns:
 //   - error: Returns nil if successful, otherwise returns an error with a localized message
-//     describing what went wrong (e.g., database query errors, update errors)
-func (m *DataDuplicatorModule) copyHotCues(sourceID, targetID string) error {
+//     describing what went wrong (e.g., database query errors)
+func (m *DataDuplicatorModule) copyHotCues(cs *common.Changeset, sourceID, targetID, label string) error {
 	hotCues, err := m.dbMgr.GetTrackHotCues(sourceID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.querycues"), err)
 	}
 
+	// Collect Kind values of the source cues, existing target cues of these kinds are replaced
+	kinds := make(map[string]bool)
+	for _, hotCue := range hotCues {
+		if kind, ok := hotCue["Kind"]; ok {
+			kinds[common.FormatChangeValue(kind)] = true
+		}
+	}
+
+	targetCues, err := m.dbMgr.GetTrackHotCues(targetID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.querycues"), err)
+	}
+	for _, targetCue := range targetCues {
+		if kinds[common.FormatChangeValue(targetCue["Kind"])] {
+			cs.Delete(common.SQLTableDJMDCue, common.FormatChangeValue(targetCue["ID"]), label)
+		}
+	}
+
+	// Cues reference their track also by its UUID
+	targetUUID, err := cs.IDs().ContentUUID(targetID)
+	if err != nil {
+		return err
+	}
+
 	// Counter for tracking the number of hot cues
 	hotCueCount := 0
 
 	// Process each hot cue
 	for _, hotCue := range hotCues {
-		// Increase the hot cue counter
-		hotCueCount++
-
-		// Get the Kind value from the hot cue
-		kind, ok := hotCue["Kind"]
-		if !ok {
+		if _, ok := hotCue["Kind"]; !ok {
 			continue
 		}
 
-		// Delete existing hot cues with the same Kind value in the target track
-		err = m.dbMgr.Execute(`DELETE FROM djmdCue WHERE ContentID = ? AND Kind = ?`, targetID, kind)
-		if err != nil {
-			return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.deletecue"), err)
-		}
+		// Increase the hot cue counter
+		hotCueCount++
 
-		// Generate a new ID for the hot cue in the target track
-		var maxID int64
-		err = m.dbMgr.QueryRow("SELECT COALESCE(MAX(CAST(ID AS INTEGER)), 0) FROM djmdCue").Scan(&maxID)
-		if err != nil {
-			return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.maxidcheck"), err)
+		fields := []common.FieldChange{
+			{Column: "ContentID", New: targetID},
+			{Column: "ContentUUID", New: targetUUID},
 		}
-		maxID++
-		newID := fmt.Sprintf("%d", maxID)
-
-		// Get current timestamp for created_at
-		currentTime := time.Now().UTC().Format("2006-01-02 15:04:05.000 +00:00")
-
-		// SQL query preparation for inserting hot cue
-		query := `
-			INSERT INTO djmdCue (
-				ID, ContentID, InMsec, InFrame, InMpegFrame, InMpegAbs, OutMsec, OutFrame, OutMpegFrame, 
-				OutMpegAbs, Kind, Color, ColorTableIndex, ActiveLoop, Comment, BeatLoopSize, CueMicrosec, 
-				InPointSeekInfo, OutPointSeekInfo, ContentUUID, UUID, rb_data_status, rb_local_data_status, 
-				rb_local_deleted, rb_local_synced, created_at, updated_at
-			) VALUES (
-				?, ?, ?, ?, ?, ?, ?, ?, ?, 
-				?, ?, ?, ?, ?, ?, ?, ?, 
-				?, ?, ?, ?, ?, ?, ?, ?, ?, ?
-			)
-		`
-
-		// Parameters for the insert preparation
-		params := []interface{}{
-			newID, targetID,
-			hotCue["InMsec"], hotCue["InFrame"], hotCue["InMpegFrame"], hotCue["InMpegAbs"],
-			hotCue["OutMsec"], hotCue["OutFrame"], hotCue["OutMpegFrame"], hotCue["OutMpegAbs"],
-			hotCue["Kind"], hotCue["Color"], hotCue["ColorTableIndex"], hotCue["ActiveLoop"],
-			hotCue["Comment"], hotCue["BeatLoopSize"], hotCue["CueMicrosec"],
-			hotCue["InPointSeekInfo"], hotCue["OutPointSeekInfo"], hotCue["ContentUUID"],
-			hotCue["UUID"], hotCue["rb_data_status"], hotCue["rb_local_data_status"],
-			hotCue["rb_local_deleted"], hotCue["rb_local_synced"],
-			currentTime, currentTime,
+		for _, column := range common.CueColumns {
+			if cueIdentityColumns[column] {
+				continue
+			}
+			fields = append(fields, common.FieldChange{Column: column, New: hotCue[column]})
 		}
-
-		// Execute the insert
-		err = m.dbMgr.Execute(query, params...)
-		if err != nil {
-			return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.cueinsert"), err)
+		// The new row gets its own ID, UUID and cleared sync columns, the changeset adds USN and timestamps
+		if _, err := cs.InsertNew(common.SQLTableDJMDCue, label, fields...); err != nil {
+			return err
 		}
 	}
 
@@ -497,16 +474,18 @@ func (m *DataDuplicatorModule) copyHotCues(sourceID, targetID string) error {
 	return nil
 }
 
-// copyTrackMetadata copies specific metadata fields from source track to target track.
+// copyTrackMetadata records the copying of specific metadata fields from source track to target track into the changeset.
 // Fields copied: StockDate, DateCreated, ColorID, DJPlayCount
 //
 // Parameters:
+//   - cs: The changeset collecting the changes
 //   - sourceID: The ID of the source track to copy metadata from
 //   - targetID: The ID of the target track to copy metadata to
+//   - label: Human readable name of the target track shown in the change preview
 //
 // Returns:
 //   - error: Returns nil if successful, otherwise returns an error with details about the failure
-func (m *DataDuplicatorModule) copyTrackMetadata(sourceID, targetID string) error {
+func (m *DataDuplicatorModule) copyTrackMetadata(cs *common.Changeset, sourceID, targetID, label string) error {
 	// Query to get source track metadata
 	query := `
 		SELECT StockDate, DateCreated, ColorID, DJPlayCount
@@ -530,22 +509,13 @@ func (m *DataDuplicatorModule) copyTrackMetadata(sourceID, targetID string) erro
 		return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.metadatascan"), err)
 	}
 
-	// Get current timestamp for updated_at
-	currentTime := time.Now().UTC().Format("2006-01-02 15:04:05.000 +00:00")
-
-	// Update target track with source track metadata
-	updateQuery := `
-		UPDATE djmdContent
-		SET StockDate = ?, DateCreated = ?, ColorID = ?, DJPlayCount = ?, updated_at = ?
-		WHERE ID = ?
-	`
-
-	err = m.dbMgr.Execute(updateQuery,
-		stockDate.ValueOrNil(),
-		dateCreated.ValueOrNil(),
-		colorID.ValueOrNil(),
-		djPlayCount.ValueOrNil(),
-		currentTime, targetID)
+	// Record the update of target track with source track metadata
+	_, err = cs.Update(common.SQLTableDJMDContent, targetID, label,
+		common.FieldChange{Column: "StockDate", New: stockDate.ValueOrNil()},
+		common.FieldChange{Column: "DateCreated", New: dateCreated.ValueOrNil()},
+		common.FieldChange{Column: "ColorID", New: colorID.ValueOrNil()},
+		common.FieldChange{Column: "DJPlayCount", New: djPlayCount.ValueOrNil()},
+	)
 	if err != nil {
 		return fmt.Errorf("%s: %w", locales.Translate("dataduplicator.err.metadataupdate"), err)
 	}
@@ -568,13 +538,8 @@ func (m *DataDuplicatorModule) getSourceTracks() ([]common.TrackItem, error) {
 	} else {
 		// Find playlist ID
 		var playlistID string
-
-		// First try to find the playlist by path
-		for _, p := range m.playlists {
-			if p.Path == m.sourcePlaylistSelect.Selected {
-				playlistID = p.ID
-				break
-			}
+		if p, ok := common.SelectedPlaylist(m.playlists, m.sourcePlaylistSelect); ok {
+			playlistID = p.ID
 		}
 
 		tracks, _ = m.dbMgr.GetTracksBasedOnPlaylist(playlistID)
@@ -612,13 +577,8 @@ func (m *DataDuplicatorModule) getTargetTracks(sourceTrack common.TrackItem) ([]
 	} else {
 		// Find playlist ID
 		var playlistID string
-
-		// First try to find the playlist by path
-		for _, p := range m.playlists {
-			if p.Path == m.targetPlaylistSelect.Selected {
-				playlistID = p.ID
-				break
-			}
+		if p, ok := common.SelectedPlaylist(m.playlists, m.targetPlaylistSelect); ok {
+			playlistID = p.ID
 		}
 
 		targetTracks, _ = m.dbMgr.GetTracksBasedOnPlaylist(playlistID)
@@ -680,39 +640,16 @@ func (m *DataDuplicatorModule) loadPlaylists() error {
 	// Store playlists for later use
 	m.playlists = playlists
 
-	// Create options list for selectors
-	options := make([]string, len(playlists))
-	for i, playlist := range playlists {
-		options[i] = playlist.Path // Use Path instead of Name to show hierarchy
-	}
+	// Create options list for selectors showing the playlist tree
+	options := common.PlaylistSelectOptions(playlists)
 
 	// Update selectors
 	m.sourcePlaylistSelect.Options = options
 	m.targetPlaylistSelect.Options = options
 
 	// Prepare selected values for source and target
-	var sourceSelectedValue string
-	var targetSelectedValue string
-
-	// Find source selected value
-	if m.sourcePlaylistID != "" {
-		for _, playlist := range m.playlists {
-			if playlist.ID == m.sourcePlaylistID {
-				sourceSelectedValue = playlist.Path
-				break
-			}
-		}
-	}
-
-	// Find target selected value
-	if m.targetPlaylistID != "" {
-		for _, playlist := range m.playlists {
-			if playlist.ID == m.targetPlaylistID {
-				targetSelectedValue = playlist.Path
-				break
-			}
-		}
-	}
+	sourceSelectedValue := common.PlaylistOptionByID(m.playlists, m.sourcePlaylistSelect, m.sourcePlaylistID)
+	targetSelectedValue := common.PlaylistOptionByID(m.playlists, m.targetPlaylistSelect, m.targetPlaylistID)
 
 	// Set active state for source and target playlist selects
 	common.SetPlaylistSelectState(m.sourcePlaylistSelect, true, sourceSelectedValue)
@@ -840,13 +777,15 @@ func (m *DataDuplicatorModule) Start() {
 // This method runs in a goroutine and handles the entire synchronization workflow:
 // 1. Gets source tracks based on selected source type
 // 2. For each source track, finds matching target tracks
-// 3. Copies hot cues and metadata from source to target tracks
+// 3. Collects hot cues and metadata to copy from source to target tracks into a changeset
 // 4. Updates progress and handles cancellation throughout the process
-// 5. Shows completion status when finished
+// 5. Shows the changes for review and writes them in a single write session on approval
+// 6. Shows completion status when finished
 //
 // The method includes panic recovery to ensure the progress dialog is always closed
 // even if an unexpected error occurs.
 func (m *DataDuplicatorModule) processUpdate() {
+	defer m.dbMgr.Finalize()
 	defer func() {
 		if r := recover(); r != nil {
 			m.CloseProgressDialog()
@@ -885,6 +824,9 @@ func (m *DataDuplicatorModule) processUpdate() {
 	// Update progress
 	m.AddInfoMessage(fmt.Sprintf(locales.Translate("dataduplicator.status.srctrackscount"), len(sourceTracks)))
 
+	// Collect all changes first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
+
 	// Track successful and skipped files
 	processedCount := 0
 	skippedCount := 0
@@ -934,7 +876,7 @@ func (m *DataDuplicatorModule) processUpdate() {
 			}
 
 			// Copy hot cues
-			err = m.copyHotCues(sourceTrack.ID, targetTrack.ID)
+			err = m.copyHotCues(cs, sourceTrack.ID, targetTrack.ID, targetTrack.FileName)
 			if err != nil {
 				context := &common.ErrorContext{
 					Module:      m.GetConfigName(),
@@ -949,7 +891,7 @@ func (m *DataDuplicatorModule) processUpdate() {
 			}
 
 			// Copy track metadata
-			err = m.copyTrackMetadata(sourceTrack.ID, targetTrack.ID)
+			err = m.copyTrackMetadata(cs, sourceTrack.ID, targetTrack.ID, targetTrack.FileName)
 			if err != nil {
 				context := &common.ErrorContext{
 					Module:      m.GetConfigName(),
@@ -963,19 +905,19 @@ func (m *DataDuplicatorModule) processUpdate() {
 				return
 			}
 			processedCount++
-
-			// Small delay to prevent database overload
-			time.Sleep(10 * time.Millisecond)
 		}
 	}
 
-	// Update progress and status
-	m.CompleteProcessing(fmt.Sprintf(locales.Translate("dataduplicator.status.completed"), processedCount, skippedCount))
-	m.AddInfoMessage(fmt.Sprintf(locales.Translate("dataduplicator.status.completed"), processedCount, skippedCount))
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("dataduplicator.dialog.header"), cs, func(applied int) {
+		// Update progress and status
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("dataduplicator.status.completed"), processedCount, skippedCount))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("dataduplicator.status.completed"), processedCount, skippedCount))
 
-	// Complete progress dialog and update button
-	m.CompleteProgressDialog()
+		// Complete progress dialog and update button
+		m.CompleteProgressDialog()
 
-	// Update submit button to show completion
-	common.UpdateButtonToCompleted(m.submitBtn)
+		// Update submit button to show completion
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
 }
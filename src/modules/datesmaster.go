@@ -488,15 +488,18 @@ func (m *DatesMasterModule) Start(mode string) {
 }
 
 // processStandardUpdate performs the standard date synchronization.
-// It updates the progress dialog, calls setStandardDates to perform the database update,
+// It updates the progress dialog, calls setStandardDates to prepare the database update, lets the user review it,
 // handles errors and cancellation, and updates the UI with results.
 // This method runs in a separate goroutine.
 func (m *DatesMasterModule) processStandardUpdate() {
+	// Release the connection and the write permission of the run, also when it ends early
+	defer m.dbMgr.Finalize()
 
 	// Execute standard date sync
 	m.StartProcessing(locales.Translate("common.status.updating"))
 	m.AddInfoMessage(locales.Translate("common.status.updating"))
-	updatedCount, err := m.setStandardDates()
+	cs := common.NewChangeset(m.dbMgr)
+	_, err := m.setStandardDates(cs)
 	if err != nil {
 		m.CloseProgressDialog()
 		context := &common.ErrorContext{
@@ -510,22 +513,30 @@ func (m *DatesMasterModule) processStandardUpdate() {
 		return
 	}
 
-	// Update progress with count
+	// Check if cancelled after database update
+	if m.IsCancelled() {
+		return
+	}
 
-	// Update progress and complete dialog with final count
-	m.CompleteProcessing(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
-	m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
-	m.CompleteProgressDialog()
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("datesmaster.dialog.header"), cs, func(updatedCount int) {
+		// Update progress and complete dialog with final count
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
+		m.CompleteProgressDialog()
 
-	// Update button to show completion
-	common.UpdateButtonToCompleted(m.standardUpdateBtn)
+		// Update button to show completion
+		common.UpdateButtonToCompleted(m.standardUpdateBtn)
+	})
 }
 
 // processCustomUpdate performs the custom date synchronization.
-// It collects custom date folders, calls setCustomDates to perform the database update,
+// It collects custom date folders, calls setCustomDates to prepare the database update, lets the user review it,
 // handles errors and cancellation, and updates the UI with results.
 // This method runs in a separate goroutine.
 func (m *DatesMasterModule) processCustomUpdate() {
+	// Release the connection and the write permission of the run, also when it ends early
+	defer m.dbMgr.Finalize()
 
 	// No need to parse custom date, it's already parsed in the validator
 	customDate, _ := time.Parse("2006-01-02", m.datePickerEntry.Text)
@@ -541,7 +552,8 @@ func (m *DatesMasterModule) processCustomUpdate() {
 	// Execute custom date sync
 	m.StartProcessing(locales.Translate("common.status.updating"))
 	m.AddInfoMessage(locales.Translate("common.status.updating"))
-	updatedCount, err := m.setCustomDates(customDateFolders, customDate)
+	cs := common.NewChangeset(m.dbMgr)
+	_, err := m.setCustomDates(cs, customDateFolders, customDate)
 	if err != nil {
 		m.CloseProgressDialog()
 		context := &common.ErrorContext{
@@ -560,29 +572,30 @@ func (m *DatesMasterModule) processCustomUpdate() {
 		return
 	}
 
-	// Update progress with count
-
-	// Update progress and complete dialog with final count
-	m.CompleteProcessing(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
-	m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
-	m.CompleteProgressDialog()
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("datesmaster.dialog.header"), cs, func(updatedCount int) {
+		// Update progress and complete dialog with final count
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.completed"), updatedCount))
+		m.CompleteProgressDialog()
 
-	// Update button to show completion
-	common.UpdateButtonToCompleted(m.customDateUpdateBtn)
+		// Update button to show completion
+		common.UpdateButtonToCompleted(m.customDateUpdateBtn)
+	})
 }
 
-// setStandardDates updates the dates in the Rekordbox database based on release dates.
+// setStandardDates prepares the update of dates in the Rekordbox database based on release dates.
 // It builds a WHERE clause to exclude specified folders if needed, counts affected records,
-// and executes the update query.
+// and records the new dates of each record into the changeset.
+// Parameters:
+//   - cs: The changeset collecting the changes
+//
 // Returns:
-//   - int: The number of records updated
+//   - int: The number of records matching the conditions
 //   - error: Any error that occurred during the operation
 //
 // The method handles cancellation during processing.
-func (m *DatesMasterModule) setStandardDates() (int, error) {
-	// Ensure database resources are properly released
-	defer m.dbMgr.Finalize()
-
+func (m *DatesMasterModule) setStandardDates(cs *common.Changeset) (int, error) {
 	// Build WHERE clause for excluded folders
 	whereClause := "WHERE ReleaseDate IS NOT NULL"
 	if m.excludeFoldersCheck.Checked {
@@ -617,32 +630,32 @@ func (m *DatesMasterModule) setStandardDates() (int, error) {
 		return 0, nil
 	}
 
-	// Update query
-	updateQuery := fmt.Sprintf("UPDATE djmdContent SET StockDate = ReleaseDate, DateCreated = ReleaseDate %s", whereClause)
-	err = m.dbMgr.Execute(updateQuery)
+	// Record the change of each affected song
+	rowsQuery := fmt.Sprintf("SELECT ID, FileNameL, StockDate, DateCreated, ReleaseDate FROM djmdContent %s", whereClause)
+	err = m.collectDateChanges(cs, rowsQuery, func(releaseDate common.NullString) interface{} {
+		return releaseDate.ValueOrNil()
+	})
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", locales.Translate("datesmaster.err.dbupdate"), err)
+		return 0, err
 	}
 
 	return totalCount, nil
 }
 
-// setCustomDates sets custom dates for tracks in selected folders.
+// setCustomDates prepares custom dates for tracks in selected folders.
 // It builds a WHERE clause to include only specified folders, counts affected records,
-// and executes the update query with the provided custom date.
+// and records the provided custom date of each record into the changeset.
 // Parameters:
+//   - cs: The changeset collecting the changes
 //   - customDateFoldersEntry: List of folder paths to include in the update
 //   - customDate: The date to set for all matching tracks
 //
 // Returns:
-//   - int: The number of records updated
+//   - int: The number of records matching the conditions
 //   - error: Any error that occurred during the operation
 //
 // The method handles cancellation during processing.
-func (m *DatesMasterModule) setCustomDates(customDateFoldersEntry []string, customDate time.Time) (int, error) {
-	// Ensure database resources are properly released
-	defer m.dbMgr.Finalize()
-
+func (m *DatesMasterModule) setCustomDates(cs *common.Changeset, customDateFoldersEntry []string, customDate time.Time) (int, error) {
 	// Build WHERE clause for selected folders
 	whereClause := "WHERE"
 	for i, folder := range customDateFoldersEntry {
@@ -670,17 +683,49 @@ func (m *DatesMasterModule) setCustomDates(customDateFoldersEntry []string, cust
 		return 0, nil
 	}
 
-	// Update query
-	updateQuery := fmt.Sprintf(`
-		UPDATE djmdContent
-		SET StockDate = ?,
-			DateCreated = ?
-		%s`, whereClause)
-
-	err = m.dbMgr.Execute(updateQuery, customDate.Format("2006-01-02"), customDate.Format("2006-01-02"))
+	// Record the change of each affected song
+	rowsQuery := fmt.Sprintf("SELECT ID, FileNameL, StockDate, DateCreated, ReleaseDate FROM djmdContent %s", whereClause)
+	err = m.collectDateChanges(cs, rowsQuery, func(common.NullString) interface{} {
+		return customDate.Format("2006-01-02")
+	})
 	if err != nil {
-		return 0, fmt.Errorf("%s: %w", locales.Translate("datesmaster.err.dbupdate"), err)
+		return 0, err
 	}
 
 	return totalCount, nil
 }
+
+// collectDateChanges records new StockDate and DateCreated values of songs selected by the query into the changeset.
+// Parameters:
+//   - cs: The changeset collecting the changes
+//   - rowsQuery: Query returning ID, FileNameL, StockDate, DateCreated and ReleaseDate of the affected songs
+//   - newDate: Function returning the new date for a song based on its release date
+//
+// Returns:
+//   - error: Any error that occurred while reading the songs
+func (m *DatesMasterModule) collectDateChanges(cs *common.Changeset, rowsQuery string, newDate func(releaseDate common.NullString) interface{}) error {
+	rows, err := m.dbMgr.Query(rowsQuery)
+	if err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("datesmaster.err.dbitemscount"), err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var fileName, stockDate, dateCreated, releaseDate common.NullString
+		if err := rows.Scan(&id, &fileName, &stockDate, &dateCreated, &releaseDate); err != nil {
+			return fmt.Errorf("%s: %w", locales.Translate("common.err.dbtrackscan"), err)
+		}
+
+		date := newDate(releaseDate)
+		cs.UpdateKnown(common.SQLTableDJMDContent, "ID", id, fileName.String,
+			common.FieldChange{Column: "StockDate", Old: stockDate.ValueOrNil(), New: date},
+			common.FieldChange{Column: "DateCreated", Old: dateCreated.ValueOrNil(), New: date},
+		)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", locales.Translate("common.err.dbrowsiteration"), err)
+	}
+
+	return nil
+}
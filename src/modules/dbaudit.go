@@ -0,0 +1,340 @@
+// modules/dbaudit.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module checks the integrity of the database and finds orphaned rows left behind by years of use,
+// e.g. cues of deleted tracks or playlist entries pointing at deleted content. Orphans can be removed after review.
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// DbAuditModule checks the database integrity and cleans up orphaned rows.
+// The audit itself only reads the database; the cleanup creates a backup first
+// and shows all deletions for review before anything is written.
+type DbAuditModule struct {
+	// ModuleBase provides common module functionality like error handling and UI components
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// cleanChecks determines which orphan categories are removed by the cleanup
+	cleanChecks map[common.OrphanKind]*widget.Check
+	// auditBtn runs the read-only audit
+	auditBtn *widget.Button
+	// submitBtn runs the audit and the cleanup
+	submitBtn *widget.Button
+}
+
+// NewDbAuditModule creates a new instance of DbAuditModule.
+// It initializes the module with the provided window, configuration manager, database manager,
+// and error handler, sets up the UI components, and loads any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized DbAuditModule instance
+func NewDbAuditModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *DbAuditModule {
+	m := &DbAuditModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *DbAuditModule) GetName() string {
+	return locales.Translate("dbaudit.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *DbAuditModule) GetConfigName() string {
+	return common.ModuleKeyDbAudit
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *DbAuditModule) GetIcon() fyne.Resource {
+	return theme.WarningIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// containing the cleanup checkboxes and the audit and cleanup buttons.
+func (m *DbAuditModule) GetModuleContent() fyne.CanvasObject {
+	checks := container.NewVBox(widget.NewLabel(locales.Translate("dbaudit.label.clean")))
+	for _, kind := range common.OrphanKinds {
+		checks.Add(m.cleanChecks[kind])
+	}
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("dbaudit.label.info")),
+		widget.NewSeparator(),
+		checks,
+	)
+
+	// Add buttons with right alignment
+	buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.auditBtn, m.submitBtn)
+	moduleContent.Add(buttonBox)
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content.
+// If no database path is set, the controls are disabled.
+func (m *DbAuditModule) GetContent() fyne.CanvasObject {
+	if m.dbMgr == nil || m.dbMgr.GetDatabasePath() == "" {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "PathToDatabaseCheck",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		common.DisableModuleControls(m.auditBtn, m.submitBtn)
+	}
+
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *DbAuditModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyDbAudit, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to DbAudit specific config
+	if cfg, ok := config.(common.DbAuditCfg); ok {
+		m.cleanChecks[common.OrphanKindCue].SetChecked(cfg.CleanCues.Value == "true")
+		m.cleanChecks[common.OrphanKindPlaylistEntry].SetChecked(cfg.CleanPlaylistEntries.Value == "true")
+		m.cleanChecks[common.OrphanKindAlbum].SetChecked(cfg.CleanAlbums.Value == "true")
+		m.cleanChecks[common.OrphanKindArtist].SetChecked(cfg.CleanArtists.Value == "true")
+	}
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *DbAuditModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultDbAuditCfg()
+
+	// Update only the values from current UI state
+	cfg.CleanCues.Value = fmt.Sprintf("%t", m.cleanChecks[common.OrphanKindCue].Checked)
+	cfg.CleanPlaylistEntries.Value = fmt.Sprintf("%t", m.cleanChecks[common.OrphanKindPlaylistEntry].Checked)
+	cfg.CleanAlbums.Value = fmt.Sprintf("%t", m.cleanChecks[common.OrphanKindAlbum].Checked)
+	cfg.CleanArtists.Value = fmt.Sprintf("%t", m.cleanChecks[common.OrphanKindArtist].Checked)
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyDbAudit, m.GetConfigName(), cfg)
+}
+
+// initializeUI sets up the user interface components.
+// It creates one checkbox per orphan category and the audit and cleanup buttons.
+func (m *DbAuditModule) initializeUI() {
+	m.cleanChecks = make(map[common.OrphanKind]*widget.Check)
+	for _, kind := range common.OrphanKinds {
+		m.cleanChecks[kind] = common.CreateCheckbox(locales.Translate("dbaudit.chkbox."+string(kind)), m.CreateBoolChangeHandler(func() {
+			m.SaveCfg()
+		}))
+	}
+
+	m.auditBtn = widget.NewButtonWithIcon(locales.Translate("dbaudit.button.audit"), theme.SearchIcon(), func() {
+		go m.runAudit()
+	})
+
+	m.submitBtn = common.CreateSubmitButton(locales.Translate("dbaudit.button.clean"), func() {
+		go m.Start()
+	})
+}
+
+// runAudit checks the database and reports the results without changing anything.
+// No backup is created, because the audit only reads the database.
+func (m *DbAuditModule) runAudit() {
+	m.ClearStatusMessages()
+	m.ShowProgressDialog(locales.Translate("dbaudit.dialog.header"))
+	m.StartProcessing(locales.Translate("dbaudit.status.checking"))
+
+	defer m.dbMgr.Finalize()
+
+	if _, ok := m.audit(); !ok {
+		return
+	}
+
+	m.CompleteProcessing(locales.Translate("dbaudit.status.auditdone"))
+	m.AddInfoMessage(locales.Translate("dbaudit.status.auditdone"))
+	m.CompleteProgressDialog()
+}
+
+// Start performs the necessary steps before starting the cleanup.
+// It validates the inputs, which includes creating a database backup,
+// displays a progress dialog and starts the cleanup in a goroutine.
+func (m *DbAuditModule) Start() {
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	// Show the progress dialog
+	m.ShowProgressDialog(locales.Translate("dbaudit.dialog.header"))
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "Database Cleanup",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processCleanup()
+	}()
+}
+
+// processCleanup audits the database and prepares the removal of orphans of the selected categories.
+// A database failing the integrity check is never cleaned up, because deleting rows could make things worse.
+// The deletions are shown for review and written in a single write session on approval.
+func (m *DbAuditModule) processCleanup() {
+	defer m.dbMgr.Finalize()
+
+	m.StartProcessing(locales.Translate("dbaudit.status.checking"))
+
+	report, ok := m.audit()
+	if !ok {
+		return
+	}
+
+	if !report.IntegrityOK() {
+		m.CloseProgressDialog()
+		context := &common.ErrorContext{
+			Module:      m.GetName(),
+			Operation:   "Database Cleanup",
+			Severity:    common.SeverityCritical,
+			Recoverable: false,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("dbaudit.err.integrity")), context)
+		m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+		return
+	}
+
+	// Collect all deletions first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
+	for _, kind := range common.OrphanKinds {
+		if !m.cleanChecks[kind].Checked {
+			continue
+		}
+		for _, orphan := range report.Orphans[kind] {
+			label := orphan.Label
+			if label == "" {
+				label = orphan.ID
+			}
+			cs.Delete(orphan.Table, orphan.ID, label)
+		}
+	}
+
+	if m.IsCancelled() {
+		m.HandleProcessCancellation("dbaudit.status.stopped")
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+
+	// Let the user review the deletions and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("dbaudit.dialog.header"), cs, func(applied int) {
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("dbaudit.status.cleaned"), applied))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("dbaudit.status.cleaned"), applied))
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
+
+// audit runs the database audit and reports its results as status messages.
+// Errors and cancellation are reported here.
+//
+// Returns:
+//   - The audit report and true if the audit completed
+//   - nil and false if the audit failed or was cancelled
+func (m *DbAuditModule) audit() (*common.AuditReport, bool) {
+	report, err := common.AuditDatabase(m.dbMgr, m.IsCancelled)
+	if errors.Is(err, common.ErrCancelled) {
+		m.HandleProcessCancellation("dbaudit.status.stopped")
+		return nil, false
+	}
+	if err != nil {
+		m.CloseProgressDialog()
+		context := &common.ErrorContext{
+			Module:      m.GetName(),
+			Operation:   "Database Audit",
+			Severity:    common.SeverityCritical,
+			Recoverable: false,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+		return nil, false
+	}
+
+	if report.IntegrityOK() {
+		m.AddInfoMessage(locales.Translate("dbaudit.status.integrityok"))
+	} else {
+		problems := report.Integrity
+		if len(problems) > 5 {
+			problems = problems[:5]
+		}
+		m.AddErrorMessage(fmt.Sprintf(locales.Translate("dbaudit.status.integrityfail"), len(report.Integrity), strings.Join(problems, "; ")))
+	}
+
+	for _, kind := range common.OrphanKinds {
+		count := len(report.Orphans[kind])
+		message := fmt.Sprintf(locales.Translate("dbaudit.status.orphans."+string(kind)), count)
+		if count > 0 {
+			m.AddWarningMessage(message)
+		} else {
+			m.AddInfoMessage(message)
+		}
+		m.Logger.Info("Database audit: %d orphaned rows of kind %s", count, kind)
+	}
+
+	return report, true
+}
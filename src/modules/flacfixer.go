@@ -3,7 +3,8 @@
 // Package modules provides functionality for different modules in the MetaRekordFixer application.
 // Each module handles a specific task related to DJ database management and music file operations.
 
-// This module reads metadata directly from FLAC files and updates the database with the extracted information
+// This module reads metadata directly from the tags of FLAC, AIFF, WAV, M4A and MP3 files
+// and updates the database with the extracted information
 
 package modules
 
@@ -11,9 +12,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -22,10 +26,47 @@ import (
 	"MetaRekordFixer/locales"
 )
 
-// FlacFixerModule handles metadata synchronization between different file formats.
-// It implements the standard Module interface and provides functionality for synchronizing
-// metadata between MP3 and FLAC files in a specified folder, ensuring consistent metadata
-// across different formats of the same tracks.
+// flacFixerFormats maps the format options shown in the UI to the file extensions they select.
+var flacFixerFormats = []struct {
+	label      string
+	extensions []string
+}{
+	{common.AudioFormatFLAC, []string{common.ExtensionFLAC}},
+	{common.AudioFormatAIFF, []string{common.ExtensionAIFF, ".aif"}},
+	{common.AudioFormatWAV, []string{common.ExtensionWAV}},
+	{common.AudioFormatM4A, []string{common.ExtensionM4A}},
+	{common.AudioFormatMP3, []string{common.ExtensionMP3}},
+}
+
+// flacFixerField is a metadata field shown in the UI with the locale key of its label or checkbox.
+type flacFixerField struct {
+	field     string
+	localeKey string
+}
+
+// flacFixerDefaultFields lists the metadata fields which are always written.
+var flacFixerDefaultFields = []flacFixerField{
+	{common.TagFieldAlbumArtist, "flacfixer.label.albumartist"},
+	{common.TagFieldOrigArtist, "flacfixer.label.origartist"},
+	{common.TagFieldReleaseDate, "flacfixer.label.releasedate"},
+	{common.TagFieldSubtitle, "flacfixer.label.subtitle"},
+}
+
+// flacFixerOptionalFields lists the metadata fields which can be selected in addition to the default fields.
+var flacFixerOptionalFields = []flacFixerField{
+	{common.TagFieldLabel, "flacfixer.chkbox.label"},
+	{common.TagFieldComposer, "flacfixer.chkbox.composer"},
+	{common.TagFieldRemixer, "flacfixer.chkbox.remixer"},
+	{common.TagFieldGenre, "flacfixer.chkbox.genre"},
+	{common.TagFieldISRC, "flacfixer.chkbox.isrc"},
+	{common.TagFieldTrackNumber, "flacfixer.chkbox.trackno"},
+	{common.TagFieldDiscNumber, "flacfixer.chkbox.discno"},
+	{common.TagFieldArtwork, "flacfixer.chkbox.artwork"},
+}
+
+// FlacFixerModule copies metadata fields which rekordbox does not import from the file tags to the database.
+// It implements the standard Module interface; the songs of the selected formats in a specified folder
+// are read with the tag keys of their format (Vorbis comments, ID3v2, MP4 atoms, RIFF INFO).
 type FlacFixerModule struct {
 	// ModuleBase is the base struct for all modules, which contains the module's window,
 	// error handler, and configuration manager.
@@ -38,8 +79,24 @@ type FlacFixerModule struct {
 	folderSelectionField fyne.CanvasObject
 	// recursiveCheck determines if the sync should process subfolders
 	recursiveCheck *widget.Check
+	// formatsCheck selects the audio formats to process
+	formatsCheck *widget.CheckGroup
+	// fieldChecks select the optional metadata fields to write, keyed by field name
+	fieldChecks map[string]*widget.Check
+	// policySelects select the overwrite policy of each metadata field, keyed by field name
+	policySelects map[string]*widget.Select
+	// dateFallbackSelect selects how release dates without a day are handled
+	dateFallbackSelect *widget.Select
+	// tagMapping holds the source tag keys and transforms edited in the mapping dialog
+	tagMapping common.TagMapping
+	// mappingBtn opens the dialog for editing the tag mapping
+	mappingBtn *widget.Button
 	// submitBtn triggers the synchronization process
 	submitBtn *widget.Button
+	// reportBtn shows the per-file report of the last run
+	reportBtn *widget.Button
+	// report holds the result of each file of the last run
+	report []common.FileReport
 }
 
 // NewFlacFixerModule creates a new instance of FlacFixerModule.
@@ -90,12 +147,27 @@ func (m *FlacFixerModule) GetIcon() fyne.Resource {
 
 // GetModuleContent returns the module's specific content without status messages.
 // This implements the method from ModuleBase to provide the module-specific UI
-// containing the folder selection field, recursive checkbox, and submit button.
+// containing the folder selection field, format and field selection, recursive checkbox, and submit button.
 func (m *FlacFixerModule) GetModuleContent() fyne.CanvasObject {
+	// Fields are arranged in a grid, each field followed by its overwrite policy
+	fieldsGrid := container.NewGridWithColumns(4)
+	for _, field := range flacFixerDefaultFields {
+		fieldsGrid.Add(widget.NewLabel(locales.Translate(field.localeKey)))
+		fieldsGrid.Add(m.policySelects[field.field])
+	}
+	for _, optional := range flacFixerOptionalFields {
+		fieldsGrid.Add(m.fieldChecks[optional.field])
+		fieldsGrid.Add(m.policySelects[optional.field])
+	}
+
 	// Create form with folder selection field
 	form := &widget.Form{
 		Items: []*widget.FormItem{
 			{Text: locales.Translate("flacfixer.label.source"), Widget: m.folderSelectionField},
+			{Text: locales.Translate("flacfixer.label.formats"), Widget: m.formatsCheck},
+			{Text: locales.Translate("flacfixer.label.fields"), Widget: fieldsGrid},
+			{Text: locales.Translate("flacfixer.label.datefallback"), Widget: m.dateFallbackSelect},
+			{Text: locales.Translate("flacfixer.label.mapping"), Widget: container.NewHBox(m.mappingBtn)},
 		},
 	}
 
@@ -114,7 +186,7 @@ func (m *FlacFixerModule) GetModuleContent() fyne.CanvasObject {
 
 	// Add submit button with right alignment if provided
 	if m.submitBtn != nil {
-		buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.submitBtn)
+		buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.reportBtn, m.submitBtn)
 		moduleContent.Add(buttonBox)
 	}
 
@@ -150,6 +222,24 @@ func (m *FlacFixerModule) LoadCfg() {
 		// Update UI elements with loaded values
 		m.sourceFolderEntry.SetText(cfg.SourceFolder.Value)
 		m.recursiveCheck.SetChecked(cfg.Recursive.Value == "true")
+		m.formatsCheck.SetSelected(formatsFromExtensions(common.ParseExtensionsCSV(cfg.Extensions.Value)))
+		for field, fieldCfg := range optionalFieldCfgs(&cfg) {
+			m.fieldChecks[field].SetChecked(fieldCfg.Value == "true")
+		}
+		policies := common.ParseFieldPolicies(cfg.FieldPolicies.Value)
+		for field, policySelect := range m.policySelects {
+			policy, ok := policies[field]
+			if !ok {
+				policy = common.FieldPolicyFillEmpty
+			}
+			policySelect.SetSelected(locales.Translate("flacfixer.dropdown." + policy))
+		}
+		dateFallback := cfg.DateFallback.Value
+		if dateFallback == "" {
+			dateFallback = common.DateFallbackFirstDay
+		}
+		m.dateFallbackSelect.SetSelected(locales.Translate("flacfixer.datefallback." + dateFallback))
+		m.tagMapping = common.ParseTagMapping(cfg.TagMapping.Value)
 	}
 }
 
@@ -165,6 +255,13 @@ func (m *FlacFixerModule) SaveCfg() {
 	// Update only the values from current UI state
 	cfg.SourceFolder.Value = common.NormalizePath(m.sourceFolderEntry.Text)
 	cfg.Recursive.Value = fmt.Sprintf("%t", m.recursiveCheck.Checked)
+	cfg.Extensions.Value = strings.Join(extensionsFromFormats(m.formatsCheck.Selected), ",")
+	for field, fieldCfg := range optionalFieldCfgs(&cfg) {
+		fieldCfg.Value = fmt.Sprintf("%t", m.fieldChecks[field].Checked)
+	}
+	cfg.FieldPolicies.Value = common.FormatFieldPolicies(m.selectedPolicies())
+	cfg.DateFallback.Value = m.selectedDateFallback()
+	cfg.TagMapping.Value = common.FormatTagMapping(m.tagMapping)
 
 	// Save typed config via ConfigManager
 	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyFlacFixer, m.GetConfigName(), cfg)
@@ -194,6 +291,55 @@ func (m *FlacFixerModule) initializeUI() {
 		m.SaveCfg()
 	})
 
+	// Initialize format selection, all formats are offered in a single row
+	formatLabels := make([]string, 0, len(flacFixerFormats))
+	for _, format := range flacFixerFormats {
+		formatLabels = append(formatLabels, format.label)
+	}
+	m.formatsCheck = widget.NewCheckGroup(formatLabels, func(selected []string) {
+		m.SaveCfg()
+	})
+	m.formatsCheck.Horizontal = true
+
+	// Initialize checkboxes of the optional metadata fields
+	m.fieldChecks = make(map[string]*widget.Check, len(flacFixerOptionalFields))
+	for _, optional := range flacFixerOptionalFields {
+		m.fieldChecks[optional.field] = common.CreateCheckbox(locales.Translate(optional.localeKey), func(checked bool) {
+			m.SaveCfg()
+		})
+	}
+
+	// Initialize overwrite policy selection of all fields
+	policyOptions := make([]string, 0, len(common.FieldPolicies))
+	for _, policy := range common.FieldPolicies {
+		policyOptions = append(policyOptions, locales.Translate("flacfixer.dropdown."+policy))
+	}
+	m.policySelects = make(map[string]*widget.Select, len(flacFixerDefaultFields)+len(flacFixerOptionalFields))
+	for _, field := range append(append([]flacFixerField(nil), flacFixerDefaultFields...), flacFixerOptionalFields...) {
+		policySelect := widget.NewSelect(policyOptions, nil)
+		policySelect.OnChanged = m.CreateSelectionChangeHandler(func() { m.SaveCfg() })
+		m.policySelects[field.field] = policySelect
+	}
+
+	// Initialize selection of the fallback for incomplete release dates
+	dateFallbackOptions := make([]string, 0, len(common.DateFallbacks))
+	for _, fallback := range common.DateFallbacks {
+		dateFallbackOptions = append(dateFallbackOptions, locales.Translate("flacfixer.datefallback."+fallback))
+	}
+	m.dateFallbackSelect = widget.NewSelect(dateFallbackOptions, nil)
+	m.dateFallbackSelect.OnChanged = m.CreateSelectionChangeHandler(func() { m.SaveCfg() })
+
+	// Initialize button of the tag mapping dialog
+	m.mappingBtn = widget.NewButtonWithIcon(locales.Translate("flacfixer.button.mapping"), theme.DocumentCreateIcon(), func() {
+		m.showMappingDialog()
+	})
+
+	// Initialize report button, enabled once a run produced a report
+	m.reportBtn = widget.NewButtonWithIcon(locales.Translate("flacfixer.button.report"), theme.ListIcon(), func() {
+		common.ShowFileReportDialog(m.Window, m.report)
+	})
+	m.reportBtn.Disable()
+
 	// Initialize sync button
 	m.submitBtn = common.CreateSubmitButton(locales.Translate("flacfixer.button.sync"), func() {
 		go m.Start()
@@ -217,6 +363,14 @@ func (m *FlacFixerModule) Start() {
 	}
 
 	sourcePath := common.NormalizePath(m.sourceFolderEntry.Text)
+	options := common.MetadataOptions{
+		Extensions:   extensionsFromFormats(m.formatsCheck.Selected),
+		Fields:       m.selectedFields(),
+		Policies:     m.selectedPolicies(),
+		DateFallback: m.selectedDateFallback(),
+		Recursive:    m.recursiveCheck.Checked,
+		Mapping:      m.tagMapping,
+	}
 
 	// Prepare cancelable context and show progress dialog with cancel support
 	ctx, cancel := context.WithCancel(context.Background())
@@ -225,25 +379,6 @@ func (m *FlacFixerModule) Start() {
 		locales.Translate("flacfixer.dialog.header"),
 		func() {
 			cancel()
-			sourcePath := common.NormalizePath(m.sourceFolderEntry.Text)
-
-			go func() {
-				defer func() {
-					if r := recover(); r != nil {
-						context := &common.ErrorContext{
-							Module:      m.GetName(),
-							Operation:   "Metadata Sync",
-							Severity:    common.SeverityCritical,
-							Recoverable: false,
-						}
-						m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
-						m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
-					}
-				}()
-
-				// Process metadata copy with cancellation context
-				m.processFlacFixer(ctx, sourcePath)
-			}()
 		},
 	)
 
@@ -264,24 +399,189 @@ func (m *FlacFixerModule) Start() {
 		}()
 
 		// Process metadata copy with cancellation context
-		m.processFlacFixer(ctx, sourcePath)
+		m.processFlacFixer(ctx, sourcePath, options)
 	}()
 }
 
-// processFlacFixer handles the actual metadata processing from FLAC files.
-// It reads metadata directly from FLAC files in the specified folder and updates
+// selectedFields returns the metadata fields to write: the default fields and the selected optional fields.
+func (m *FlacFixerModule) selectedFields() []string {
+	fields := append([]string(nil), common.DefaultMetadataFields...)
+	for _, optional := range flacFixerOptionalFields {
+		if m.fieldChecks[optional.field].Checked {
+			fields = append(fields, optional.field)
+		}
+	}
+	return fields
+}
+
+// selectedPolicies returns the selected overwrite policy of each metadata field.
+func (m *FlacFixerModule) selectedPolicies() map[string]string {
+	policies := make(map[string]string, len(m.policySelects))
+	for field, policySelect := range m.policySelects {
+		for _, policy := range common.FieldPolicies {
+			if policySelect.Selected == locales.Translate("flacfixer.dropdown."+policy) {
+				policies[field] = policy
+			}
+		}
+	}
+	return policies
+}
+
+// selectedDateFallback returns the selected fallback for incomplete release dates.
+func (m *FlacFixerModule) selectedDateFallback() string {
+	for _, fallback := range common.DateFallbacks {
+		if m.dateFallbackSelect.Selected == locales.Translate("flacfixer.datefallback."+fallback) {
+			return fallback
+		}
+	}
+	return common.DateFallbackFirstDay
+}
+
+// showMappingDialog shows the editable tag mapping: for each metadata field the source tag keys
+// in order of preference and the transform of the values. Fields without keys are read with the built-in keys,
+// which are shown as placeholder. The mapping is saved to the configuration on confirmation.
+func (m *FlacFixerModule) showMappingDialog() {
+	transformOptions := make([]string, 0, len(common.TagTransforms))
+	for _, transform := range common.TagTransforms {
+		transformOptions = append(transformOptions, locales.Translate("flacfixer.transform."+transform))
+	}
+
+	grid := container.NewGridWithColumns(3,
+		widget.NewLabelWithStyle(locales.Translate("flacfixer.mapping.field"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(locales.Translate("flacfixer.mapping.keys"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewLabelWithStyle(locales.Translate("flacfixer.mapping.transform"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	)
+	fields := mappingFields()
+	keyEntries := make([]*widget.Entry, len(fields))
+	transformSelects := make([]*widget.Select, len(fields))
+	for i, field := range fields {
+		entry := m.tagMapping[field.field]
+
+		keyEntries[i] = widget.NewEntry()
+		keyEntries[i].SetPlaceHolder(strings.Join(common.TagFieldKeys[field.field][common.TagContainerVorbis], " | "))
+		keyEntries[i].SetText(strings.Join(entry.Keys, " | "))
+
+		transformSelects[i] = widget.NewSelect(transformOptions, nil)
+		transformSelects[i].SetSelected(locales.Translate("flacfixer.transform." + m.tagMapping.Transform(field.field)))
+
+		grid.Add(widget.NewLabel(locales.Translate(field.localeKey)))
+		grid.Add(keyEntries[i])
+		grid.Add(transformSelects[i])
+	}
+
+	infoLabel := widget.NewLabel(locales.Translate("flacfixer.mapping.info"))
+	infoLabel.Wrapping = fyne.TextWrapWord
+
+	var dlg *dialog.CustomDialog
+
+	resetBtn := widget.NewButtonWithIcon(locales.Translate("flacfixer.button.mappingreset"), theme.ContentUndoIcon(), func() {
+		for i := range fields {
+			keyEntries[i].SetText("")
+			transformSelects[i].SetSelected(locales.Translate("flacfixer.transform." + common.TagTransformNone))
+		}
+	})
+	cancelBtn := widget.NewButtonWithIcon(locales.Translate("common.button.cancel"), theme.CancelIcon(), func() {
+		dlg.Hide()
+	})
+	saveBtn := widget.NewButtonWithIcon(locales.Translate("common.button.save"), theme.ConfirmIcon(), func() {
+		mapping := make(common.TagMapping, len(fields))
+		for i, field := range fields {
+			entry := common.TagMappingEntry{Keys: common.ParseTagKeys(keyEntries[i].Text), Transform: common.TagTransformNone}
+			for _, transform := range common.TagTransforms {
+				if transformSelects[i].Selected == locales.Translate("flacfixer.transform."+transform) {
+					entry.Transform = transform
+				}
+			}
+			mapping[field.field] = entry
+		}
+		m.tagMapping = mapping
+		m.SaveCfg()
+		dlg.Hide()
+	})
+	saveBtn.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		infoLabel,
+		container.NewHBox(resetBtn, layout.NewSpacer(), cancelBtn, saveBtn),
+		nil,
+		nil,
+		container.NewVScroll(grid),
+	)
+
+	dlg = dialog.NewCustomWithoutButtons(locales.Translate("flacfixer.mapping.header"), content, m.Window)
+	dlg.Resize(fyne.NewSize(750, 600))
+	dlg.Show()
+}
+
+// mappingFields returns the metadata fields shown in the tag mapping dialog, in the order of the module's fields.
+// The artwork is not read from a tag key and cannot be mapped.
+func mappingFields() []flacFixerField {
+	var fields []flacFixerField
+	for _, field := range append(append([]flacFixerField(nil), flacFixerDefaultFields...), flacFixerOptionalFields...) {
+		if _, ok := common.TagFieldKeys[field.field]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// optionalFieldCfgs returns the configuration fields of the optional metadata fields keyed by field name.
+func optionalFieldCfgs(cfg *common.FlacFixerCfg) map[string]*common.FieldCfg {
+	return map[string]*common.FieldCfg{
+		common.TagFieldLabel:       &cfg.WriteLabel,
+		common.TagFieldComposer:    &cfg.WriteComposer,
+		common.TagFieldISRC:        &cfg.WriteISRC,
+		common.TagFieldRemixer:     &cfg.WriteRemixer,
+		common.TagFieldGenre:       &cfg.WriteGenre,
+		common.TagFieldTrackNumber: &cfg.WriteTrackNo,
+		common.TagFieldDiscNumber:  &cfg.WriteDiscNo,
+		common.TagFieldArtwork:     &cfg.WriteArtwork,
+	}
+}
+
+// formatsFromExtensions returns the format options selected by the given file extensions.
+func formatsFromExtensions(extensions []string) []string {
+	var selected []string
+	for _, format := range flacFixerFormats {
+		for _, ext := range format.extensions {
+			if slices.Contains(extensions, ext) {
+				selected = append(selected, format.label)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// extensionsFromFormats returns the file extensions of the given format options.
+func extensionsFromFormats(selected []string) []string {
+	var extensions []string
+	for _, format := range flacFixerFormats {
+		if slices.Contains(selected, format.label) {
+			extensions = append(extensions, format.extensions...)
+		}
+	}
+	return extensions
+}
+
+// processFlacFixer handles the actual metadata processing from the audio files.
+// It reads metadata directly from the tags of the audio files in the specified folder and updates
 // the database with the extracted information, managing progress and status updates.
 //
 // The method performs the following steps:
-// 1. Finds all FLAC files in the specified folder (recursively if enabled)
-// 2. Reads metadata directly from each FLAC file
-// 3. Updates the database with artist, album, and track metadata
+// 1. Finds all files of the selected formats in the specified folder (recursively if enabled)
+// 2. Reads metadata directly from the tags of each file
+// 3. Updates the database with the selected artist, album, and track metadata according to the overwrite policies
 // 4. Updates progress and handles cancellation throughout the process
 //
+// All database changes are collected into a changeset and shown for review first,
+// approved changes are written in a single write session.
+//
 // Parameters:
 //   - ctx: The context for cancellation
 //   - sourcePath: The folder path to process for metadata extraction
-func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath string) {
+//   - options: The selected formats, fields and overwrite policies
+func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath string, options common.MetadataOptions) {
 	defer m.dbMgr.Finalize()
 
 	// Normalize paths
@@ -290,12 +590,15 @@ func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath strin
 	// Do not show initial generic progress; validator already provided start status,
 	// and specific progress will appear as soon as counts are known.
 
-	// Process all FLAC files in the folder
+	// Collect all changes first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
+
+	// Process all files of the selected formats in the folder
 	summary, err := common.ProcessFolderMetadata(
 		ctx,
-		m.dbMgr,
+		cs,
 		sourcePath,
-		m.recursiveCheck.Checked,
+		options,
 		func(total int) {
 			// Inform about files found
 			m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.filesfound"), total))
@@ -312,6 +615,12 @@ func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath strin
 		},
 	)
 
+	// The report of the files processed so far can be viewed even if the run was stopped
+	if len(summary.Files) > 0 {
+		m.report = summary.Files
+		m.reportBtn.Enable()
+	}
+
 	if err != nil {
 		// Handle cancellation explicitly
 		if errors.Is(err, common.ErrCancelled) {
@@ -322,7 +631,7 @@ func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath strin
 		m.CloseProgressDialog()
 		context := &common.ErrorContext{
 			Module:      m.GetName(),
-			Operation:   "FLAC Metadata Processing",
+			Operation:   "Tag Metadata Processing",
 			Severity:    common.SeverityCritical,
 			Recoverable: false,
 		}
@@ -338,11 +647,28 @@ func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath strin
 		return
 	}
 
-	// Add completion status messages
+	// No files means nothing to review
 	if summary.Total == 0 {
 		m.AddErrorMessage(locales.Translate("common.err.nofiles"))
 		m.UpdateProgressStatus(1.0, locales.Translate("common.err.nofiles"))
-	} else {
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+
+	// Report release dates which could not be normalized, they are listed in the log
+	if len(summary.DateIssues) > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("flacfixer.status.dateissues"), len(summary.DateIssues)))
+	}
+
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("flacfixer.dialog.header"), cs, func(applied int) {
+		// The artwork images are created only after their ImagePath was written
+		if applied > 0 && len(summary.Artwork) > 0 {
+			m.writeArtwork(summary.Artwork)
+		}
+
+		// Add completion status messages
 		finalMsg := fmt.Sprintf(
 			locales.Translate("flacfixer.status.summary"),
 			summary.Total,
@@ -355,10 +681,46 @@ func (m *FlacFixerModule) processFlacFixer(ctx context.Context, sourcePath strin
 			summary.SkippedDirs,
 		)
 		m.AddInfoMessage(finalMsg)
+		m.addFieldStatsMessages(summary.Fields)
+		m.AddInfoMessage(locales.Translate("flacfixer.status.report"))
 		m.CompleteProcessing(finalMsg)
+
+		// Mark the progress dialog as completed and update button
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
+
+// writeArtwork creates the images of the imported artwork in the share folder of the database.
+// Images which cannot be created are reported, the tracks keep their new ImagePath.
+//
+// Parameters:
+//   - files: The artwork collected by ProcessFolderMetadata
+func (m *FlacFixerModule) writeArtwork(files []common.ArtworkFile) {
+	written, failed := common.WriteArtworkFiles(common.ArtworkShareDir(m.dbMgr.GetDatabasePath()), files)
+	for _, err := range failed {
+		m.Logger.Error("%v", err)
+	}
+	if len(failed) > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("flacfixer.status.artworkfailed"), len(failed)))
 	}
+	m.AddInfoMessage(fmt.Sprintf(locales.Translate("flacfixer.status.artwork"), written))
+}
 
-	// Mark the progress dialog as completed and update button
-	m.CompleteProgressDialog()
-	common.UpdateButtonToCompleted(m.submitBtn)
+// addFieldStatsMessages adds a status message with the counts of each metadata field with filled,
+// overwritten or conflicting values. Conflicts are reported as warnings.
+func (m *FlacFixerModule) addFieldStatsMessages(fieldStats map[string]common.FieldStats) {
+	for _, field := range append(append([]flacFixerField(nil), flacFixerDefaultFields...), flacFixerOptionalFields...) {
+		stats, ok := fieldStats[field.field]
+		if !ok {
+			continue
+		}
+		msg := fmt.Sprintf(locales.Translate("flacfixer.status.field"),
+			locales.Translate(field.localeKey), stats.Filled, stats.Overwritten, stats.Conflicts)
+		if stats.Conflicts > 0 {
+			m.AddWarningMessage(msg)
+		} else {
+			m.AddInfoMessage(msg)
+		}
+	}
 }
@@ -20,13 +20,9 @@ import (
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 
-	"MetaRekordFixer/assets"
 	"MetaRekordFixer/common"
 	"MetaRekordFixer/locales"
-	"bytes"
-	"encoding/csv"
 	"encoding/json"
-	"io"
 	"os/exec"
 	"path/filepath"
 	"sort"
@@ -74,7 +70,7 @@ type FormatConverterModule struct {
 	// Current state
 	currentTargetFormat string
 	isConverting        bool
-	metadataMap         *MetadataMap
+	metadataMap         *common.MetadataMap
 
 	// Current ffmpeg process
 	currentProcess *exec.Cmd
@@ -320,7 +316,7 @@ func (m *FormatConverterModule) LoadCfg() {
 	}
 
 	// Ensure metadata map is loaded
-	m.metadataMap, _ = m.loadMetadataMap()
+	m.metadataMap, _ = common.LoadMetadataMap()
 }
 
 // SaveCfg saves current UI state to typed configuration
@@ -991,7 +987,7 @@ func (m *FormatConverterModule) convertFiles(sourceFolder, targetFolder, targetF
 //
 // Returns:
 //   - error if the conversion fails, nil otherwise
-func (m *FormatConverterModule) convertFile(sourcePath, targetPath, targetFormat string, formatSettings map[string]string, metadata map[string]string, bitDepth string, sampleRate string, metadataMap *MetadataMap) error {
+func (m *FormatConverterModule) convertFile(sourcePath, targetPath, targetFormat string, formatSettings map[string]string, metadata map[string]string, bitDepth string, sampleRate string, metadataMap *common.MetadataMap) error {
 	// Build ffmpeg arguments
 	args := []string{
 		"-i", sourcePath,
@@ -1167,17 +1163,6 @@ func (m *FormatConverterModule) convertFile(sourcePath, targetPath, targetFormat
 	return nil
 }
 
-// MetadataMap represents the mapping between metadata fields for different formats.
-// It provides translation tables between internal field names and format-specific field names.
-type MetadataMap struct {
-	// InternalToMP3 maps internal field names to MP3 (ID3) field names
-	InternalToMP3 map[string]string
-	// InternalToFLAC maps internal field names to FLAC field names
-	InternalToFLAC map[string]string
-	// InternalToWAV maps internal field names to WAV field names
-	InternalToWAV map[string]string
-}
-
 // metadataItem represents a metadata key-value pair for ffmpeg
 type metadataItem struct {
 	key   string
@@ -1322,77 +1307,6 @@ var (
 	}
 )
 
-// loadMetadataMap loads the metadata mapping from the embedded CSV file.
-// The CSV file defines how metadata fields should be mapped between different audio formats.
-//
-// Returns:
-//   - A populated MetadataMap structure and nil error on success
-//   - nil and an error if loading or parsing fails
-func (m *FormatConverterModule) loadMetadataMap() (*MetadataMap, error) {
-	// Load the CSV content from the embedded file
-	csvContent := assets.ResourceMetadataMapCSV.Content()
-
-	// Create a new CSV reader from the content
-	reader := csv.NewReader(bytes.NewReader(csvContent))
-
-	// Read the header row
-	header, err := reader.Read()
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", locales.Translate("formatconverter.err.readcsvheader"), err)
-	}
-
-	// Initialize maps
-	result := &MetadataMap{
-		InternalToMP3:  make(map[string]string),
-		InternalToFLAC: make(map[string]string),
-		InternalToWAV:  make(map[string]string),
-	}
-
-	// Find column indices
-	mpIndex := -1
-	flacIndex := -1
-	wavIndex := -1
-	for i, col := range header {
-		switch col {
-		case "MP3":
-			mpIndex = i
-		case "FLAC":
-			flacIndex = i
-		case "WAV":
-			wavIndex = i
-		}
-	}
-
-	if mpIndex == -1 || flacIndex == -1 || wavIndex == -1 {
-		return nil, errors.New(locales.Translate("formatconverter.err.metamapheader"))
-	}
-
-	// Read and process each row
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, errors.New(locales.Translate("formatconverter.err.readmaprow"))
-
-		}
-
-		// Skip empty rows
-		if len(record) == 0 || record[0] == "" {
-			continue
-		}
-
-		// Map the fields
-		internalName := record[0]
-		result.InternalToMP3[internalName] = record[mpIndex]
-		result.InternalToFLAC[internalName] = record[flacIndex]
-		result.InternalToWAV[internalName] = record[wavIndex]
-	}
-
-	return result, nil
-}
-
 // findAudioFiles recursively finds all audio files in the given directory.
 // If sourceFormat is specified (not "All"), only files of that format are returned.
 //
@@ -1572,7 +1486,7 @@ func detectSourceFormat(filePath string) string {
 //
 // Returns:
 //   - error if mapping fails, nil otherwise
-func (m *FormatConverterModule) mapMetadataUsingCSV(sourceFormat, targetFormat string, sourceMetadata map[string]string, metadataMap *MetadataMap, metadataItems *[]metadataItem) error {
+func (m *FormatConverterModule) mapMetadataUsingCSV(sourceFormat, targetFormat string, sourceMetadata map[string]string, metadataMap *common.MetadataMap, metadataItems *[]metadataItem) error {
 	// Get the appropriate source and target mapping based on formats
 	var sourceMap, targetMap map[string]string
 
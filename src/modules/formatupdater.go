@@ -175,11 +175,8 @@ func (m *FormatUpdaterModule) LoadCfg() {
 
 		// Load playlist selection if playlists are already loaded
 		if m.pendingPlaylistID != "" && len(m.playlists) > 0 {
-			for _, playlist := range m.playlists {
-				if playlist.ID == m.pendingPlaylistID {
-					m.playlistSelect.SetSelected(playlist.Path)
-					break
-				}
+			if option := common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.pendingPlaylistID); option != "" {
+				m.playlistSelect.SetSelected(option)
 			}
 		}
 	}
@@ -213,12 +210,14 @@ func (m *FormatUpdaterModule) initializeUI() {
 	// The select widget is disabled to prevent the user from changing the playlist
 	// before the module is fully loaded.
 	m.playlistSelect = common.CreatePlaylistSelect(m.CreateSelectionChangeHandler(func() {
-		// Find the playlist ID for the selected name
-		for _, p := range m.playlists {
-			if p.Path == m.playlistSelect.Selected {
-				m.pendingPlaylistID = p.ID
-				break
-			}
+		// Folders cannot be used as a source of tracks
+		if !common.GuardPlaylistSelection(m.playlists, m.playlistSelect, m.pendingPlaylistID) {
+			return
+		}
+		// Find the playlist ID for the selected option
+		m.pendingPlaylistID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.playlistSelect); ok {
+			m.pendingPlaylistID = p.ID
 		}
 		m.SaveCfg()
 	}), "common.select.plsplacehldrinact")
@@ -252,34 +251,9 @@ func (m *FormatUpdaterModule) initializeUI() {
 	)
 }
 
-// getFileType translates a file extension into a numeric identifier used in the database.
-// This identifier is stored in the FileType field of the djmdContent table.
-//
-// Parameters:
-//   - ext: The file extension including the dot (e.g., ".mp3")
-//
-// Returns:
-//   - An integer representing the file type in the database format
-func getFileType(ext string) int {
-	switch strings.ToLower(ext) {
-	case common.ExtensionMP3:
-		return 1
-	case common.ExtensionM4A:
-		return 4
-	case common.ExtensionFLAC:
-		return 5
-	case common.ExtensionWAV:
-		return 11
-	case common.ExtensionAIFF:
-		return 12
-	default:
-		return 0
-	}
-}
-
 // loadPlaylists loads playlist items from the database and updates the playlist selector.
 // It connects to the database, retrieves all playlists, and updates the UI component
-// with the playlist paths. It also restores any previously selected playlist.
+// with the playlist tree. It also restores any previously selected playlist.
 //
 // Returns:
 //   - An error if database connection or playlist retrieval fails, nil otherwise
@@ -299,27 +273,11 @@ func (m *FormatUpdaterModule) loadPlaylists() error {
 	// Store playlists for later use
 	m.playlists = playlists
 
-	// Create options list for selectors
-	playlistPaths := make([]string, len(playlists))
-	for i, playlist := range playlists {
-		playlistPaths[i] = playlist.Path
-	}
-
-	// Update select widget options
-	m.playlistSelect.Options = playlistPaths
-
-	// Set active state with appropriate placeholder
-	var selectedValue string
+	// Update select widget options with the playlist tree
+	m.playlistSelect.Options = common.PlaylistSelectOptions(playlists)
 
 	// Find selected value from pending ID if exists
-	if m.pendingPlaylistID != "" {
-		for _, playlist := range m.playlists {
-			if playlist.ID == m.pendingPlaylistID {
-				selectedValue = playlist.Path
-				break
-			}
-		}
-	}
+	selectedValue := common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.pendingPlaylistID)
 
 	// Set active state with found value (or empty if no pending ID)
 	common.SetPlaylistSelectState(m.playlistSelect, true, selectedValue)
@@ -356,11 +314,14 @@ func (m *FormatUpdaterModule) Start() {
 // 2. Loading tracks from the selected playlist
 // 3. Scanning the target folder for matching files
 // 4. Matching files by base name (without extension)
-// 5. Updating track records in the database
-// 6. Reporting progress and results
+// 5. Showing the prepared track record changes for review
+// 6. Updating track records in the database in a single write session on approval
+// 7. Reporting progress and results
 //
 // The process can be cancelled at any time by the user.
 func (m *FormatUpdaterModule) processUpdate() {
+	defer m.dbMgr.Finalize()
+
 	// Track the number of updated files.
 	updateCount := 0
 	// Validate playlist selection
@@ -401,11 +362,8 @@ func (m *FormatUpdaterModule) processUpdate() {
 	// Get the selected playlist.
 	m.StartProcessing(locales.Translate("common.status.playlistload"))
 	selectedPlaylist := ""
-	for _, p := range m.playlists {
-		if p.Path == m.playlistSelect.Selected {
-			selectedPlaylist = p.ID
-			break
-		}
+	if p, ok := common.SelectedPlaylist(m.playlists, m.playlistSelect); ok && p.Selectable() {
+		selectedPlaylist = p.ID
 	}
 	if selectedPlaylist == "" {
 		context := &common.ErrorContext{
@@ -522,7 +480,7 @@ func (m *FormatUpdaterModule) processUpdate() {
 
 		newPath := newFiles[0]
 		newExt := strings.ToLower(filepath.Ext(newPath))
-		newFileType := getFileType(newExt)
+		newFileType := common.GetFileType(newExt)
 		if newFileType == 0 {
 			nonMatchingFiles++
 			mismatchedFiles = append(mismatchedFiles, track.FileName)
@@ -566,16 +524,14 @@ func (m *FormatUpdaterModule) processUpdate() {
 		return
 	}
 
-	// Update tracks in database
+	// Collect all changes first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
 	for _, updateTrack := range updateTracks {
-		if err := m.dbMgr.Execute(`
-			UPDATE djmdContent
-			SET 
-				FolderPath = ?,
-				FileNameL = ?,
-				FileType = ?
-			WHERE ID = ?
-		`, updateTrack.NewPath, updateTrack.NewFileName, updateTrack.NewFileType, updateTrack.TrackID); err != nil {
+		if _, err := cs.Update(common.SQLTableDJMDContent, updateTrack.TrackID, updateTrack.NewFileName,
+			common.FieldChange{Column: "FolderPath", New: updateTrack.NewPath},
+			common.FieldChange{Column: "FileNameL", New: updateTrack.NewFileName},
+			common.FieldChange{Column: "FileType", New: updateTrack.NewFileType},
+		); err != nil {
 			context := &common.ErrorContext{
 				Module:      m.GetConfigName(),
 				Operation:   "Update Track",
@@ -587,9 +543,6 @@ func (m *FormatUpdaterModule) processUpdate() {
 			return
 		}
 
-		updateCount++
-		m.UpdateProcessingProgress(updateCount-1, len(updateTracks), fmt.Sprintf(locales.Translate("formatupdater.status.progress"), updateCount, len(updateTracks)))
-
 		// Check if operation was cancelled
 		if m.IsCancelled() {
 			m.HandleProcessCancellation("updater.status.stopped", updateCount, len(updateTracks))
@@ -598,13 +551,18 @@ func (m *FormatUpdaterModule) processUpdate() {
 		}
 	}
 
-	// Update progress and status
-	m.CompleteProcessing(fmt.Sprintf(locales.Translate("formatupdater.status.completed"), updateCount))
-	m.AddInfoMessage(fmt.Sprintf(locales.Translate("formatupdater.status.completed"), updateCount))
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("formatupdater.dialog.header"), cs, func(applied int) {
+		updateCount = applied
 
-	// Mark the progress dialog as completed
-	m.CompleteProgressDialog()
+		// Update progress and status
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("formatupdater.status.completed"), updateCount))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("formatupdater.status.completed"), updateCount))
 
-	// Update submit button to show completion
-	common.UpdateButtonToCompleted(m.submitBtn)
+		// Mark the progress dialog as completed
+		m.CompleteProgressDialog()
+
+		// Update submit button to show completion
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
 }
@@ -0,0 +1,572 @@
+// modules/playlistmirror.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module mirrors a playlist tree (e.g. "Music" with FLAC files) into another folder of playlists
+// (e.g. "Music MP3"), replacing every FLAC track with its MP3 twin found in a chosen folder.
+// Later runs update the mirrored tree incrementally.
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// PlaylistMirrorModule clones a playlist subtree under another folder of playlists,
+// substituting each FLAC track with its MP3 twin.
+type PlaylistMirrorModule struct {
+	// ModuleBase provides common module functionality like error handling and UI components
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// sourceSelect selects the folder or playlist to mirror
+	sourceSelect *widget.Select
+	// targetSelect selects the folder receiving the mirrored playlists
+	targetSelect *widget.Select
+	// twinFolderEntry holds the folder searched for MP3 twins
+	twinFolderEntry *widget.Entry
+	// twinFolderField is the folder selection field containing twinFolderEntry
+	twinFolderField fyne.CanvasObject
+	// removeStaleCheck determines whether mirrored playlists missing in the source are removed
+	removeStaleCheck *widget.Check
+	// submitBtn starts the mirroring
+	submitBtn *widget.Button
+	// playlists holds all folders and playlists shown in sourceSelect
+	playlists []common.PlaylistItem
+	// folders holds the folders shown in targetSelect
+	folders []common.PlaylistItem
+	// sourceID and targetID keep the selected IDs until the playlists are loaded
+	sourceID string
+	targetID string
+}
+
+// mirrorStats counts the changes prepared by a mirroring run.
+type mirrorStats struct {
+	created      int
+	removed      int
+	added        int
+	dropped      int
+	moved        int
+	missingTwins int
+}
+
+// NewPlaylistMirrorModule creates a new instance of PlaylistMirrorModule.
+// It initializes the module with the provided window, configuration manager, database manager,
+// and error handler, sets up the UI components, and loads any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized PlaylistMirrorModule instance
+func NewPlaylistMirrorModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *PlaylistMirrorModule {
+	m := &PlaylistMirrorModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *PlaylistMirrorModule) GetName() string {
+	return locales.Translate("playlistmirror.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *PlaylistMirrorModule) GetConfigName() string {
+	return common.ModuleKeyPlaylistMirror
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *PlaylistMirrorModule) GetIcon() fyne.Resource {
+	return theme.ContentCopyIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// containing the playlist selectors, the twin folder field and the submit button.
+func (m *PlaylistMirrorModule) GetModuleContent() fyne.CanvasObject {
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: locales.Translate("playlistmirror.label.source"), Widget: m.sourceSelect},
+			{Text: locales.Translate("playlistmirror.label.target"), Widget: m.targetSelect},
+			{Text: locales.Translate("playlistmirror.label.twins"), Widget: m.twinFolderField},
+		},
+	}
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("playlistmirror.label.info")),
+		widget.NewSeparator(),
+		form,
+		m.removeStaleCheck,
+	)
+
+	// Add submit button with right alignment
+	buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.submitBtn)
+	moduleContent.Add(buttonBox)
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content and loads the playlists from the database.
+// If the database is not available, the controls are disabled.
+func (m *PlaylistMirrorModule) GetContent() fyne.CanvasObject {
+	if m.dbMgr == nil || m.dbMgr.GetDatabasePath() == "" {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "PathToDatabaseCheck",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		common.DisableModuleControls(m.sourceSelect, m.targetSelect, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	if err := m.loadPlaylists(); err != nil {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "LoadDataFromDatabase",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		common.DisableModuleControls(m.sourceSelect, m.targetSelect, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	m.submitBtn.Enable()
+
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *PlaylistMirrorModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyPlaylistMirror, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to PlaylistMirror specific config
+	if cfg, ok := config.(common.PlaylistMirrorCfg); ok {
+		m.sourceID = cfg.SourcePlaylist.Value
+		m.targetID = cfg.TargetFolder.Value
+		m.twinFolderEntry.SetText(cfg.TwinFolder.Value)
+		m.removeStaleCheck.SetChecked(cfg.RemoveStale.Value == "true")
+
+		// Restore the selections if playlists are already loaded
+		if option := common.PlaylistOptionByID(m.playlists, m.sourceSelect, m.sourceID); option != "" {
+			m.sourceSelect.SetSelected(option)
+		}
+		if option := common.PlaylistOptionByID(m.folders, m.targetSelect, m.targetID); option != "" {
+			m.targetSelect.SetSelected(option)
+		}
+	}
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *PlaylistMirrorModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultPlaylistMirrorCfg()
+
+	// Update only the values from current UI state
+	cfg.SourcePlaylist.Value = m.sourceID
+	cfg.TargetFolder.Value = m.targetID
+	cfg.TwinFolder.Value = m.twinFolderEntry.Text
+	cfg.RemoveStale.Value = fmt.Sprintf("%t", m.removeStaleCheck.Checked)
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyPlaylistMirror, m.GetConfigName(), cfg)
+}
+
+// initializeUI sets up the user interface components.
+// Both selects stay disabled until the playlists are loaded from the database.
+func (m *PlaylistMirrorModule) initializeUI() {
+	// Any folder or playlist can be mirrored
+	m.sourceSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
+	m.sourceSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
+		m.sourceID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.sourceSelect); ok {
+			m.sourceID = p.ID
+		}
+		m.SaveCfg()
+	})
+
+	// Only folders are offered as the target
+	m.targetSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
+	m.targetSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
+		m.targetID = ""
+		if p, ok := common.SelectedPlaylist(m.folders, m.targetSelect); ok {
+			m.targetID = p.ID
+		}
+		m.SaveCfg()
+	})
+
+	m.twinFolderField = common.CreateFolderSelectionField(
+		locales.Translate("common.entry.placeholderpath"),
+		nil,
+		func(path string) {
+			m.SaveCfg()
+		},
+	)
+	// Extract the entry widget from the container for direct access
+	if container, ok := m.twinFolderField.(*fyne.Container); ok && len(container.Objects) > 0 {
+		if entry, ok := container.Objects[0].(*widget.Entry); ok {
+			m.twinFolderEntry = entry
+		}
+	}
+
+	m.removeStaleCheck = common.CreateCheckbox(locales.Translate("playlistmirror.chkbox.removestale"), m.CreateBoolChangeHandler(func() {
+		m.SaveCfg()
+	}))
+
+	m.submitBtn = common.CreateDisabledSubmitButton(locales.Translate("playlistmirror.button.start"), func() {
+		go m.Start()
+	})
+}
+
+// loadPlaylists loads the playlist tree and fills both selects.
+//
+// Returns:
+//   - An error if the playlists cannot be loaded
+func (m *PlaylistMirrorModule) loadPlaylists() error {
+	err := m.dbMgr.Connect()
+	if err != nil {
+		return err // DBMgr.Connect() is expected to return a localized error.
+	}
+	defer m.dbMgr.Finalize()
+
+	playlists, err := m.dbMgr.GetPlaylists()
+	if err != nil {
+		return err
+	}
+
+	m.playlists = playlists
+	m.folders = nil
+	for _, playlist := range playlists {
+		if playlist.IsFolder() {
+			m.folders = append(m.folders, playlist)
+		}
+	}
+
+	m.sourceSelect.Options = common.PlaylistSelectOptions(m.playlists)
+	m.targetSelect.Options = common.PlaylistSelectOptions(m.folders)
+
+	common.SetPlaylistSelectState(m.sourceSelect, true, common.PlaylistOptionByID(m.playlists, m.sourceSelect, m.sourceID))
+	common.SetPlaylistSelectState(m.targetSelect, true, common.PlaylistOptionByID(m.folders, m.targetSelect, m.targetID))
+
+	return nil
+}
+
+// Start performs the necessary steps before starting the main process.
+// It validates the inputs, which includes creating a database backup,
+// displays a progress dialog and starts the mirroring in a goroutine.
+func (m *PlaylistMirrorModule) Start() {
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	// Show the progress dialog
+	m.ShowProgressDialog(locales.Translate("playlistmirror.dialog.header"))
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "Playlist Mirror",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processMirror()
+	}()
+}
+
+// processMirror prepares all playlist changes, shows them for review and writes them on approval.
+func (m *PlaylistMirrorModule) processMirror() {
+	defer m.dbMgr.Finalize()
+
+	m.StartProcessing(locales.Translate("common.status.playlistload"))
+
+	tree, err := m.dbMgr.GetPlaylistTree()
+	if err != nil {
+		m.showError("Load Playlists", err)
+		return
+	}
+
+	source := tree.Node(m.sourceID)
+	target := tree.Node(m.targetID)
+	if source == nil || target == nil || !target.IsFolder() {
+		m.showError("Playlist Selection", errors.New(locales.Translate("playlistmirror.err.selection")))
+		return
+	}
+
+	// The target must not lie inside the mirrored tree, the mirror would contain itself
+	for node := target; node != nil; node = node.Parent {
+		if node == source {
+			m.showError("Playlist Selection", errors.New(locales.Translate("playlistmirror.err.nested")))
+			return
+		}
+	}
+
+	m.UpdateProgressStatus(0.1, locales.Translate("playlistmirror.status.twins"))
+	twins, err := m.loadTwins()
+	if err != nil {
+		m.showError("Load Twins", err)
+		return
+	}
+
+	// A folder is mirrored by its content, a single playlist is mirrored as a playlist of the same name
+	sources := source.Children
+	if !source.IsFolder() {
+		sources = []*common.PlaylistNode{source}
+	}
+
+	cs := common.NewChangeset(m.dbMgr)
+	stats := &mirrorStats{}
+	m.UpdateProgressStatus(0.3, locales.Translate("playlistmirror.status.preparing"))
+	// Only a mirrored folder owns all children of the target, a single playlist leaves its siblings alone
+	if err := m.mirrorChildren(cs, stats, sources, target.ID, target.Path, target.Children, twins, source.IsFolder()); err != nil {
+		if errors.Is(err, common.ErrCancelled) {
+			m.HandleProcessCancellation("playlistmirror.status.stopped")
+			return
+		}
+		m.showError("Prepare Playlists", err)
+		return
+	}
+
+	if stats.missingTwins > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("playlistmirror.status.missingtwins"), stats.missingTwins))
+	}
+	m.AddInfoMessage(fmt.Sprintf(locales.Translate("playlistmirror.status.prepared"),
+		stats.created, stats.removed, stats.added, stats.dropped, stats.moved))
+
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("playlistmirror.dialog.header"), cs, func(applied int) {
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("playlistmirror.status.done"), applied))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("playlistmirror.status.done"), applied))
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
+
+// mirrorChildren mirrors the source nodes into the children of a target folder.
+// Existing target children are matched by name and kind, missing ones are created,
+// and all mirrored children are ordered like their sources. Smart playlists of the target are never
+// matched, entries cannot be added to them. Children without a source are removed when enabled and
+// prune is set, otherwise they are kept after the mirrored ones.
+func (m *PlaylistMirrorModule) mirrorChildren(cs *common.Changeset, stats *mirrorStats, sources []*common.PlaylistNode,
+	parentID, parentPath string, existing []*common.PlaylistNode, twins map[string]string, prune bool) error {
+	used := make(map[*common.PlaylistNode]bool)
+	seq := 0
+
+	for _, source := range sources {
+		if m.IsCancelled() {
+			return common.ErrCancelled
+		}
+		seq++
+
+		var match *common.PlaylistNode
+		for _, candidate := range existing {
+			if !used[candidate] && !candidate.IsSmart() && candidate.IsFolder() == source.IsFolder() && strings.EqualFold(candidate.Name, source.Name) {
+				match = candidate
+				break
+			}
+		}
+
+		path := parentPath + common.PlaylistPathSeparator + source.Name
+		var id string
+		var children []*common.PlaylistNode
+		if match != nil {
+			used[match] = true
+			id = match.ID
+			children = match.Children
+			if _, err := common.SetPlaylistSeq(cs, id, path, seq); err != nil {
+				return err
+			}
+		} else {
+			playlistType := common.PlaylistTypePlaylist
+			if source.IsFolder() {
+				playlistType = common.PlaylistTypeFolder
+			}
+			newID, err := common.CreatePlaylist(cs, parentID, source.Name, playlistType, seq)
+			if err != nil {
+				return err
+			}
+			id = newID
+			stats.created++
+		}
+
+		if source.IsFolder() {
+			if err := m.mirrorChildren(cs, stats, source.Children, id, path, children, twins, true); err != nil {
+				return err
+			}
+			continue
+		}
+
+		contentIDs, err := m.mirroredTracks(source, twins, stats)
+		if err != nil {
+			return err
+		}
+		added, dropped, moved, err := common.SyncPlaylistEntries(cs, id, path, contentIDs)
+		if err != nil {
+			return err
+		}
+		stats.added += added
+		stats.dropped += dropped
+		stats.moved += moved
+	}
+
+	// Children of the target without a source
+	for _, child := range existing {
+		if used[child] {
+			continue
+		}
+		if prune && m.removeStaleCheck.Checked {
+			if err := common.DeletePlaylist(cs, child); err != nil {
+				return err
+			}
+			stats.removed++
+			continue
+		}
+		seq++
+		if _, err := common.SetPlaylistSeq(cs, child.ID, child.Path, seq); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mirroredTracks returns the tracks of the mirrored playlist in source order.
+// FLAC tracks are replaced by their MP3 twins, other tracks are used as they are.
+func (m *PlaylistMirrorModule) mirroredTracks(source *common.PlaylistNode, twins map[string]string, stats *mirrorStats) ([]string, error) {
+	var paths, ids []string
+	if source.IsSmart() {
+		tracks, err := m.dbMgr.GetTracksBasedOnPlaylist(source.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range tracks {
+			ids = append(ids, track.ID)
+			paths = append(paths, track.FolderPath)
+		}
+	} else {
+		entries, err := m.dbMgr.GetPlaylistEntries(source.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.FolderPath == "" {
+				continue // Entry of a deleted track
+			}
+			ids = append(ids, entry.ContentID)
+			paths = append(paths, entry.FolderPath)
+		}
+	}
+
+	contentIDs := make([]string, 0, len(ids))
+	for i, path := range paths {
+		if !strings.EqualFold(filepath.Ext(path), common.ExtensionFLAC) {
+			contentIDs = append(contentIDs, ids[i])
+			continue
+		}
+		twinID, ok := twins[twinKey(path)]
+		if !ok {
+			m.Logger.Warning(locales.Translate("playlistmirror.log.notwin"), path)
+			stats.missingTwins++
+			continue
+		}
+		contentIDs = append(contentIDs, twinID)
+	}
+
+	return contentIDs, nil
+}
+
+// loadTwins collects the MP3 tracks in the twin folder by their file name without extension.
+// If several files share a name, the first one is used and the others are logged.
+func (m *PlaylistMirrorModule) loadTwins() (map[string]string, error) {
+	tracks, err := m.dbMgr.GetTracksBasedOnFolder(m.twinFolderEntry.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	twins := make(map[string]string)
+	for _, track := range tracks {
+		if !strings.EqualFold(filepath.Ext(track.FolderPath), common.ExtensionMP3) {
+			continue
+		}
+		key := twinKey(track.FolderPath)
+		if _, exists := twins[key]; exists {
+			m.Logger.Warning(locales.Translate("playlistmirror.log.duplicatetwin"), track.FolderPath)
+			continue
+		}
+		twins[key] = track.ID
+	}
+
+	return twins, nil
+}
+
+// twinKey returns the key used to match a track with its twin: the lower-case file name without extension.
+func twinKey(path string) string {
+	name := filepath.Base(filepath.FromSlash(path))
+	return strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+}
+
+// showError closes the progress dialog and reports a critical error of the mirroring.
+func (m *PlaylistMirrorModule) showError(operation string, err error) {
+	m.CloseProgressDialog()
+	context := &common.ErrorContext{
+		Module:      m.GetName(),
+		Operation:   operation,
+		Severity:    common.SeverityCritical,
+		Recoverable: false,
+	}
+	m.ErrorHandler.ShowStandardError(err, context)
+	m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+}
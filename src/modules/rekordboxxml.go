@@ -0,0 +1,445 @@
+// modules/rekordboxxml.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module exports the collection or selected playlists to the rekordbox XML library format
+// and imports cues and metadata from such a file back into matching tracks.
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	nativedialog "github.com/sqweek/dialog"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// RekordboxXmlModule exchanges the library with other tools through rekordbox.xml files.
+type RekordboxXmlModule struct {
+	// ModuleBase provides common module functionality like error handling and UI components
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// exportAllCheck exports the whole collection with all playlists
+	exportAllCheck *widget.Check
+	// playlistSelect selects the folder or playlist to export
+	playlistSelect *widget.Select
+	// exportBtn starts the export
+	exportBtn *widget.Button
+	// importFileEntry holds the path of the XML file to import
+	importFileEntry *widget.Entry
+	// importFileField is the file selection field containing importFileEntry
+	importFileField fyne.CanvasObject
+	// importCuesCheck imports memory cues, hot cues and loops
+	importCuesCheck *widget.Check
+	// importMetadataCheck imports track metadata
+	importMetadataCheck *widget.Check
+	// submitBtn starts the import
+	submitBtn *widget.Button
+	// playlists holds all folders and playlists shown in playlistSelect
+	playlists []common.PlaylistItem
+	// playlistID keeps the selected ID until the playlists are loaded
+	playlistID string
+}
+
+// NewRekordboxXmlModule creates a new instance of RekordboxXmlModule.
+// It initializes the module with the provided window, configuration manager, database manager,
+// and error handler, sets up the UI components, and loads any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized RekordboxXmlModule instance
+func NewRekordboxXmlModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *RekordboxXmlModule {
+	m := &RekordboxXmlModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *RekordboxXmlModule) GetName() string {
+	return locales.Translate("rekordboxxml.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *RekordboxXmlModule) GetConfigName() string {
+	return common.ModuleKeyRekordboxXml
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *RekordboxXmlModule) GetIcon() fyne.Resource {
+	return theme.DocumentIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// with the export section and the import section.
+func (m *RekordboxXmlModule) GetModuleContent() fyne.CanvasObject {
+	exportForm := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: locales.Translate("rekordboxxml.label.playlist"), Widget: m.playlistSelect},
+		},
+	}
+	importForm := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: locales.Translate("rekordboxxml.label.file"), Widget: m.importFileField},
+		},
+	}
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("rekordboxxml.label.info")),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(locales.Translate("rekordboxxml.label.export"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		m.exportAllCheck,
+		exportForm,
+		container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.exportBtn),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(locales.Translate("rekordboxxml.label.import"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		importForm,
+		m.importCuesCheck,
+		m.importMetadataCheck,
+		container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.submitBtn),
+	)
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content and loads the playlists from the database.
+// If the database is not available, the controls are disabled.
+func (m *RekordboxXmlModule) GetContent() fyne.CanvasObject {
+	if m.dbMgr == nil || m.dbMgr.GetDatabasePath() == "" {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "PathToDatabaseCheck",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		common.DisableModuleControls(m.playlistSelect, m.exportBtn, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	if err := m.loadPlaylists(); err != nil {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "LoadDataFromDatabase",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		common.DisableModuleControls(m.playlistSelect, m.exportBtn, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	m.exportBtn.Enable()
+	m.submitBtn.Enable()
+
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *RekordboxXmlModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyRekordboxXml, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to RekordboxXml specific config
+	if cfg, ok := config.(common.RekordboxXmlCfg); ok {
+		m.exportAllCheck.SetChecked(cfg.ExportAll.Value == "true")
+		m.playlistID = cfg.ExportPlaylist.Value
+		m.importFileEntry.SetText(cfg.ImportFile.Value)
+		m.importCuesCheck.SetChecked(cfg.ImportCues.Value == "true")
+		m.importMetadataCheck.SetChecked(cfg.ImportMetadata.Value == "true")
+
+		// Restore the selection if playlists are already loaded
+		if option := common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.playlistID); option != "" {
+			m.playlistSelect.SetSelected(option)
+		}
+		m.updatePlaylistSelectState()
+	}
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *RekordboxXmlModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultRekordboxXmlCfg()
+
+	// Update only the values from current UI state
+	cfg.ExportAll.Value = fmt.Sprintf("%t", m.exportAllCheck.Checked)
+	cfg.ExportPlaylist.Value = m.playlistID
+	cfg.ImportFile.Value = m.importFileEntry.Text
+	cfg.ImportCues.Value = fmt.Sprintf("%t", m.importCuesCheck.Checked)
+	cfg.ImportMetadata.Value = fmt.Sprintf("%t", m.importMetadataCheck.Checked)
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyRekordboxXml, m.GetConfigName(), cfg)
+}
+
+// initializeUI sets up the user interface components.
+// The playlist select and both buttons stay disabled until the playlists are loaded from the database.
+func (m *RekordboxXmlModule) initializeUI() {
+	m.exportAllCheck = common.CreateCheckbox(locales.Translate("rekordboxxml.chkbox.exportall"), m.CreateBoolChangeHandler(func() {
+		m.updatePlaylistSelectState()
+		m.SaveCfg()
+	}))
+
+	m.playlistSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
+	m.playlistSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
+		m.playlistID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.playlistSelect); ok {
+			m.playlistID = p.ID
+		}
+		m.SaveCfg()
+	})
+
+	m.exportBtn = common.CreateDisabledSubmitButton(locales.Translate("rekordboxxml.button.export"), func() {
+		m.exportXML()
+	})
+
+	m.importFileEntry = widget.NewEntry()
+	m.importFileField = common.CreateFileSelectionField(
+		locales.Translate("rekordboxxml.label.file"),
+		m.importFileEntry,
+		locales.Translate("rekordboxxml.filter.xml"),
+		[]string{"xml"},
+		func(path string) {
+			m.SaveCfg()
+		},
+	)
+
+	m.importCuesCheck = common.CreateCheckbox(locales.Translate("rekordboxxml.chkbox.cues"), m.CreateBoolChangeHandler(func() {
+		m.SaveCfg()
+	}))
+	m.importMetadataCheck = common.CreateCheckbox(locales.Translate("rekordboxxml.chkbox.metadata"), m.CreateBoolChangeHandler(func() {
+		m.SaveCfg()
+	}))
+
+	m.submitBtn = common.CreateDisabledSubmitButton(locales.Translate("rekordboxxml.button.import"), func() {
+		go m.Start()
+	})
+}
+
+// updatePlaylistSelectState enables the playlist select only when a partial export is chosen
+// and the playlists are loaded.
+func (m *RekordboxXmlModule) updatePlaylistSelectState() {
+	if m.exportAllCheck.Checked || len(m.playlists) == 0 {
+		m.playlistSelect.Disable()
+	} else {
+		m.playlistSelect.Enable()
+	}
+}
+
+// loadPlaylists loads the playlist tree and fills the playlist select.
+//
+// Returns:
+//   - An error if the playlists cannot be loaded
+func (m *RekordboxXmlModule) loadPlaylists() error {
+	err := m.dbMgr.Connect()
+	if err != nil {
+		return err // DBMgr.Connect() is expected to return a localized error.
+	}
+	defer m.dbMgr.Finalize()
+
+	playlists, err := m.dbMgr.GetPlaylists()
+	if err != nil {
+		return err
+	}
+
+	m.playlists = playlists
+	m.playlistSelect.Options = common.PlaylistSelectOptions(m.playlists)
+	common.SetPlaylistSelectState(m.playlistSelect, true, common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.playlistID))
+	m.updatePlaylistSelectState()
+
+	return nil
+}
+
+// exportXML asks for the target file and writes the collection or the selected playlist to it.
+// The export only reads the database, so no backup is created.
+func (m *RekordboxXmlModule) exportXML() {
+	playlistID := ""
+	if !m.exportAllCheck.Checked {
+		if m.playlistID == "" {
+			context := &common.ErrorContext{
+				Module:      m.GetName(),
+				Operation:   "XML Export",
+				Severity:    common.SeverityWarning,
+				Recoverable: true,
+			}
+			m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("validator.err.noplaylist")), context)
+			return
+		}
+		playlistID = m.playlistID
+	}
+
+	path, err := nativedialog.File().
+		Filter(locales.Translate("rekordboxxml.filter.xml"), "xml").
+		Title(locales.Translate("rekordboxxml.button.export")).
+		Save()
+	if err != nil || path == "" {
+		return
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".xml") {
+		path += ".xml"
+	}
+
+	go func() {
+		m.ClearStatusMessages()
+		m.ShowProgressDialog(locales.Translate("rekordboxxml.dialog.export"))
+		m.StartProcessing(locales.Translate("rekordboxxml.status.reading"))
+
+		defer m.dbMgr.Finalize()
+
+		library, err := common.BuildRekordboxXML(m.dbMgr, playlistID)
+		if err == nil {
+			err = common.WriteRekordboxXML(path, library)
+		}
+		if err != nil {
+			m.showError("XML Export", err)
+			return
+		}
+
+		message := fmt.Sprintf(locales.Translate("rekordboxxml.status.exported"), library.Collection.Entries, filepath.Base(path))
+		m.CompleteProcessing(message)
+		m.AddInfoMessage(message)
+		m.CompleteProgressDialog()
+	}()
+}
+
+// Start performs the necessary steps before starting the import.
+// It validates the inputs, which includes creating a database backup,
+// displays a progress dialog and prepares the import in a goroutine.
+func (m *RekordboxXmlModule) Start() {
+	if !m.importCuesCheck.Checked && !m.importMetadataCheck.Checked {
+		context := &common.ErrorContext{
+			Module:      m.GetName(),
+			Operation:   "XML Import",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("rekordboxxml.err.nothing")), context)
+		return
+	}
+
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	// Show the progress dialog
+	m.ShowProgressDialog(locales.Translate("rekordboxxml.dialog.import"))
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "XML Import",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processImport()
+	}()
+}
+
+// processImport prepares the import of the XML file, shows the changes for review and writes them on approval.
+// The review dialog is the diff report of the import; discarding it leaves the database untouched.
+func (m *RekordboxXmlModule) processImport() {
+	defer m.dbMgr.Finalize()
+
+	m.StartProcessing(locales.Translate("rekordboxxml.status.reading"))
+
+	library, err := common.ReadRekordboxXML(m.importFileEntry.Text)
+	if err != nil {
+		m.showError("XML Import", err)
+		return
+	}
+
+	cs := common.NewChangeset(m.dbMgr)
+	options := common.XMLImportOptions{Cues: m.importCuesCheck.Checked, Metadata: m.importMetadataCheck.Checked}
+	summary, err := common.PrepareXMLImport(cs, library, options, m.IsCancelled)
+	if errors.Is(err, common.ErrCancelled) {
+		m.HandleProcessCancellation("rekordboxxml.status.stopped")
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+	if err != nil {
+		m.showError("XML Import", err)
+		return
+	}
+
+	m.AddInfoMessage(fmt.Sprintf(locales.Translate("rekordboxxml.status.matched"),
+		summary.Tracks, summary.Matched, summary.TracksChanged, summary.CuesAdded, summary.CuesReplaced))
+	if len(summary.Unmatched) > 0 {
+		for _, location := range summary.Unmatched {
+			m.Logger.Warning(locales.Translate("rekordboxxml.log.unmatched"), location)
+		}
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("rekordboxxml.status.unmatched"), len(summary.Unmatched)))
+	}
+
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("rekordboxxml.dialog.import"), cs, func(applied int) {
+		m.CompleteProcessing(fmt.Sprintf(locales.Translate("rekordboxxml.status.done"), applied))
+		m.AddInfoMessage(fmt.Sprintf(locales.Translate("rekordboxxml.status.done"), applied))
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
+
+// showError closes the progress dialog and reports a critical error.
+func (m *RekordboxXmlModule) showError(operation string, err error) {
+	m.CloseProgressDialog()
+	context := &common.ErrorContext{
+		Module:      m.GetName(),
+		Operation:   operation,
+		Severity:    common.SeverityCritical,
+		Recoverable: false,
+	}
+	m.ErrorHandler.ShowStandardError(err, context)
+	m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+}
@@ -0,0 +1,462 @@
+// modules/tagwriter.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module writes metadata curated in rekordbox (ratings, colors, comments, My Tags, genres, titles and more)
+// from the database back into the tags of FLAC and MP3 files of a folder or playlist.
+
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// TagWriterModule exports selected database fields into the tags of audio files.
+// The tag changes are compared with the current tags and shown for review before any file is written.
+type TagWriterModule struct {
+	// ModuleBase provides common module functionality like error handling and UI components
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// sourceType selects whether the tracks are taken from a folder or a playlist
+	sourceType *widget.Select
+	// folderEntry holds the path of the source folder
+	folderEntry *widget.Entry
+	// folderField is the folder selection field containing folderEntry
+	folderField fyne.CanvasObject
+	// playlistSelect selects the source playlist
+	playlistSelect *widget.Select
+	// fieldChecks select the fields to export, keyed by field name
+	fieldChecks map[string]*widget.Check
+	// submitBtn starts the export
+	submitBtn *widget.Button
+	// playlists holds all folders and playlists shown in playlistSelect
+	playlists []common.PlaylistItem
+	// playlistID keeps the selected ID until the playlists are loaded
+	playlistID string
+}
+
+// NewTagWriterModule creates a new instance of TagWriterModule.
+// It initializes the module with the provided window, configuration manager, database manager,
+// and error handler, sets up the UI components, and loads any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized TagWriterModule instance
+func NewTagWriterModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *TagWriterModule {
+	m := &TagWriterModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *TagWriterModule) GetName() string {
+	return locales.Translate("tagwriter.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *TagWriterModule) GetConfigName() string {
+	return common.ModuleKeyTagWriter
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *TagWriterModule) GetIcon() fyne.Resource {
+	return theme.DocumentSaveIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// containing the source selection, the field selection and the submit button.
+func (m *TagWriterModule) GetModuleContent() fyne.CanvasObject {
+	fieldsGrid := container.NewGridWithColumns(4)
+	for _, field := range common.TagExportFields {
+		fieldsGrid.Add(m.fieldChecks[field])
+	}
+
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{
+				Text: locales.Translate("tagwriter.label.source"),
+				Widget: container.NewBorder(
+					nil, nil,
+					m.sourceType,
+					nil,
+					container.NewStack(
+						m.folderField,
+						m.playlistSelect,
+					),
+				),
+			},
+		},
+	}
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("tagwriter.label.info")),
+		widget.NewSeparator(),
+		form,
+		widget.NewLabel(locales.Translate("tagwriter.label.fields")),
+		fieldsGrid,
+	)
+
+	// Add submit button with right alignment
+	buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.submitBtn)
+	moduleContent.Add(buttonBox)
+
+	m.updateSourceVisibility()
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content and loads the playlists from the database.
+// If the database is not available, the controls are disabled.
+func (m *TagWriterModule) GetContent() fyne.CanvasObject {
+	if m.dbMgr == nil || m.dbMgr.GetDatabasePath() == "" {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "PathToDatabaseCheck",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		common.DisableModuleControls(m.playlistSelect, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	if err := m.loadPlaylists(); err != nil {
+		context := &common.ErrorContext{
+			Module:      m.GetConfigName(),
+			Operation:   "LoadDataFromDatabase",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		common.DisableModuleControls(m.playlistSelect, m.submitBtn)
+		return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+	}
+
+	m.submitBtn.Enable()
+
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *TagWriterModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyTagWriter, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to TagWriter specific config
+	if cfg, ok := config.(common.TagWriterCfg); ok {
+		m.sourceType.SetSelected(locales.Translate("tagwriter.dropdown." + cfg.SourceType.Value))
+		m.folderEntry.SetText(cfg.Folder.Value)
+		m.playlistID = cfg.PlaylistID.Value
+
+		selected := strings.Split(cfg.Fields.Value, ",")
+		for field, check := range m.fieldChecks {
+			check.SetChecked(slices.Contains(selected, field))
+		}
+
+		// Restore the selection if playlists are already loaded
+		if option := common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.playlistID); option != "" {
+			m.playlistSelect.SetSelected(option)
+		}
+	}
+
+	m.updateSourceVisibility()
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *TagWriterModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultTagWriterCfg()
+
+	// Update only the values from current UI state
+	cfg.SourceType.Value = m.selectedSourceType()
+	cfg.Folder.Value = m.folderEntry.Text
+	cfg.PlaylistID.Value = m.playlistID
+	cfg.Fields.Value = strings.Join(m.selectedFields(), ",")
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyTagWriter, m.GetConfigName(), cfg)
+}
+
+// initializeUI sets up the user interface components.
+// The playlist select and the submit button stay disabled until the playlists are loaded from the database.
+func (m *TagWriterModule) initializeUI() {
+	m.sourceType = widget.NewSelect([]string{
+		locales.Translate("tagwriter.dropdown." + common.ContentTypeFolder),
+		locales.Translate("tagwriter.dropdown." + common.ContentTypePlaylist),
+	}, nil)
+	m.sourceType.OnChanged = m.CreateSelectionChangeHandler(func() {
+		m.updateSourceVisibility()
+		m.SaveCfg()
+	})
+
+	m.folderEntry = widget.NewEntry()
+	m.folderEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	m.folderField = common.CreateFolderSelectionField(
+		locales.Translate("common.entry.placeholderpath"),
+		m.folderEntry,
+		m.CreateChangeHandler(func() {
+			m.SaveCfg()
+		}),
+	)
+
+	m.playlistSelect = common.CreatePlaylistSelect(nil, "common.select.plsplacehldrinact")
+	m.playlistSelect.OnChanged = m.CreateSelectionChangeHandler(func() {
+		// Folders of playlists cannot be used as a source of tracks
+		if !common.GuardPlaylistSelection(m.playlists, m.playlistSelect, m.playlistID) {
+			return
+		}
+		m.playlistID = ""
+		if p, ok := common.SelectedPlaylist(m.playlists, m.playlistSelect); ok {
+			m.playlistID = p.ID
+		}
+		m.SaveCfg()
+	})
+
+	m.fieldChecks = make(map[string]*widget.Check, len(common.TagExportFields))
+	for _, field := range common.TagExportFields {
+		m.fieldChecks[field] = common.CreateCheckbox(locales.Translate("tagwriter.field."+field), m.CreateBoolChangeHandler(func() {
+			m.SaveCfg()
+		}))
+	}
+
+	m.submitBtn = common.CreateDisabledSubmitButton(locales.Translate("tagwriter.button.write"), func() {
+		go m.Start()
+	})
+}
+
+// selectedSourceType returns the selected source type, common.ContentTypeFolder or common.ContentTypePlaylist.
+func (m *TagWriterModule) selectedSourceType() string {
+	if m.sourceType.Selected == locales.Translate("tagwriter.dropdown."+common.ContentTypePlaylist) {
+		return common.ContentTypePlaylist
+	}
+	return common.ContentTypeFolder
+}
+
+// selectedFields returns the checked fields in the order of common.TagExportFields.
+func (m *TagWriterModule) selectedFields() []string {
+	var fields []string
+	for _, field := range common.TagExportFields {
+		if m.fieldChecks[field].Checked {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// updateSourceVisibility shows the folder field or the playlist select according to the source type.
+func (m *TagWriterModule) updateSourceVisibility() {
+	if m.selectedSourceType() == common.ContentTypePlaylist {
+		m.folderField.Hide()
+		m.playlistSelect.Show()
+	} else {
+		m.playlistSelect.Hide()
+		m.folderField.Show()
+	}
+}
+
+// loadPlaylists loads the playlist tree and fills the playlist select.
+//
+// Returns:
+//   - An error if the playlists cannot be loaded
+func (m *TagWriterModule) loadPlaylists() error {
+	err := m.dbMgr.Connect()
+	if err != nil {
+		return err // DBMgr.Connect() is expected to return a localized error.
+	}
+	defer m.dbMgr.Finalize()
+
+	playlists, err := m.dbMgr.GetPlaylists()
+	if err != nil {
+		return err
+	}
+
+	m.playlists = playlists
+	m.playlistSelect.Options = common.PlaylistSelectOptions(m.playlists)
+	common.SetPlaylistSelectState(m.playlistSelect, true, common.PlaylistOptionByID(m.playlists, m.playlistSelect, m.playlistID))
+
+	return nil
+}
+
+// Start performs the necessary steps before starting the export.
+// It validates the inputs, displays a progress dialog and compares the tags in a goroutine.
+func (m *TagWriterModule) Start() {
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	// Show the progress dialog
+	m.ShowProgressDialog(locales.Translate("tagwriter.dialog.header"))
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "Tag Export",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processExport()
+	}()
+}
+
+// processExport reads the database values of the source tracks, compares them with the file tags
+// and shows the tag changes for review. The tags are written only after the user approves them.
+func (m *TagWriterModule) processExport() {
+	defer m.dbMgr.Finalize()
+
+	m.StartProcessing(locales.Translate("tagwriter.status.reading"))
+
+	mapping, err := common.LoadMetadataMap()
+	if err != nil {
+		m.showError("Tag Export", err)
+		return
+	}
+
+	var tracks []common.TrackItem
+	if m.selectedSourceType() == common.ContentTypePlaylist {
+		tracks, err = m.dbMgr.GetTracksBasedOnPlaylist(m.playlistID)
+	} else {
+		tracks, err = m.dbMgr.GetTracksBasedOnFolder(m.folderEntry.Text)
+	}
+	if err != nil {
+		m.showError("Tag Export", err)
+		return
+	}
+
+	summary, err := common.PrepareTagExport(m.dbMgr, tracks, m.selectedFields(), mapping, m.IsCancelled)
+	if errors.Is(err, common.ErrCancelled) {
+		m.HandleProcessCancellation("tagwriter.status.stopped", 0, summary.Tracks)
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+	if err != nil {
+		m.showError("Tag Export", err)
+		return
+	}
+
+	m.AddInfoMessage(fmt.Sprintf(locales.Translate("tagwriter.status.compared"), summary.Tracks, len(summary.Items), summary.Unchanged))
+	if summary.Unsupported > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("tagwriter.status.unsupported"), summary.Unsupported))
+	}
+	if len(summary.Missing) > 0 {
+		for _, file := range summary.Missing {
+			m.Logger.Warning(locales.Translate("tagwriter.log.missing"), file)
+		}
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("tagwriter.status.missing"), len(summary.Missing)))
+	}
+	if len(summary.Failed) > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("tagwriter.status.unreadable"), len(summary.Failed)))
+	}
+
+	if len(summary.Items) == 0 {
+		m.CompleteProcessing(locales.Translate("tagwriter.status.nochanges"))
+		m.AddInfoMessage(locales.Translate("tagwriter.status.nochanges"))
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+
+	// Let the user review the tag changes and write them on approval
+	m.CloseProgressDialog()
+	common.ShowTagExportReviewDialog(m.Window, summary.Items,
+		func() {
+			m.ShowProgressDialog(locales.Translate("tagwriter.dialog.header"))
+			m.StartProcessing(locales.Translate("tagwriter.status.writing"))
+			go m.writeTags(summary.Items, mapping)
+		},
+		func() {
+			m.AddInfoMessage(locales.Translate("tagwriter.status.discarded"))
+		})
+}
+
+// writeTags writes the reviewed tag changes into the files and reports the result.
+// Files which cannot be written are logged and reported, the remaining files are still written.
+func (m *TagWriterModule) writeTags(items []common.TagExportItem, mapping *common.MetadataMap) {
+	written, failed, err := common.ApplyTagExport(items, mapping, m.IsCancelled, func(done, total int) {
+		m.UpdateProcessingProgress(done, total, fmt.Sprintf(locales.Translate("common.status.progress"), done, total))
+	})
+	for _, writeErr := range failed {
+		m.Logger.Error("%v", writeErr)
+	}
+	if len(failed) > 0 {
+		m.AddWarningMessage(fmt.Sprintf(locales.Translate("tagwriter.status.writefailed"), len(failed)))
+	}
+	if errors.Is(err, common.ErrCancelled) {
+		m.HandleProcessCancellation("tagwriter.status.stopped", written, len(items))
+		common.UpdateButtonToCompleted(m.submitBtn)
+		return
+	}
+
+	message := fmt.Sprintf(locales.Translate("tagwriter.status.done"), written, len(items))
+	m.CompleteProcessing(message)
+	m.AddInfoMessage(message)
+	m.CompleteProgressDialog()
+	common.UpdateButtonToCompleted(m.submitBtn)
+}
+
+// showError closes the progress dialog and reports a critical error.
+func (m *TagWriterModule) showError(operation string, err error) {
+	m.CloseProgressDialog()
+	context := &common.ErrorContext{
+		Module:      m.GetName(),
+		Operation:   operation,
+		Severity:    common.SeverityCritical,
+		Recoverable: false,
+	}
+	m.ErrorHandler.ShowStandardError(err, context)
+	m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+}
@@ -0,0 +1,282 @@
+// modules/trackimporter.go
+
+// Package modules provides functionality for different modules in the MetaRekordFixer application.
+// Each module handles a specific task related to DJ database management and music file operations.
+
+// This module registers new audio files (e.g. MP3 twins made by FormatConverter) in the rekordbox collection,
+// so they can be used as DataDuplicator targets without importing them in rekordbox first.
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+)
+
+// TrackImporterModule imports audio files of a folder that are not in the rekordbox collection yet.
+type TrackImporterModule struct {
+	// ModuleBase is the base struct for all modules, which contains the module's window,
+	// error handler, and configuration manager.
+	*common.ModuleBase
+	// dbMgr handles database operations
+	dbMgr *common.DBManager
+	// sourceFolderEntry is the entry field for source folder path
+	sourceFolderEntry *widget.Entry
+	// folderSelectionField contains the complete folder selection UI component
+	folderSelectionField fyne.CanvasObject
+	// recursiveCheck determines if the import should process subfolders
+	recursiveCheck *widget.Check
+	// submitBtn triggers the import
+	submitBtn *widget.Button
+}
+
+// NewTrackImporterModule creates a new instance of TrackImporterModule.
+// It initializes the module with the provided window, configuration manager,
+// database manager, and error handler, sets up the UI components, and loads
+// any saved configuration.
+//
+// Parameters:
+//   - window: The main application window
+//   - configMgr: Configuration manager for saving/loading module settings
+//   - dbMgr: Database manager for accessing the DJ database
+//   - errorHandler: Error handler for displaying and logging errors
+//
+// Returns:
+//   - A fully initialized TrackImporterModule instance
+func NewTrackImporterModule(window fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) *TrackImporterModule {
+	m := &TrackImporterModule{
+		ModuleBase: common.NewModuleBase(window, configMgr, errorHandler),
+		dbMgr:      dbMgr,
+	}
+
+	m.initializeUI()
+
+	// Load typed configuration
+	m.LoadCfg()
+
+	return m
+}
+
+// GetName returns the localized name of this module.
+// This implements the Module interface method.
+func (m *TrackImporterModule) GetName() string {
+	return locales.Translate("trackimporter.mod.name")
+}
+
+// GetConfigName returns the configuration key for this module.
+// This key is used to store and retrieve module-specific configuration.
+func (m *TrackImporterModule) GetConfigName() string {
+	return common.ModuleKeyTrackImporter
+}
+
+// GetIcon returns the module's icon resource.
+// This implements the Module interface method and provides the visual representation
+// of this module in the UI.
+func (m *TrackImporterModule) GetIcon() fyne.Resource {
+	return theme.DownloadIcon()
+}
+
+// GetModuleContent returns the module's specific content without status messages.
+// This implements the method from ModuleBase to provide the module-specific UI
+// containing the folder selection field, recursive checkbox, and submit button.
+func (m *TrackImporterModule) GetModuleContent() fyne.CanvasObject {
+	form := &widget.Form{
+		Items: []*widget.FormItem{
+			{Text: locales.Translate("trackimporter.label.source"), Widget: m.folderSelectionField},
+		},
+	}
+
+	// Create module content with description and separator
+	moduleContent := container.NewVBox(
+		common.CreateDescriptionLabel(locales.Translate("trackimporter.label.info")),
+		widget.NewSeparator(),
+		form,
+		m.recursiveCheck,
+	)
+
+	// Add submit button with right alignment
+	buttonBox := container.New(layout.NewHBoxLayout(), layout.NewSpacer(), m.submitBtn)
+	moduleContent.Add(buttonBox)
+
+	return moduleContent
+}
+
+// GetContent returns the module's main UI content.
+// Like FlacFixer, the module needs no database connection until the backup is created.
+func (m *TrackImporterModule) GetContent() fyne.CanvasObject {
+	// Create the complete module layout with status messages container
+	return m.CreateModuleLayoutWithStatusMessages(m.GetModuleContent())
+}
+
+// LoadCfg loads typed configuration and updates UI elements
+func (m *TrackImporterModule) LoadCfg() {
+	m.IsLoadingConfig = true
+	defer func() { m.IsLoadingConfig = false }()
+
+	// Load typed config from ConfigManager
+	config, err := m.ConfigMgr.GetModuleCfg(common.ModuleKeyTrackImporter, m.GetConfigName())
+	if err != nil {
+		return
+	}
+
+	// Cast to TrackImporter specific config
+	if cfg, ok := config.(common.TrackImporterCfg); ok {
+		m.sourceFolderEntry.SetText(cfg.SourceFolder.Value)
+		m.recursiveCheck.SetChecked(cfg.Recursive.Value == "true")
+	}
+}
+
+// SaveCfg saves current UI state to typed configuration
+func (m *TrackImporterModule) SaveCfg() {
+	if m.IsLoadingConfig {
+		return // Safeguard: no save if config is being loaded
+	}
+
+	// Get default configuration with all field definitions
+	cfg := common.GetDefaultTrackImporterCfg()
+
+	// Update only the values from current UI state
+	cfg.SourceFolder.Value = common.NormalizePath(m.sourceFolderEntry.Text)
+	cfg.Recursive.Value = fmt.Sprintf("%t", m.recursiveCheck.Checked)
+
+	// Save typed config via ConfigManager
+	m.ConfigMgr.SaveModuleCfg(common.ModuleKeyTrackImporter, m.GetConfigName(), cfg)
+}
+
+// initializeUI sets up the user interface components.
+func (m *TrackImporterModule) initializeUI() {
+	m.folderSelectionField = common.CreateFolderSelectionField(
+		locales.Translate("common.entry.placeholderpath"),
+		nil,
+		m.CreateChangeHandler(func() {
+			m.SaveCfg()
+		}),
+	)
+	// Extract the entry widget from the container for direct access
+	if container, ok := m.folderSelectionField.(*fyne.Container); ok && len(container.Objects) > 0 {
+		if entry, ok := container.Objects[0].(*widget.Entry); ok {
+			m.sourceFolderEntry = entry
+		}
+	}
+
+	m.recursiveCheck = common.CreateCheckbox(locales.Translate("trackimporter.chkbox.recursive"), func(checked bool) {
+		m.SaveCfg()
+	})
+
+	m.submitBtn = common.CreateSubmitButton(locales.Translate("trackimporter.button.start"), func() {
+		go m.Start()
+	})
+}
+
+// Start performs the necessary steps before starting the main process.
+// It validates the inputs, which includes creating a database backup,
+// displays a progress dialog and starts the import in a goroutine.
+func (m *TrackImporterModule) Start() {
+	// Create and run validator
+	validator := common.NewValidator(m, m.ConfigMgr, m.dbMgr, m.ErrorHandler)
+	if err := validator.Validate(common.ValidatorActionStart); err != nil {
+		return
+	}
+
+	sourcePath := common.NormalizePath(m.sourceFolderEntry.Text)
+
+	// Prepare cancelable context and show progress dialog with cancel support
+	ctx, cancel := context.WithCancel(context.Background())
+	m.ShowProgressDialog(
+		locales.Translate("trackimporter.dialog.header"),
+		func() {
+			cancel()
+		},
+	)
+
+	// Start processing in a goroutine
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.CloseProgressDialog()
+				context := &common.ErrorContext{
+					Module:      m.GetName(),
+					Operation:   "Track Import",
+					Severity:    common.SeverityCritical,
+					Recoverable: false,
+				}
+				m.ErrorHandler.ShowStandardError(fmt.Errorf("%v", r), context)
+				m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+			}
+		}()
+
+		m.processImport(ctx, sourcePath)
+	}()
+}
+
+// processImport prepares the new djmdContent rows, shows them for review and writes them on approval.
+//
+// Parameters:
+//   - ctx: The context for cancellation
+//   - sourcePath: The folder with the files to import
+func (m *TrackImporterModule) processImport(ctx context.Context, sourcePath string) {
+	defer m.dbMgr.Finalize()
+
+	// Collect all changes first, nothing is written before the user approves them
+	cs := common.NewChangeset(m.dbMgr)
+
+	summary, err := common.ImportFolder(
+		ctx,
+		cs,
+		sourcePath,
+		m.recursiveCheck.Checked,
+		func(total int) {
+			m.AddInfoMessage(fmt.Sprintf(locales.Translate("common.status.filesfound"), total))
+		},
+		func(progress float64, imported int, total int) {
+			m.UpdateProgressStatus(progress, fmt.Sprintf(locales.Translate("trackimporter.status.progress"), imported, total))
+		},
+	)
+
+	if err != nil {
+		if errors.Is(err, common.ErrCancelled) {
+			m.HandleProcessCancellation("trackimporter.status.stopped")
+			common.UpdateButtonToCompleted(m.submitBtn)
+			return
+		}
+		m.CloseProgressDialog()
+		context := &common.ErrorContext{
+			Module:      m.GetName(),
+			Operation:   "Track Import",
+			Severity:    common.SeverityCritical,
+			Recoverable: false,
+		}
+		m.ErrorHandler.ShowStandardError(err, context)
+		m.AddErrorMessage(locales.Translate("common.err.statusfinal"))
+		return
+	}
+
+	// Let the user review the changes and write them on approval
+	m.ReviewAndApplyChanges(m.GetName(), locales.Translate("trackimporter.dialog.header"), cs, func(applied int) {
+		finalMsg := fmt.Sprintf(
+			locales.Translate("trackimporter.status.summary"),
+			summary.Total,
+			summary.Imported,
+			summary.Existing,
+			summary.SkippedZero,
+			summary.ProbeErrs,
+			summary.SkippedDirs,
+		)
+		m.AddInfoMessage(finalMsg)
+		m.CompleteProcessing(finalMsg)
+
+		// Mark the progress dialog as completed and update button
+		m.CompleteProgressDialog()
+		common.UpdateButtonToCompleted(m.submitBtn)
+	})
+}
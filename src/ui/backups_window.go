@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+	"errors"
+	"fmt"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowBackupsWindow creates and displays the database backups window.
+// It lists existing backups and allows the user to verify, prune and restore them.
+func ShowBackupsWindow(parent fyne.Window, configMgr *common.ConfigManager, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) {
+	if dbMgr == nil || configMgr == nil {
+		context := &common.ErrorContext{
+			Module:      "Backups",
+			Operation:   "Open Backups",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		errorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		return
+	}
+
+	// Always work with the current policy from the settings
+	dbMgr.SetBackupSettings(configMgr.GetBackupSettings())
+	backupMgr := dbMgr.BackupManager()
+
+	var backups []common.BackupInfo
+	verifyResults := make(map[string]string) // backup path -> localized verification result
+	var resultsMutex sync.Mutex
+	selected := -1
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	headers := []string{
+		locales.Translate("backups.col.created"),
+		locales.Translate("backups.col.kind"),
+		locales.Translate("backups.col.size"),
+		locales.Translate("backups.col.compressed"),
+		locales.Translate("backups.col.status"),
+	}
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(backups), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, object fyne.CanvasObject) {
+			backup := backups[id.Row]
+			text := ""
+			switch id.Col {
+			case 0:
+				text = backup.Created.Format("2006-01-02 15:04:05")
+			case 1:
+				if backup.Safety {
+					text = locales.Translate("backups.value.safety")
+				} else {
+					text = locales.Translate("backups.value.backup")
+				}
+			case 2:
+				text = fmt.Sprintf("%.1f MB", float64(backup.Size)/(1024*1024))
+			case 3:
+				if backup.Compressed {
+					text = locales.Translate("backups.value.yes")
+				} else {
+					text = locales.Translate("backups.value.no")
+				}
+			case 4:
+				resultsMutex.Lock()
+				text = verifyResults[backup.Path]
+				resultsMutex.Unlock()
+				if text == "" {
+					text = locales.Translate("backups.status.unchecked")
+				}
+			}
+			object.(*widget.Label).SetText(text)
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.TextStyle = fyne.TextStyle{Bold: true}
+		return label
+	}
+	table.UpdateHeader = func(id widget.TableCellID, object fyne.CanvasObject) {
+		if id.Col >= 0 {
+			object.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	for col, width := range []float32{200, 130, 110, 110, 320} {
+		table.SetColumnWidth(col, width)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		selected = id.Row
+	}
+
+	showError := func(err error, operation string) {
+		context := &common.ErrorContext{
+			Module:      "Backups",
+			Operation:   operation,
+			Severity:    common.SeverityError,
+			Recoverable: true,
+		}
+		errorHandler.ShowStandardError(err, context)
+	}
+
+	reload := func() {
+		list, err := backupMgr.List()
+		if err != nil {
+			showError(err, "List Backups")
+			return
+		}
+		backups = list
+		selected = -1
+		table.UnselectAll()
+		table.Refresh()
+		statusLabel.SetText(fmt.Sprintf(locales.Translate("backups.status.count"), len(backups)))
+	}
+
+	// verify checks the given backups in the background and shows the result per backup
+	var buttons []fyne.Disableable
+	verify := func(toCheck []common.BackupInfo) {
+		for _, b := range buttons {
+			b.Disable()
+		}
+		go func() {
+			failed := 0
+			for i, backup := range toCheck {
+				statusLabel.SetText(fmt.Sprintf(locales.Translate("backups.status.verifying"), i+1, len(toCheck), backup.Name))
+				result := locales.Translate("backups.status.ok")
+				if err := backupMgr.Verify(backup); err != nil {
+					result = err.Error()
+					failed++
+				}
+				resultsMutex.Lock()
+				verifyResults[backup.Path] = result
+				resultsMutex.Unlock()
+				table.Refresh()
+			}
+			statusLabel.SetText(fmt.Sprintf(locales.Translate("backups.status.verified"), len(toCheck), failed))
+			for _, b := range buttons {
+				b.Enable()
+			}
+		}()
+	}
+
+	selectedBackup := func() (common.BackupInfo, bool) {
+		if selected < 0 || selected >= len(backups) {
+			statusLabel.SetText(locales.Translate("backups.status.noselection"))
+			return common.BackupInfo{}, false
+		}
+		return backups[selected], true
+	}
+
+	refreshButton := widget.NewButtonWithIcon(locales.Translate("backups.button.refresh"), theme.ViewRefreshIcon(), reload)
+
+	verifyButton := widget.NewButtonWithIcon(locales.Translate("backups.button.verify"), theme.SearchIcon(), func() {
+		if backup, ok := selectedBackup(); ok {
+			verify([]common.BackupInfo{backup})
+		}
+	})
+
+	verifyAllButton := widget.NewButtonWithIcon(locales.Translate("backups.button.verifyall"), theme.SearchIcon(), func() {
+		if len(backups) > 0 {
+			verify(append([]common.BackupInfo(nil), backups...))
+		}
+	})
+
+	pruneButton := widget.NewButtonWithIcon(locales.Translate("backups.button.prune"), theme.DeleteIcon(), func() {
+		removed, err := backupMgr.Prune()
+		if err != nil {
+			showError(err, "Prune Backups")
+		}
+		reload()
+		statusLabel.SetText(fmt.Sprintf(locales.Translate("backups.status.pruned"), len(removed)))
+	})
+
+	restoreButton := widget.NewButtonWithIcon(locales.Translate("backups.button.restore"), theme.HistoryIcon(), func() {
+		backup, ok := selectedBackup()
+		if !ok {
+			return
+		}
+		// Never replace the database under a running write session
+		if dbMgr.InSession() {
+			showError(errors.New(locales.Translate("backups.err.busy")), "Restore Backup")
+			return
+		}
+		dialog.ShowConfirm(
+			locales.Translate("backups.dlg.restoretitle"),
+			fmt.Sprintf(locales.Translate("backups.dlg.restoremsg"), backup.Created.Format("2006-01-02 15:04:05")),
+			func(confirmed bool) {
+				if !confirmed {
+					return
+				}
+				// rekordbox keeps the database open, replacing it underneath would lose the restore or its changes
+				if err := dbMgr.CheckNotInUse(common.PreflightLockTimeout, common.DefaultProcessLister); err != nil {
+					showError(err, "Restore Backup")
+					return
+				}
+				statusLabel.SetText(locales.Translate("backups.status.restoring"))
+				safetyPath, err := dbMgr.RestoreBackup(backup)
+				if err != nil {
+					statusLabel.SetText("")
+					showError(err, "Restore Backup")
+					return
+				}
+				reload()
+				statusLabel.SetText(fmt.Sprintf(locales.Translate("backups.status.restored"), safetyPath))
+			},
+			parent,
+		)
+	})
+	restoreButton.Importance = widget.HighImportance
+
+	buttons = []fyne.Disableable{refreshButton, verifyButton, verifyAllButton, pruneButton, restoreButton}
+
+	locationLabel := widget.NewLabel(fmt.Sprintf(locales.Translate("backups.label.location"), backupMgr.Dir()))
+	locationLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		locationLabel,
+		container.NewVBox(
+			statusLabel,
+			container.NewHBox(refreshButton, verifyButton, verifyAllButton, pruneButton, layout.NewSpacer(), restoreButton),
+		),
+		nil,
+		nil,
+		table,
+	)
+
+	backupsDialog := dialog.NewCustom(
+		locales.Translate("backups.win.title"),
+		"", // Clear text for default button
+		content,
+		parent,
+	)
+
+	closeButton := widget.NewButton(locales.Translate("common.button.close"), func() {
+		backupsDialog.Hide()
+	})
+	closeButton.Importance = widget.DangerImportance
+	backupsDialog.SetButtons([]fyne.CanvasObject{closeButton})
+
+	backupsDialog.Resize(fyne.NewSize(850, 550))
+	reload()
+	backupsDialog.Show()
+}
@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"MetaRekordFixer/common"
+	"MetaRekordFixer/locales"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowHistoryWindow creates and displays the change history window.
+// It lists the runs recorded in the change journal and allows the user to revert a single run
+// by writing back the values it has overwritten. The revert is reviewed like any other change.
+func ShowHistoryWindow(parent fyne.Window, configMgr *common.ConfigManager, journal *common.ChangeJournal, dbMgr *common.DBManager, errorHandler *common.ErrorHandler) {
+	if dbMgr == nil || journal == nil || configMgr == nil {
+		context := &common.ErrorContext{
+			Module:      "History",
+			Operation:   "Open History",
+			Severity:    common.SeverityWarning,
+			Recoverable: true,
+		}
+		errorHandler.ShowStandardError(errors.New(locales.Translate("common.err.dbpath")), context)
+		return
+	}
+
+	var runs []common.JournalRun
+	selected := -1
+
+	statusLabel := widget.NewLabel("")
+	statusLabel.Wrapping = fyne.TextWrapWord
+
+	headers := []string{
+		locales.Translate("history.col.run"),
+		locales.Translate("history.col.created"),
+		locales.Translate("history.col.module"),
+		locales.Translate("history.col.database"),
+		locales.Translate("history.col.rows"),
+		locales.Translate("history.col.status"),
+	}
+
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(runs), len(headers)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, object fyne.CanvasObject) {
+			run := runs[id.Row]
+			text := ""
+			switch id.Col {
+			case 0:
+				text = fmt.Sprintf("%d", run.ID)
+			case 1:
+				text = run.Created.Format("2006-01-02 15:04:05")
+			case 2:
+				text = run.Module
+			case 3:
+				text = filepath.Base(run.DBPath)
+			case 4:
+				text = fmt.Sprintf("%d", run.Rows)
+			case 5:
+				if run.RevertedBy != 0 {
+					text = fmt.Sprintf(locales.Translate("history.status.revertedby"), run.RevertedBy)
+				} else if run.RevertOf != 0 {
+					text = fmt.Sprintf(locales.Translate("history.status.revertof"), run.RevertOf)
+				}
+			}
+			object.(*widget.Label).SetText(text)
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		label := widget.NewLabel("")
+		label.TextStyle = fyne.TextStyle{Bold: true}
+		return label
+	}
+	table.UpdateHeader = func(id widget.TableCellID, object fyne.CanvasObject) {
+		if id.Col >= 0 {
+			object.(*widget.Label).SetText(headers[id.Col])
+		}
+	}
+	for col, width := range []float32{60, 170, 170, 170, 80, 160} {
+		table.SetColumnWidth(col, width)
+	}
+	table.OnSelected = func(id widget.TableCellID) {
+		selected = id.Row
+	}
+
+	showError := func(err error, operation string) {
+		context := &common.ErrorContext{
+			Module:      "History",
+			Operation:   operation,
+			Severity:    common.SeverityError,
+			Recoverable: true,
+		}
+		errorHandler.ShowStandardError(err, context)
+	}
+
+	reload := func() {
+		list, err := journal.Runs()
+		if err != nil {
+			showError(err, "List Runs")
+			return
+		}
+		runs = list
+		selected = -1
+		table.UnselectAll()
+		table.Refresh()
+		statusLabel.SetText(fmt.Sprintf(locales.Translate("history.status.count"), len(runs)))
+	}
+
+	var buttons []fyne.Disableable
+	setButtonsEnabled := func(enabled bool) {
+		for _, b := range buttons {
+			if enabled {
+				b.Enable()
+			} else {
+				b.Disable()
+			}
+		}
+	}
+
+	// apply checks that rekordbox does not hold the database, backs it up and writes the reverting changes
+	apply := func(cs *common.Changeset, conflicts int) {
+		go func() {
+			defer dbMgr.Finalize()
+
+			if err := dbMgr.CheckNotInUse(common.PreflightLockTimeout, common.DefaultProcessLister); err != nil {
+				showError(err, "Revert Run")
+				setButtonsEnabled(true)
+				statusLabel.SetText("")
+				return
+			}
+
+			statusLabel.SetText(locales.Translate("history.status.backup"))
+			dbMgr.SetBackupSettings(configMgr.GetBackupSettings())
+			if err := dbMgr.BackupDatabase(); err != nil {
+				showError(err, "Revert Run")
+				setButtonsEnabled(true)
+				return
+			}
+			dbMgr.EnableWrites()
+
+			applied, err := cs.Apply(nil, func(applied, total int) {
+				statusLabel.SetText(fmt.Sprintf(locales.Translate("history.status.applying"), applied, total))
+			})
+			setButtonsEnabled(true)
+			if err != nil {
+				showError(err, "Revert Run")
+				statusLabel.SetText(locales.Translate("common.status.rolledback"))
+				return
+			}
+			reload()
+			statusLabel.SetText(fmt.Sprintf(locales.Translate("history.status.reverted"), applied, conflicts))
+		}()
+	}
+
+	refreshButton := widget.NewButtonWithIcon(locales.Translate("history.button.refresh"), theme.ViewRefreshIcon(), reload)
+
+	revertButton := widget.NewButtonWithIcon(locales.Translate("history.button.revert"), theme.ContentUndoIcon(), func() {
+		if selected < 0 || selected >= len(runs) {
+			statusLabel.SetText(locales.Translate("history.status.noselection"))
+			return
+		}
+		run := runs[selected]
+		// Never write under a running write session of a module
+		if dbMgr.InSession() {
+			showError(errors.New(locales.Translate("backups.err.busy")), "Revert Run")
+			return
+		}
+
+		setButtonsEnabled(false)
+		statusLabel.SetText(fmt.Sprintf(locales.Translate("history.status.preparing"), run.ID))
+
+		go func() {
+			cs := common.NewChangeset(dbMgr)
+			cs.SetSource(fmt.Sprintf(locales.Translate("history.value.source"), run.Module))
+			conflicts, err := common.PrepareRevert(cs, journal, run)
+			if err == nil && cs.Len() > 0 {
+				// Stamp before the review, so the preview shows the USN and timestamps that will be written
+				err = cs.Stamp()
+			}
+			if err != nil {
+				dbMgr.Finalize()
+				setButtonsEnabled(true)
+				statusLabel.SetText("")
+				showError(err, "Revert Run")
+				return
+			}
+			if cs.Len() == 0 {
+				dbMgr.Finalize()
+				setButtonsEnabled(true)
+				statusLabel.SetText(locales.Translate("history.status.nothing"))
+				return
+			}
+
+			message := fmt.Sprintf(locales.Translate("common.status.changesprepared"), cs.Len())
+			if conflicts > 0 {
+				message += " " + fmt.Sprintf(locales.Translate("history.status.conflicts"), conflicts)
+			}
+			statusLabel.SetText(message)
+
+			common.ShowChangesetReviewDialog(parent, cs,
+				func() {
+					apply(cs, conflicts)
+				},
+				func() {
+					dbMgr.Finalize()
+					setButtonsEnabled(true)
+					statusLabel.SetText(locales.Translate("common.status.changesdiscarded"))
+				},
+			)
+		}()
+	})
+	revertButton.Importance = widget.HighImportance
+
+	buttons = []fyne.Disableable{refreshButton, revertButton}
+
+	infoLabel := widget.NewLabel(fmt.Sprintf(locales.Translate("history.label.info"), journal.Path()))
+	infoLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		infoLabel,
+		container.NewVBox(
+			statusLabel,
+			container.NewHBox(refreshButton, layout.NewSpacer(), revertButton),
+		),
+		nil,
+		nil,
+		table,
+	)
+
+	historyDialog := dialog.NewCustom(
+		locales.Translate("history.win.title"),
+		"", // Clear text for default button
+		content,
+		parent,
+	)
+
+	closeButton := widget.NewButton(locales.Translate("common.button.close"), func() {
+		historyDialog.Hide()
+	})
+	closeButton.Importance = widget.DangerImportance
+	historyDialog.SetButtons([]fyne.CanvasObject{closeButton})
+
+	historyDialog.Resize(fyne.NewSize(900, 550))
+	reload()
+	historyDialog.Show()
+}
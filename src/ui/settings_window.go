@@ -5,6 +5,7 @@ import (
 	"MetaRekordFixer/locales"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"fyne.io/fyne/v2"
@@ -94,12 +95,67 @@ func ShowSettingsWindow(parent fyne.Window, configMgr *common.ConfigManager, err
 		}
 	}
 
+	// Backup policy setup
+	markUnsaved := func() {
+		if saveButton != nil {
+			saveButton.SetIcon(nil)
+			saveButton.SetText(locales.Translate("settings.write.settings"))
+		}
+	}
+
+	backupDirEntry := widget.NewEntry()
+	backupDirEntry.SetText(config.BackupDir)
+	backupDirContainer := common.CreateFolderSelectionField(locales.Translate("settings.backup.dirtitle"), backupDirEntry, func(string) {
+		markUnsaved()
+	})
+
+	keepCountEntry := widget.NewEntry()
+	keepCountEntry.SetPlaceHolder(locales.Translate("settings.backup.unlimited"))
+	if config.BackupKeepCount > 0 {
+		keepCountEntry.SetText(strconv.Itoa(config.BackupKeepCount))
+	}
+	keepCountEntry.OnChanged = func(string) {
+		markUnsaved()
+	}
+
+	keepDaysEntry := widget.NewEntry()
+	keepDaysEntry.SetPlaceHolder(locales.Translate("settings.backup.unlimited"))
+	if config.BackupKeepDays > 0 {
+		keepDaysEntry.SetText(strconv.Itoa(config.BackupKeepDays))
+	}
+	keepDaysEntry.OnChanged = func(string) {
+		markUnsaved()
+	}
+
+	compressCheck := common.CreateCheckbox(locales.Translate("settings.backup.compress"), func(bool) {
+		markUnsaved()
+	})
+	compressCheck.SetChecked(config.BackupCompress)
+
 	// Create save button using abstraction
 	saveButton = common.CreateActionButton(
 		locales.Translate("settings.write.settings"),
 		func() {
+			// Validate retention limits, empty means unlimited
+			keepCount, countErr := parseRetentionLimit(keepCountEntry.Text)
+			keepDays, daysErr := parseRetentionLimit(keepDaysEntry.Text)
+			if countErr != nil || daysErr != nil {
+				context := &common.ErrorContext{
+					Module:      "Settings",
+					Operation:   "Backup Settings Validation",
+					Severity:    common.SeverityWarning,
+					Recoverable: true,
+				}
+				errorHandler.ShowStandardError(errors.New(locales.Translate("settings.err.backuplimit")), context)
+				return
+			}
+
 			// Update and save config
 			config.DatabasePath = dbPathEntry.Text
+			config.BackupDir = backupDirEntry.Text
+			config.BackupKeepCount = keepCount
+			config.BackupKeepDays = keepDays
+			config.BackupCompress = compressCheck.Checked
 
 			// Find selected language code
 			for _, lang := range langItems {
@@ -146,6 +202,14 @@ func ShowSettingsWindow(parent fyne.Window, configMgr *common.ConfigManager, err
 			widget.NewFormItem(locales.Translate("settings.rbxdb.loc"), container.NewBorder(nil, nil, nil, detectButton, dbPathContainer)),
 			widget.NewFormItem(locales.Translate("settings.lang.sel"), languageSelect),
 		),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle(locales.Translate("settings.backup.header"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		widget.NewForm(
+			widget.NewFormItem(locales.Translate("settings.backup.dir"), backupDirContainer),
+			widget.NewFormItem(locales.Translate("settings.backup.keepcount"), keepCountEntry),
+			widget.NewFormItem(locales.Translate("settings.backup.keepdays"), keepDaysEntry),
+			widget.NewFormItem("", compressCheck),
+		),
 		container.NewHBox(layout.NewSpacer(), saveButton),
 	)
 
@@ -167,8 +231,22 @@ func ShowSettingsWindow(parent fyne.Window, configMgr *common.ConfigManager, err
 	settingsDialog.SetButtons([]fyne.CanvasObject{closeButton})
 
 	// Set dialog size
-	settingsDialog.Resize(fyne.NewSize(800, 400))
+	settingsDialog.Resize(fyne.NewSize(800, 550))
 
 	// Show dialog as modal
 	settingsDialog.Show()
 }
+
+// parseRetentionLimit parses a backup retention limit entered in the settings window.
+// An empty value means no limit and is returned as 0.
+func parseRetentionLimit(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid retention limit: %q", text)
+	}
+	return value, nil
+}